@@ -18,19 +18,37 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/durudex/durudex-post-service/internal/auth"
 	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/metrics"
+	"github.com/durudex/durudex-post-service/internal/publisher"
 	"github.com/durudex/durudex-post-service/internal/repository"
 	"github.com/durudex/durudex-post-service/internal/service"
+	"github.com/durudex/durudex-post-service/internal/tracing"
+	"github.com/durudex/durudex-post-service/internal/transport/connect"
 	"github.com/durudex/durudex-post-service/internal/transport/grpc"
+	"github.com/durudex/durudex-post-service/internal/transport/http"
+	"github.com/durudex/durudex-post-service/pkg/lifecycle"
+	"github.com/durudex/durudex-post-service/pkg/tls"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
+var (
+	// Apply pending database migrations and exit.
+	migrateOnly = flag.Bool("migrate-only", false, "apply pending database migrations and exit")
+	// Apply pending database migrations including contract migrations and exit.
+	migrateContract = flag.Bool("migrate-contract", false,
+		"apply pending database migrations including contract migrations and exit")
+)
+
 // Initialize application.
 func init() {
 	// Set logger mode.
@@ -43,32 +61,167 @@ func init() {
 
 // A function that running the application.
 func main() {
+	flag.Parse()
+
 	// Initialize config.
 	cfg, err := config.Init()
 	if err != nil {
 		log.Error().Err(err).Msg("error initialize config")
 	}
 
+	// Creating a new tracer provider.
+	tp, err := tracing.NewProvider(cfg.Tracing)
+	if err != nil {
+		log.Fatal().Err(err).Msg("error creating tracer provider")
+	}
+
 	// Creating a new repository.
 	repos := repository.NewRepository(cfg.Database)
+
+	// Applying pending database migrations.
+	if cfg.Database.Postgres.Migrate || *migrateOnly || *migrateContract {
+		if err := repos.Postgres.Migrator.Up(context.Background(), *migrateContract); err != nil {
+			log.Fatal().Err(err).Msg("error migrating database schema")
+		}
+	}
+
+	// Exiting after migrating database schema.
+	if *migrateOnly || *migrateContract {
+		log.Info().Msg("Database schema is up to date")
+
+		repos.Close()
+
+		if err := tp.Shutdown(context.Background()); err != nil {
+			log.Error().Err(err).Msg("error shutting down tracer provider")
+		}
+
+		return
+	}
+
+	// Refusing to serve on an outdated database schema.
+	if err := repos.Postgres.Migrator.Check(context.Background()); err != nil {
+		log.Fatal().Err(err).Msg("database schema is not up to date")
+	}
+
+	// Creating a new access token authenticator.
+	var authenticator *auth.Authenticator
+
+	if cfg.Auth.Enable {
+		authenticator, err = auth.NewAuthenticator(cfg.Auth)
+		if err != nil {
+			log.Fatal().Err(err).Msg("error creating access token authenticator")
+		}
+	} else if cfg.Post.RequireAuth {
+		log.Fatal().Msg("post authentication is required but access token authentication is disabled")
+	}
+
+	// Creating a new post event publisher.
+	pub, err := publisher.NewPublisher(cfg.Publisher)
+	if err != nil {
+		log.Fatal().Err(err).Msg("error creating post event publisher")
+	}
+
+	// Registering database pool metrics.
+	metrics.Registry.MustRegister(metrics.NewPoolCollector(repos.Postgres))
+
 	// Creating a new service.
-	service := service.NewService(repos)
+	service := service.NewService(repos, pub, cfg)
 	// Creating a new gRPC handler.
 	handler := grpc.NewHandler(service)
 
+	// Creating a new lifecycle manager.
+	lc := lifecycle.NewManager(cfg.GRPC.ShutdownTimeout)
+
+	// Running post events dispatcher.
+	lc.Go(service.Event.Run)
+	// Running outbox relay worker.
+	lc.Go(service.Outbox.Run)
+	// Running author stats reconciliation worker.
+	lc.Go(service.Stats.Run)
+	// Running post fingerprints backfill worker.
+	lc.Go(service.Fingerprint.Run)
+	// Running webhook delivery worker.
+	lc.Go(service.Webhook.Run)
+
+	// Reporting recovered handler panics to the request span.
+	reporter := grpc.PanicReporterFunc(tracing.ReportPanic)
+
+	opts := []grpc.Option{grpc.WithAuthenticator(authenticator), grpc.WithPanicReporter(reporter)}
+
+	// Loading gRPC server TLS certificates.
+	if cfg.GRPC.TLS.Enable {
+		certs, err := tls.NewReloader(cfg.GRPC.TLS.CACert, cfg.GRPC.TLS.Cert, cfg.GRPC.TLS.Key,
+			cfg.GRPC.TLS.ExpiryWarning)
+		if err != nil {
+			log.Fatal().Err(err).Msg("error loading TLS certificates")
+		}
+
+		// Registering certificate expiry metric.
+		metrics.Registry.MustRegister(metrics.NewCertificateExpiry(certs))
+
+		// Watching certificate files.
+		if cfg.GRPC.TLS.Reload {
+			lc.Go(certs.Watch)
+		}
+
+		opts = append(opts, grpc.WithCertificates(certs))
+	}
+
 	// Create a new server.
-	srv := grpc.NewServer(cfg.GRPC, handler)
+	srv := grpc.NewServer(cfg.GRPC, handler, opts...)
+
+	// Running health checker.
+	lc.Go(func(ctx context.Context) { service.Health.Run(ctx, srv.SetServing) })
 
 	// Run server.
 	go srv.Run()
 
+	// Create a new HTTP server.
+	httpSrv := http.NewServer(cfg.HTTP, http.NewHandler(service, cfg.HTTP.GraphQL, authenticator, reporter))
+
+	// Run HTTP server.
+	go httpSrv.Run()
+
+	// Create a new Connect server.
+	connectSrv := connect.NewServer(cfg.Connect, connect.NewHandler(service, authenticator, reporter))
+
+	// Run Connect server.
+	go connectSrv.Run()
+
+	// Create a new metrics server.
+	metricsSrv := metrics.NewServer(cfg.Metrics)
+
+	// Run metrics server.
+	go metricsSrv.Run()
+
+	// Stopping in order: marking health as not serving, ending post event
+	// streams, draining the servers at the same time, stopping background
+	// workers, closing the publisher and closing the database pool last.
+	lc.OnStop("gRPC health", srv.StopServing)
+	lc.OnStop("post event streams", service.Event.Stop)
+	lc.OnStopConcurrent(
+		lifecycle.Hook{Name: "HTTP server", Stop: httpSrv.Stop},
+		lifecycle.Hook{Name: "Connect server", Stop: connectSrv.Stop},
+		lifecycle.Hook{Name: "gRPC server", Stop: srv.Stop},
+	)
+	lc.OnStop("background workers", lc.StopWorkers)
+	lc.OnStop("post event publisher", func(context.Context) error { return pub.Close() })
+	lc.OnStop("metrics server", metricsSrv.Stop)
+	lc.OnStop("tracer provider", tp.Shutdown)
+	lc.OnStop("repository", func(context.Context) error {
+		repos.Close()
+		return nil
+	})
+
 	// Quit in application.
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
 	<-quit
 
-	// Stopping server.
-	srv.Stop()
+	// Shutting down the application.
+	if err := lc.Shutdown(); err != nil {
+		log.Error().Err(err).Msg("error shutting down")
+	}
 
 	log.Info().Msg("Durudex Post Service stopping!")
 }
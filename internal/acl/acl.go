@@ -0,0 +1,102 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package acl
+
+import (
+	"crypto/x509"
+	"strings"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+)
+
+// Wildcard matching any identity or method.
+const wildcard = "*"
+
+// Access control list structure.
+//
+// Maps service identities to the RPC methods they are allowed to call.
+type ACL struct{ rules map[string][]string }
+
+// Creating a new access control list.
+func New(cfg config.ACLConfig) *ACL {
+	rules := make(map[string][]string, len(cfg.Rules))
+
+	for _, rule := range cfg.Rules {
+		rules[rule.Identity] = append(rules[rule.Identity], rule.Methods...)
+	}
+
+	return &ACL{rules: rules}
+}
+
+// Checking if any of the identities is allowed to call the full method name.
+// Rules of the wildcard identity apply to every identity.
+//
+// Rule methods are full method names such as "/durudex.v1.PostService/GetPost",
+// a service name followed by "/*" or a single wildcard.
+func (a *ACL) Allowed(identities []string, method string) bool {
+	for _, identity := range identities {
+		if a.allowed(identity, method) {
+			return true
+		}
+	}
+
+	return a.allowed(wildcard, method)
+}
+
+// Checking if the identity rule allows the full method name.
+func (a *ACL) allowed(identity, method string) bool {
+	for _, pattern := range a.rules[identity] {
+		if match(pattern, method) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Matching a method pattern against the full method name.
+func match(pattern, method string) bool {
+	if pattern == wildcard || pattern == method {
+		return true
+	}
+
+	// Check is service wildcard.
+	if service := strings.TrimSuffix(pattern, wildcard); service != pattern {
+		return strings.HasPrefix(method, service)
+	}
+
+	return false
+}
+
+// Getting service identities of the certificate. These are the SAN URIs and
+// DNS names followed by the subject common name.
+func Identities(cert *x509.Certificate) []string {
+	identities := make([]string, 0, len(cert.URIs)+len(cert.DNSNames)+1)
+
+	for _, uri := range cert.URIs {
+		identities = append(identities, uri.String())
+	}
+
+	identities = append(identities, cert.DNSNames...)
+
+	if cert.Subject.CommonName != "" {
+		identities = append(identities, cert.Subject.CommonName)
+	}
+
+	return identities
+}
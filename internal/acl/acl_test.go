@@ -0,0 +1,109 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package acl_test
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/internal/acl"
+	"github.com/durudex/durudex-post-service/internal/config"
+)
+
+// Testing checking allowed methods.
+func TestACL_Allowed(t *testing.T) {
+	// Creating a new access control list.
+	a := acl.New(config.ACLConfig{Rules: []config.ACLRule{
+		{Identity: "*", Methods: []string{"/grpc.health.v1.Health/*"}},
+		{Identity: "gateway.service.durudex.local", Methods: []string{"/durudex.v1.PostService/GetPost"}},
+		{Identity: "moderation.service.durudex.local", Methods: []string{"/durudex.v1.WebhookService/*"}},
+		{Identity: "admin.service.durudex.local", Methods: []string{"*"}},
+	}})
+
+	// Testing args.
+	type args struct {
+		identities []string
+		method     string
+	}
+
+	// Tests structures.
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "Method",
+			args: args{[]string{"gateway.service.durudex.local"}, "/durudex.v1.PostService/GetPost"},
+			want: true,
+		},
+		{
+			name: "Method denied",
+			args: args{[]string{"gateway.service.durudex.local"}, "/durudex.v1.PostService/DeletePost"},
+			want: false,
+		},
+		{
+			name: "Service",
+			args: args{[]string{"gateway", "moderation.service.durudex.local"}, "/durudex.v1.WebhookService/CreateWebhook"},
+			want: true,
+		},
+		{
+			name: "Service denied",
+			args: args{[]string{"gateway.service.durudex.local"}, "/durudex.v1.WebhookService/CreateWebhook"},
+			want: false,
+		},
+		{
+			name: "Wildcard identity",
+			args: args{[]string{"unknown"}, "/grpc.health.v1.Health/Check"},
+			want: true,
+		},
+		{
+			name: "Wildcard method",
+			args: args{[]string{"admin.service.durudex.local"}, "/durudex.v1.PostService/DeletePost"},
+			want: true,
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := a.Allowed(tt.args.identities, tt.args.method); got != tt.want {
+				t.Errorf("error allowed: got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+// Testing getting certificate identities.
+func TestIdentities(t *testing.T) {
+	cert := &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "moderation"},
+		DNSNames: []string{"moderation.service.durudex.local"},
+		URIs:     []*url.URL{{Scheme: "spiffe", Host: "durudex.com", Path: "/moderation"}},
+	}
+
+	want := []string{"spiffe://durudex.com/moderation", "moderation.service.durudex.local", "moderation"}
+
+	// Check for similarity of identities.
+	if got := acl.Identities(cert); !reflect.DeepEqual(got, want) {
+		t.Errorf("error identities are not similar: got %v, want %v", got, want)
+	}
+}
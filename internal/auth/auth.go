@@ -0,0 +1,170 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package auth
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/segmentio/ksuid"
+)
+
+// Errors returned by the authenticator.
+var (
+	ErrInvalidToken = errors.New("invalid access token")
+	ErrNoKeys       = errors.New("no access token keys configured")
+	ErrNoAudience   = errors.New("no access token audience configured")
+)
+
+// Authenticator structure.
+//
+// Validates access tokens signed with the HMAC secret or with one of the JWKS
+// file keys.
+type Authenticator struct {
+	config  config.AuthConfig
+	secret  []byte
+	keys    map[string]crypto.PublicKey
+	methods []string
+}
+
+// Creating a new authenticator. The audience is required, so that tokens
+// issued to other services are rejected.
+func NewAuthenticator(cfg config.AuthConfig) (*Authenticator, error) {
+	if cfg.Audience == "" {
+		return nil, ErrNoAudience
+	}
+
+	a := &Authenticator{config: cfg}
+
+	// Added HMAC secret.
+	if cfg.Secret != "" {
+		a.secret = []byte(cfg.Secret)
+		a.methods = append(a.methods, "HS256", "HS384", "HS512")
+	}
+
+	// Loading JWKS file keys.
+	if cfg.JWKSFile != "" {
+		keys, err := LoadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+
+		a.keys = keys
+		a.methods = append(a.methods, "RS256", "RS384", "RS512", "PS256", "PS384", "PS512",
+			"ES256", "ES384", "ES512")
+	}
+
+	if len(a.methods) == 0 {
+		return nil, ErrNoKeys
+	}
+
+	return a, nil
+}
+
+// Authenticating an access token and getting its subject.
+func (a *Authenticator) Authenticate(token string) (ksuid.KSUID, error) {
+	var claims jwt.RegisteredClaims
+
+	parser := jwt.NewParser(jwt.WithValidMethods(a.methods), jwt.WithoutClaimsValidation())
+
+	// Parsing and verifying token signature.
+	if _, err := parser.ParseWithClaims(token, &claims, a.key); err != nil {
+		return ksuid.Nil, fmt.Errorf("%w: %s", ErrInvalidToken, err.Error())
+	}
+
+	// Validating token claims.
+	if err := a.validate(&claims); err != nil {
+		return ksuid.Nil, fmt.Errorf("%w: %s", ErrInvalidToken, err.Error())
+	}
+
+	// Parsing token subject.
+	subject, err := ksuid.Parse(claims.Subject)
+	if err != nil {
+		return ksuid.Nil, fmt.Errorf("%w: invalid subject", ErrInvalidToken)
+	}
+
+	return subject, nil
+}
+
+// Getting token verification key.
+func (a *Authenticator) key(token *jwt.Token) (interface{}, error) {
+	// Check is HMAC signed token.
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		return a.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+
+	// Using the only key when token has no key id.
+	if kid == "" && len(a.keys) == 1 {
+		for _, key := range a.keys {
+			return key, nil
+		}
+	}
+
+	key, ok := a.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id: %q", kid)
+	}
+
+	return key, nil
+}
+
+// Validating token expiry, audience and issuer claims.
+func (a *Authenticator) validate(claims *jwt.RegisteredClaims) error {
+	now := time.Now()
+
+	// Check is token expired.
+	if claims.ExpiresAt == nil || now.After(claims.ExpiresAt.Add(a.config.Leeway)) {
+		return errors.New("token is expired")
+	}
+
+	// Check is token not valid yet.
+	if claims.NotBefore != nil && now.Add(a.config.Leeway).Before(claims.NotBefore.Time) {
+		return errors.New("token is not valid yet")
+	}
+
+	// Check token audience.
+	if !claims.VerifyAudience(a.config.Audience, true) {
+		return errors.New("invalid audience")
+	}
+
+	// Check token issuer.
+	if a.config.Issuer != "" && !claims.VerifyIssuer(a.config.Issuer, true) {
+		return errors.New("invalid issuer")
+	}
+
+	return nil
+}
+
+// Getting token from a bearer authorization value.
+func BearerToken(authorization string) (string, bool) {
+	const prefix = "bearer "
+
+	if len(authorization) <= len(prefix) || !strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return "", false
+	}
+
+	return strings.TrimSpace(authorization[len(prefix):]), true
+}
@@ -0,0 +1,247 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/auth"
+	"github.com/durudex/durudex-post-service/internal/config"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/segmentio/ksuid"
+)
+
+// Testing authenticator config.
+var testConfig = config.AuthConfig{
+	Audience: "durudex-post-service",
+	Issuer:   "https://auth.durudex.com",
+	Leeway:   time.Second,
+	Secret:   "secret",
+}
+
+// Getting testing token claims.
+func testClaims(subject ksuid.KSUID, expiresAt time.Time) jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		Subject:   subject.String(),
+		Audience:  jwt.ClaimStrings{testConfig.Audience},
+		Issuer:    testConfig.Issuer,
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+}
+
+// Signing a testing token.
+func sign(t *testing.T, method jwt.SigningMethod, claims jwt.Claims, key interface{}) string {
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("error signing token: %s", err.Error())
+	}
+
+	return token
+}
+
+// Testing creating a new authenticator.
+func TestNewAuthenticator(t *testing.T) {
+	// Tests structures.
+	tests := []struct {
+		name    string
+		cfg     config.AuthConfig
+		wantErr error
+	}{
+		{name: "OK", cfg: testConfig},
+		{name: "Without audience", cfg: config.AuthConfig{Secret: testConfig.Secret}, wantErr: auth.ErrNoAudience},
+		{name: "Without keys", cfg: config.AuthConfig{Audience: testConfig.Audience}, wantErr: auth.ErrNoKeys},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Creating a new authenticator.
+			if _, err := auth.NewAuthenticator(tt.cfg); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error creating authenticator: got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Testing authenticating HMAC signed access tokens.
+func TestAuthenticator_Authenticate(t *testing.T) {
+	subject := ksuid.New()
+
+	// Creating a new authenticator.
+	a, err := auth.NewAuthenticator(testConfig)
+	if err != nil {
+		t.Fatalf("error creating authenticator: %s", err.Error())
+	}
+
+	secret := []byte(testConfig.Secret)
+
+	// Tests structures.
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{
+			name:  "OK",
+			token: sign(t, jwt.SigningMethodHS256, testClaims(subject, time.Now().Add(time.Minute)), secret),
+		},
+		{
+			name:    "Expired",
+			token:   sign(t, jwt.SigningMethodHS256, testClaims(subject, time.Now().Add(-time.Minute)), secret),
+			wantErr: true,
+		},
+		{
+			name: "Without expiry",
+			token: sign(t, jwt.SigningMethodHS256, jwt.RegisteredClaims{
+				Subject:  subject.String(),
+				Audience: jwt.ClaimStrings{testConfig.Audience},
+				Issuer:   testConfig.Issuer,
+			}, secret),
+			wantErr: true,
+		},
+		{
+			name: "Wrong audience",
+			token: sign(t, jwt.SigningMethodHS256, jwt.RegisteredClaims{
+				Subject:   subject.String(),
+				Audience:  jwt.ClaimStrings{"durudex-user-service"},
+				Issuer:    testConfig.Issuer,
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			}, secret),
+			wantErr: true,
+		},
+		{
+			name: "Without audience",
+			token: sign(t, jwt.SigningMethodHS256, jwt.RegisteredClaims{
+				Subject:   subject.String(),
+				Issuer:    testConfig.Issuer,
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			}, secret),
+			wantErr: true,
+		},
+		{
+			name:    "Wrong secret",
+			token:   sign(t, jwt.SigningMethodHS256, testClaims(subject, time.Now().Add(time.Minute)), []byte("wrong")),
+			wantErr: true,
+		},
+		{
+			name: "None algorithm",
+			token: sign(t, jwt.SigningMethodNone, testClaims(subject, time.Now().Add(time.Minute)),
+				jwt.UnsafeAllowNoneSignatureType),
+			wantErr: true,
+		},
+		{
+			name: "Invalid subject",
+			token: sign(t, jwt.SigningMethodHS256, jwt.RegisteredClaims{
+				Subject:   "user",
+				Audience:  jwt.ClaimStrings{testConfig.Audience},
+				Issuer:    testConfig.Issuer,
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			}, secret),
+			wantErr: true,
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Authenticating access token.
+			got, err := a.Authenticate(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error authenticating token: %v", err)
+			}
+
+			// Check for similarity of subject.
+			if !tt.wantErr && got != subject {
+				t.Errorf("error subject are not similar: got %s, want %s", got, subject)
+			}
+		})
+	}
+}
+
+// Testing authenticating access tokens signed with JWKS file keys.
+func TestAuthenticator_AuthenticateJWKS(t *testing.T) {
+	subject := ksuid.New()
+
+	// Generating a new RSA key.
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating key: %s", err.Error())
+	}
+
+	// Writing a JWKS file.
+	data, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kid": "test",
+			"kty": "RSA",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	})
+	if err != nil {
+		t.Fatalf("error encoding JWKS: %s", err.Error())
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("error writing JWKS file: %s", err.Error())
+	}
+
+	// Creating a new authenticator.
+	a, err := auth.NewAuthenticator(config.AuthConfig{Audience: testConfig.Audience, JWKSFile: path})
+	if err != nil {
+		t.Fatalf("error creating authenticator: %s", err.Error())
+	}
+
+	// Signing a token with key id.
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, testClaims(subject, time.Now().Add(time.Minute)))
+	token.Header["kid"] = "test"
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("error signing token: %s", err.Error())
+	}
+
+	// Authenticating access token.
+	got, err := a.Authenticate(signed)
+	if err != nil {
+		t.Fatalf("error authenticating token: %s", err.Error())
+	}
+
+	// Check for similarity of subject.
+	if got != subject {
+		t.Errorf("error subject are not similar: got %s, want %s", got, subject)
+	}
+
+	// Check HMAC tokens are rejected without secret.
+	if _, err := a.Authenticate(sign(t, jwt.SigningMethodHS256,
+		testClaims(subject, time.Now().Add(time.Minute)), []byte("secret"))); err == nil {
+		t.Error("error authenticating HMAC token without secret")
+	}
+}
@@ -0,0 +1,38 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package auth
+
+import (
+	"context"
+
+	"github.com/segmentio/ksuid"
+)
+
+// Subject context key.
+type subjectKey struct{}
+
+// Adding authenticated subject to the context.
+func NewContext(ctx context.Context, subject ksuid.KSUID) context.Context {
+	return context.WithValue(ctx, subjectKey{}, subject)
+}
+
+// Getting authenticated subject from the context.
+func SubjectFromContext(ctx context.Context) (ksuid.KSUID, bool) {
+	subject, ok := ctx.Value(subjectKey{}).(ksuid.KSUID)
+	return subject, ok
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package auth
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+)
+
+// HTTP authentication middleware.
+//
+// A valid bearer token adds its subject to the request context, an invalid
+// token is rejected and requests without a token are passed on.
+func Middleware(a *Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization := r.Header.Get("Authorization")
+			if authorization == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, ok := BearerToken(authorization)
+			if !ok {
+				unauthorized(w)
+				return
+			}
+
+			// Authenticating access token.
+			subject, err := a.Authenticate(token)
+			if err != nil {
+				log.Debug().Err(err).Msg("error authenticating request")
+				unauthorized(w)
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), subject)))
+		})
+	}
+}
+
+// Writing an unauthorized response.
+func unauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnauthorized)
+
+	if _, err := w.Write([]byte(`{"code":401,"message":"Invalid access token"}`)); err != nil {
+		log.Error().Err(err).Msg("error writing response")
+	}
+}
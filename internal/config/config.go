@@ -20,6 +20,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
@@ -30,23 +31,106 @@ const defaultConfigPath string = "configs/main"
 type (
 	// Config variables.
 	Config struct {
-		GRPC     GRPCConfig     `mapstructure:"grpc"`
-		Database DatabaseConfig `mapstructure:"database"`
+		GRPC      GRPCConfig      `mapstructure:"grpc"`
+		HTTP      HTTPConfig      `mapstructure:"http"`
+		Connect   ConnectConfig   `mapstructure:"connect"`
+		Metrics   MetricsConfig   `mapstructure:"metrics"`
+		Tracing   TracingConfig   `mapstructure:"tracing"`
+		Auth      AuthConfig      `mapstructure:"auth"`
+		Database  DatabaseConfig  `mapstructure:"database"`
+		Post      PostConfig      `mapstructure:"post"`
+		Outbox    OutboxConfig    `mapstructure:"outbox"`
+		Stats     StatsConfig     `mapstructure:"stats"`
+		Publisher PublisherConfig `mapstructure:"publisher"`
+		Webhook   WebhookConfig   `mapstructure:"webhook"`
+		Health    HealthConfig    `mapstructure:"health"`
 	}
 
 	// gRPC server config variables.
 	GRPCConfig struct {
-		Host string    `mapstructure:"host"`
-		Port string    `mapstructure:"port"`
-		TLS  TLSConfig `mapstructure:"tls"`
+		Host            string          `mapstructure:"host"`
+		Port            string          `mapstructure:"port"`
+		ShutdownTimeout time.Duration   `mapstructure:"shutdown-timeout"`
+		TLS             TLSConfig       `mapstructure:"tls"`
+		AccessLog       AccessLogConfig `mapstructure:"access-log"`
+		ACL             ACLConfig       `mapstructure:"acl"`
+	}
+
+	// gRPC access control list config variables.
+	ACLConfig struct {
+		Enable bool      `mapstructure:"enable"`
+		Rules  []ACLRule `mapstructure:"rules"`
+	}
+
+	// gRPC access control list rule config variables.
+	ACLRule struct {
+		Identity string   `mapstructure:"identity"`
+		Methods  []string `mapstructure:"methods"`
+	}
+
+	// gRPC access log config variables.
+	AccessLogConfig struct {
+		Sample uint32 `mapstructure:"sample"`
+	}
+
+	// HTTP server config variables.
+	HTTPConfig struct {
+		Host      string          `mapstructure:"host"`
+		Port      string          `mapstructure:"port"`
+		GraphQL   GraphQLConfig   `mapstructure:"graphql"`
+		AccessLog AccessLogConfig `mapstructure:"access-log"`
+	}
+
+	// GraphQL endpoint config variables.
+	GraphQLConfig struct {
+		Enable    bool          `mapstructure:"enable"`
+		MaxDepth  int           `mapstructure:"max-depth"`
+		BatchWait time.Duration `mapstructure:"batch-wait"`
+		MaxBatch  int           `mapstructure:"max-batch"`
+	}
+
+	// Connect server config variables.
+	ConnectConfig struct {
+		Host           string          `mapstructure:"host"`
+		Port           string          `mapstructure:"port"`
+		AllowedOrigins []string        `mapstructure:"allowed-origins"`
+		AccessLog      AccessLogConfig `mapstructure:"access-log"`
+	}
+
+	// Metrics server config variables.
+	MetricsConfig struct {
+		Host string `mapstructure:"host"`
+		Port string `mapstructure:"port"`
+		Path string `mapstructure:"path"`
+	}
+
+	// Tracing config variables.
+	TracingConfig struct {
+		Exporter    string  `mapstructure:"exporter"`
+		Endpoint    string  `mapstructure:"endpoint"`
+		Insecure    bool    `mapstructure:"insecure"`
+		ServiceName string  `mapstructure:"service-name"`
+		SampleRatio float64 `mapstructure:"sample-ratio"`
+	}
+
+	// Access token authentication config variables.
+	AuthConfig struct {
+		Enable   bool          `mapstructure:"enable"`
+		Audience string        `mapstructure:"audience"`
+		Issuer   string        `mapstructure:"issuer"`
+		JWKSFile string        `mapstructure:"jwks-file"`
+		Leeway   time.Duration `mapstructure:"leeway"`
+		Secret   string
 	}
 
 	// TLS config variables.
 	TLSConfig struct {
-		Enable bool   `mapstructure:"enable"`
-		CACert string `mapstructure:"ca-cert"`
-		Cert   string `mapstructure:"cert"`
-		Key    string `mapstructure:"key"`
+		Enable        bool          `mapstructure:"enable"`
+		CACert        string        `mapstructure:"ca-cert"`
+		Cert          string        `mapstructure:"cert"`
+		Key           string        `mapstructure:"key"`
+		Reload        bool          `mapstructure:"reload"`
+		ExpiryWarning time.Duration `mapstructure:"expiry-warning"`
 	}
 
 	// Default config variables.
@@ -58,8 +142,96 @@ type (
 	PostgresConfig struct {
 		MaxConns int32 `mapstructure:"max-conns"`
 		MinConns int32 `mapstructure:"min-conns"`
+		Migrate  bool  `mapstructure:"migrate"`
 		URL      string
 	}
+
+	// Post config variables.
+	PostConfig struct {
+		RequireAuth bool            `mapstructure:"require-auth"`
+		Duplicate   DuplicateConfig `mapstructure:"duplicate"`
+		Stream      StreamConfig    `mapstructure:"stream"`
+	}
+
+	// Duplicate post detection config variables.
+	DuplicateConfig struct {
+		Window       time.Duration `mapstructure:"window"`
+		SearchWindow time.Duration `mapstructure:"search-window"`
+		Distance     int           `mapstructure:"distance"`
+		Reject       bool          `mapstructure:"reject"`
+		Limit        int32         `mapstructure:"limit"`
+	}
+
+	// Post events stream config variables.
+	StreamConfig struct {
+		Buffer     int           `mapstructure:"buffer"`
+		Batch      int32         `mapstructure:"batch"`
+		MaxAuthors int           `mapstructure:"max-authors"`
+		Retention  time.Duration `mapstructure:"retention"`
+		Reconnect  time.Duration `mapstructure:"reconnect"`
+	}
+
+	// Outbox relay config variables.
+	OutboxConfig struct {
+		Interval    time.Duration `mapstructure:"interval"`
+		Batch       int32         `mapstructure:"batch"`
+		MaxAttempts int32         `mapstructure:"max-attempts"`
+		Lease       time.Duration `mapstructure:"lease"`
+	}
+
+	// Author stats reconciliation config variables.
+	//
+	// Every interval all author stats are scanned in batches of the batch
+	// authors, each batch in its own transaction with one indexed posts count
+	// per author, so a pass costs about one index scan of each author posts
+	// and holds the stats lock for a single batch at a time.
+	StatsConfig struct {
+		Interval time.Duration `mapstructure:"interval"`
+		Batch    int32         `mapstructure:"batch"`
+	}
+
+	// Post event publisher config variables.
+	PublisherConfig struct {
+		Type string     `mapstructure:"type"`
+		NATS NATSConfig `mapstructure:"nats"`
+	}
+
+	// NATS JetStream publisher config variables.
+	NATSConfig struct {
+		Stream        string             `mapstructure:"stream"`
+		Subjects      NATSSubjectsConfig `mapstructure:"subjects"`
+		Timeout       time.Duration      `mapstructure:"timeout"`
+		ReconnectWait time.Duration      `mapstructure:"reconnect-wait"`
+		MaxReconnects int                `mapstructure:"max-reconnects"`
+		Retries       int                `mapstructure:"retries"`
+		Backoff       time.Duration      `mapstructure:"backoff"`
+		URL           string
+	}
+
+	// NATS post event subjects config variables.
+	NATSSubjectsConfig struct {
+		Created string `mapstructure:"created"`
+		Updated string `mapstructure:"updated"`
+		Deleted string `mapstructure:"deleted"`
+	}
+
+	// Webhook delivery config variables.
+	WebhookConfig struct {
+		Interval    time.Duration `mapstructure:"interval"`
+		Batch       int32         `mapstructure:"batch"`
+		Timeout     time.Duration `mapstructure:"timeout"`
+		Concurrency int32         `mapstructure:"concurrency"`
+		MaxAttempts int32         `mapstructure:"max-attempts"`
+		Backoff     time.Duration `mapstructure:"backoff"`
+		MaxBackoff  time.Duration `mapstructure:"max-backoff"`
+	}
+
+	// Health checker config variables.
+	HealthConfig struct {
+		Interval  time.Duration `mapstructure:"interval"`
+		Timeout   time.Duration `mapstructure:"timeout"`
+		Threshold int           `mapstructure:"threshold"`
+	}
 )
 
 // Initialize config.
@@ -112,4 +284,10 @@ func setFromEnv(cfg *Config) {
 
 	// Postgres database variables.
 	cfg.Database.Postgres.URL = os.Getenv("POSTGRES_URL")
+
+	// NATS publisher variables.
+	cfg.Publisher.NATS.URL = os.Getenv("NATS_URL")
+
+	// Access token authentication variables.
+	cfg.Auth.Secret = os.Getenv("JWT_SECRET")
 }
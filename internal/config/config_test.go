@@ -21,6 +21,7 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/durudex/durudex-post-service/internal/config"
 )
@@ -28,7 +29,7 @@ import (
 // Test initialize config.
 func TestConfig_Init(t *testing.T) {
 	// Environment configurations.
-	type env struct{ configPath, postgresURL string }
+	type env struct{ configPath, postgresURL, natsURL, jwtSecret string }
 
 	// Testing args.
 	type args struct{ env env }
@@ -37,6 +38,8 @@ func TestConfig_Init(t *testing.T) {
 	setEnv := func(env env) {
 		os.Setenv("CONFIG_PATH", env.configPath)
 		os.Setenv("POSTGRES_URL", env.postgresURL)
+		os.Setenv("NATS_URL", env.natsURL)
+		os.Setenv("JWT_SECRET", env.jwtSecret)
 	}
 
 	// Tests structures.
@@ -48,24 +51,153 @@ func TestConfig_Init(t *testing.T) {
 	}{
 		{
 			name: "OK",
-			args: args{env: env{configPath: "fixtures/main", postgresURL: "postgres://localhost:1"}},
+			args: args{env: env{configPath: "fixtures/main", postgresURL: "postgres://localhost:1", natsURL: "nats://localhost:2", jwtSecret: "secret"}},
 			want: &config.Config{
 				GRPC: config.GRPCConfig{
-					Host: "post.service.durudex.local",
-					Port: "8005",
+					Host:            "post.service.durudex.local",
+					Port:            "8005",
+					ShutdownTimeout: 15 * time.Second,
 					TLS: config.TLSConfig{
+						Enable:        true,
+						CACert:        "./certs/rootCA.pem",
+						Cert:          "./certs/post.service.durudex.local-cert.pem",
+						Key:           "./certs/post.service.durudex.local-key.pem",
+						Reload:        true,
+						ExpiryWarning: 720 * time.Hour,
+					},
+					AccessLog: config.AccessLogConfig{Sample: 10},
+					ACL: config.ACLConfig{
 						Enable: true,
-						CACert: "./certs/rootCA.pem",
-						Cert:   "./certs/post.service.durudex.local-cert.pem",
-						Key:    "./certs/post.service.durudex.local-key.pem",
+						Rules: []config.ACLRule{
+							{Identity: "*", Methods: []string{"/grpc.health.v1.Health/*"}},
+							{
+								Identity: "gateway.service.durudex.local",
+								Methods: []string{
+									"/durudex.v1.PostService/CreatePost",
+									"/durudex.v1.PostService/GetPost",
+									"/durudex.v1.PostService/GetPosts",
+									"/durudex.v1.PostService/UpdatePost",
+									"/durudex.v1.PostService/DeletePost",
+									"/durudex.v1.PostService/GetTotalPostsCount",
+									"/durudex.v1.PostService/WatchPosts",
+									"/durudex.v2.PostService/CreatePost",
+									"/durudex.v2.PostService/GetPost",
+									"/durudex.v2.PostService/ListPosts",
+									"/durudex.v2.PostService/UpdatePost",
+									"/durudex.v2.PostService/DeletePost",
+									"/durudex.v2.PostService/GetTotalPostsCount",
+								},
+							},
+							{
+								Identity: "moderation.service.durudex.local",
+								Methods: []string{"/durudex.v1.PostService/*", "/durudex.v2.PostService/*",
+									"/durudex.v1.WebhookService/*"},
+							},
+						},
+					},
+				},
+				HTTP: config.HTTPConfig{
+					Host: "post.service.durudex.local",
+					Port: "8006",
+					GraphQL: config.GraphQLConfig{
+						Enable:    true,
+						MaxDepth:  10,
+						BatchWait: 2 * time.Millisecond,
+						MaxBatch:  100,
 					},
+					AccessLog: config.AccessLogConfig{Sample: 10},
+				},
+				Connect: config.ConnectConfig{
+					Host:           "post.service.durudex.local",
+					Port:           "8007",
+					AllowedOrigins: []string{"https://durudex.com"},
+					AccessLog:      config.AccessLogConfig{Sample: 10},
+				},
+				Metrics: config.MetricsConfig{
+					Host: "post.service.durudex.local",
+					Port: "8008",
+					Path: "/metrics",
+				},
+				Tracing: config.TracingConfig{
+					Exporter:    "otlp",
+					Endpoint:    "jaeger.durudex.local:4317",
+					Insecure:    true,
+					ServiceName: "durudex-post-service",
+					SampleRatio: 1,
+				},
+				Auth: config.AuthConfig{
+					Enable:   true,
+					Audience: "durudex-post-service",
+					Issuer:   "https://auth.durudex.com",
+					JWKSFile: "./certs/jwks.json",
+					Leeway:   30 * time.Second,
+					Secret:   "secret",
 				},
 				Database: config.DatabaseConfig{
 					Postgres: config.PostgresConfig{
 						MaxConns: 20,
 						MinConns: 5,
+						Migrate:  true,
 						URL:      "postgres://localhost:1",
 					}},
+				Post: config.PostConfig{
+					RequireAuth: true,
+					Duplicate: config.DuplicateConfig{
+						Window:       24 * time.Hour,
+						SearchWindow: 720 * time.Hour,
+						Distance:     6,
+						Reject:       true,
+						Limit:        50,
+					},
+					Stream: config.StreamConfig{
+						Buffer:     256,
+						Batch:      100,
+						MaxAuthors: 100,
+						Retention:  24 * time.Hour,
+						Reconnect:  5 * time.Second,
+					},
+				},
+				Outbox: config.OutboxConfig{
+					Interval:    time.Second,
+					Batch:       100,
+					MaxAttempts: 10,
+					Lease:       time.Minute,
+				},
+				Stats: config.StatsConfig{
+					Interval: time.Hour,
+					Batch:    100,
+				},
+				Publisher: config.PublisherConfig{
+					Type: "nats",
+					NATS: config.NATSConfig{
+						Stream: "POST",
+						Subjects: config.NATSSubjectsConfig{
+							Created: "durudex.post.created",
+							Updated: "durudex.post.updated",
+							Deleted: "durudex.post.deleted",
+						},
+						Timeout:       5 * time.Second,
+						ReconnectWait: 2 * time.Second,
+						MaxReconnects: -1,
+						Retries:       3,
+						Backoff:       100 * time.Millisecond,
+						URL:           "nats://localhost:2",
+					},
+				},
+				Webhook: config.WebhookConfig{
+					Interval:    time.Second,
+					Batch:       100,
+					Timeout:     10 * time.Second,
+					Concurrency: 10,
+					MaxAttempts: 8,
+					Backoff:     10 * time.Second,
+					MaxBackoff:  time.Hour,
+				},
+				Health: config.HealthConfig{
+					Interval:  5 * time.Second,
+					Timeout:   2 * time.Second,
+					Threshold: 3,
+				},
 			},
 		},
 	}
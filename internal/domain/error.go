@@ -17,7 +17,10 @@
 
 package domain
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // Error status code.
 type Code int
@@ -28,12 +31,50 @@ const (
 	CodeNotFound
 	CodeAlreadyExists
 	CodeInvalidArgument
+	CodeResourceExhausted
+	CodeUnauthenticated
+	CodeOutOfRange
+	CodeUnavailable
+)
+
+// Machine-readable error reasons.
+const (
+	ReasonPostNotFound            = "POST_NOT_FOUND"
+	ReasonPostDuplicate           = "POST_DUPLICATE"
+	ReasonTextTooLong             = "TEXT_TOO_LONG"
+	ReasonSortRequired            = "SORT_REQUIRED"
+	ReasonInvalidTimeRange        = "INVALID_TIME_RANGE"
+	ReasonInvalidAuthors          = "INVALID_AUTHORS"
+	ReasonConsumerTooSlow         = "CONSUMER_TOO_SLOW"
+	ReasonCursorExpired           = "CURSOR_EXPIRED"
+	ReasonWebhookNotFound         = "WEBHOOK_NOT_FOUND"
+	ReasonWebhookDeliveryNotFound = "WEBHOOK_DELIVERY_NOT_FOUND"
+	ReasonInvalidWebhookUrl       = "INVALID_WEBHOOK_URL"
+	ReasonInvalidEventType        = "INVALID_EVENT_TYPE"
+	ReasonInvalidArgument         = "INVALID_ARGUMENT"
+	ReasonAuthenticationRequired  = "AUTHENTICATION_REQUIRED"
+	ReasonServerShuttingDown      = "SERVER_SHUTTING_DOWN"
 )
 
 // Error structure.
 type Error struct {
 	Code    Code
 	Message string
+	// Machine-readable reason of the error.
+	Reason string
+	// Additional structured details of the error.
+	Metadata map[string]string
+	// Request fields violating constraints.
+	Violations []FieldViolation
+	// Delay after which the request may be retried.
+	RetryDelay time.Duration
+}
+
+// Field violation structure.
+type FieldViolation struct {
+	// Path of the request field, such as "sort_options.first".
+	Field       string
+	Description string
 }
 
 // Getting error message.
@@ -0,0 +1,121 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package domain
+
+import (
+	"crypto/sha256"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/durudex/durudex-post-service/pkg/simhash"
+
+	"github.com/segmentio/ksuid"
+)
+
+const (
+	// Length of the text shingles used for the SimHash.
+	shingleLength int = 4
+	// Minimum number of text shingles to compare fingerprints.
+	minShingles int = 4
+)
+
+// Post content fingerprint structure.
+type Fingerprint struct {
+	// Hash of the normalized post text.
+	Hash []byte
+	// SimHash of the normalized post text.
+	SimHash uint64
+	// Number of the normalized post text shingles.
+	Shingles int
+}
+
+// Check is the fingerprint distinctive enough to find duplicates. Texts
+// without letters or digits and very short texts share their fingerprints.
+func (f Fingerprint) Comparable() bool { return f.Shingles >= minShingles }
+
+// Similar posts query options.
+type SimilarOptions struct {
+	// Author of similar posts, any author if nil.
+	AuthorId ksuid.KSUID
+	// Post excluded from the result.
+	Exclude ksuid.KSUID
+	// Maximum SimHash distance.
+	Distance int
+	// Minimum creation time of similar posts, any time if zero.
+	Since time.Time
+	// Maximum number of similar posts.
+	Limit int32
+}
+
+// Creating a new post content fingerprint.
+func NewFingerprint(text string) Fingerprint {
+	normalized := normalizeText(text)
+	hash := sha256.Sum256([]byte(normalized))
+	features := shingles(normalized)
+
+	fingerprint := Fingerprint{Hash: hash[:], SimHash: simhash.Sum(features)}
+
+	if normalized != "" {
+		fingerprint.Shingles = len(features)
+	}
+
+	return fingerprint
+}
+
+// Normalizing a text to lowercase letters and digits separated by single spaces.
+func normalizeText(text string) string {
+	var b strings.Builder
+
+	space := false
+
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			// Write a single space between words.
+			if space && b.Len() != 0 {
+				b.WriteByte(' ')
+			}
+
+			b.WriteRune(unicode.ToLower(r))
+
+			space = false
+		} else {
+			space = true
+		}
+	}
+
+	return b.String()
+}
+
+// Splitting a text into overlapping character shingles.
+func shingles(text string) []string {
+	runes := []rune(text)
+
+	// Check is text shorter than a shingle.
+	if len(runes) <= shingleLength {
+		return []string{text}
+	}
+
+	features := make([]string, 0, len(runes)-shingleLength+1)
+
+	for i := 0; i+shingleLength <= len(runes); i++ {
+		features = append(features, string(runes[i:i+shingleLength]))
+	}
+
+	return features
+}
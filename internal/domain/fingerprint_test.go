@@ -0,0 +1,96 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package domain
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/pkg/simhash"
+)
+
+// Testing creating a new post content fingerprint.
+func TestNewFingerprint(t *testing.T) {
+	// Testing args.
+	type args struct{ a, b string }
+
+	// Tests structures.
+	tests := []struct {
+		name      string
+		args      args
+		sameHash  bool
+		maxSimDst int
+	}{
+		{
+			name:      "Normalized",
+			args:      args{a: "Hello,   World!", b: "hello world"},
+			sameHash:  true,
+			maxSimDst: 0,
+		},
+		{
+			name: "Near duplicate",
+			args: args{
+				a: "Buy cheap watches online today with the best price guaranteed and free shipping",
+				b: "Buy cheap watches online today with the best price guaranteed and free shipping now",
+			},
+			sameHash:  false,
+			maxSimDst: 8,
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := NewFingerprint(tt.args.a), NewFingerprint(tt.args.b)
+
+			// Check for similarity of hashes.
+			if bytes.Equal(a.Hash, b.Hash) != tt.sameHash {
+				t.Errorf("error hash similarity is not %t", tt.sameHash)
+			}
+
+			// Check distance between fingerprints.
+			if dist := simhash.Distance(a.SimHash, b.SimHash); dist > tt.maxSimDst {
+				t.Errorf("error simhash distance %d is greater than %d", dist, tt.maxSimDst)
+			}
+		})
+	}
+}
+
+// Testing checking is a fingerprint comparable.
+func TestFingerprint_Comparable(t *testing.T) {
+	// Tests structures.
+	tests := []struct {
+		name string
+		text string
+		want bool
+	}{
+		{name: "OK", text: "This is a test post.", want: true},
+		{name: "Punctuation", text: "?!... :)", want: false},
+		{name: "Emoji", text: "🎉🎉🎉", want: false},
+		{name: "Short", text: "ok lol", want: false},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewFingerprint(tt.text).Comparable(); got != tt.want {
+				t.Errorf("error comparable: got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -25,17 +25,83 @@ import (
 
 // Post structure.
 type Post struct {
-	Id        ksuid.KSUID
-	AuthorId  ksuid.KSUID
-	Text      string
-	UpdatedAt *time.Time
+	Id          ksuid.KSUID
+	AuthorId    ksuid.KSUID
+	Text        string
+	Flagged     bool
+	CreatedAt   time.Time
+	UpdatedAt   *time.Time
+	Fingerprint Fingerprint
+}
+
+// Post fields.
+const (
+	PostFieldId        = "id"
+	PostFieldAuthorId  = "author_id"
+	PostFieldText      = "text"
+	PostFieldFlagged   = "flagged"
+	PostFieldCreatedAt = "created_at"
+	PostFieldUpdatedAt = "updated_at"
+)
+
+// Author posts filter structure. Zero times are not filtered.
+type PostFilter struct {
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
+// Check is posts filter empty.
+func (f PostFilter) IsZero() bool {
+	return f.CreatedAfter.IsZero() && f.CreatedBefore.IsZero()
+}
+
+// Validate posts filter.
+func (f PostFilter) Validate() error {
+	// Check creation time range.
+	if !f.CreatedAfter.IsZero() && !f.CreatedBefore.IsZero() && !f.CreatedAfter.Before(f.CreatedBefore) {
+		return &Error{
+			Code:    CodeInvalidArgument,
+			Message: "Invalid creation time range",
+			Reason:  ReasonInvalidTimeRange,
+			Violations: []FieldViolation{
+				{Field: "created_before", Description: "Must be after `created_after`"},
+			},
+		}
+	}
+
+	return nil
+}
+
+// Field mask of the read or updated fields. An empty mask selects the default
+// fields.
+type FieldMask []string
+
+// Checking if the mask selects the field.
+func (m FieldMask) Has(field string) bool {
+	if len(m) == 0 {
+		return true
+	}
+
+	for _, f := range m {
+		if f == field {
+			return true
+		}
+	}
+
+	return false
 }
 
 // Validate post.
 func (p Post) Validate() error {
 	// Check post text length.
 	if len(p.Text) > 500 {
-		return &Error{Code: CodeInvalidArgument, Message: "Text is too long"}
+		return &Error{
+			Code:       CodeInvalidArgument,
+			Message:    "Text is too long",
+			Reason:     ReasonTextTooLong,
+			Metadata:   map[string]string{"max_length": "500"},
+			Violations: []FieldViolation{{Field: "text", Description: "Text must be at most 500 bytes"}},
+		}
 	}
 
 	return nil
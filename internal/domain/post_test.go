@@ -17,7 +17,11 @@
 
 package domain
 
-import "testing"
+import (
+	"errors"
+	"strings"
+	"testing"
+)
 
 // Testing validate a post.
 func TestPost_Validate(t *testing.T) {
@@ -26,15 +30,22 @@ func TestPost_Validate(t *testing.T) {
 
 	// Tests structures.
 	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
+		name      string
+		args      args
+		wantErr   bool
+		wantField string
 	}{
 		{
 			name:    "OK",
 			args:    args{text: "Hello world!"},
 			wantErr: false,
 		},
+		{
+			name:      "Text too long",
+			args:      args{text: strings.Repeat("a", 501)},
+			wantErr:   true,
+			wantField: "text",
+		},
 	}
 
 	// Conducting tests in various structures.
@@ -46,7 +57,13 @@ func TestPost_Validate(t *testing.T) {
 			// Validate post.
 			err := post.Validate()
 			if (err != nil) != tt.wantErr {
-				t.Errorf("error validation post: %s", err.Error())
+				t.Fatalf("error validation post: %v", err)
+			}
+
+			// Check field violation.
+			var e *Error
+			if tt.wantErr && (!errors.As(err, &e) || len(e.Violations) != 1 || e.Violations[0].Field != tt.wantField) {
+				t.Errorf("error field violation: %v", err)
 			}
 		})
 	}
@@ -0,0 +1,104 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package domain
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+// Webhook delivery status.
+type DeliveryStatus string
+
+// Webhook delivery statuses.
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Webhook subscription structure.
+type Webhook struct {
+	Id        ksuid.KSUID
+	URL       string
+	Events    []EventType
+	Secret    string
+	CreatedAt time.Time
+}
+
+// Validate webhook.
+func (w Webhook) Validate() error {
+	// Check webhook target url.
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &Error{
+			Code:       CodeInvalidArgument,
+			Message:    "Invalid webhook url",
+			Reason:     ReasonInvalidWebhookUrl,
+			Violations: []FieldViolation{{Field: "url", Description: "Url must be an absolute http or https url"}},
+		}
+	}
+
+	// Check webhook event filter.
+	for _, t := range w.Events {
+		if t != EventPostCreated && t != EventPostUpdated && t != EventPostDeleted {
+			return &Error{
+				Code:       CodeInvalidArgument,
+				Message:    "Invalid webhook event type",
+				Reason:     ReasonInvalidEventType,
+				Violations: []FieldViolation{{Field: "events", Description: "Unknown post event type"}},
+			}
+		}
+	}
+
+	return nil
+}
+
+// Check is the webhook subscribed to the event type. A webhook without an event
+// filter is subscribed to all event types.
+func (w Webhook) Accepts(t EventType) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+
+	for _, e := range w.Events {
+		if e == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Webhook delivery structure.
+type WebhookDelivery struct {
+	Id            ksuid.KSUID
+	WebhookId     ksuid.KSUID
+	EventId       int64
+	EventType     EventType
+	Payload       []byte
+	Status        DeliveryStatus
+	Attempts      int32
+	ResponseCode  int32
+	Error         string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     *time.Time
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package domain
+
+import "testing"
+
+// Testing validate a webhook.
+func TestWebhook_Validate(t *testing.T) {
+	// Testing args.
+	type args struct {
+		url    string
+		events []EventType
+	}
+
+	// Tests structures.
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			args: args{url: "https://example.com/hook", events: []EventType{EventPostCreated}},
+		},
+		{
+			name:    "Invalid URL",
+			args:    args{url: "example.com/hook"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Event Type",
+			args:    args{url: "https://example.com/hook", events: []EventType{"post.unknown"}},
+			wantErr: true,
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Creating a new webhook.
+			webhook := Webhook{URL: tt.args.url, Events: tt.args.events}
+
+			// Validate webhook.
+			err := webhook.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error validation webhook: %v", err)
+			}
+		})
+	}
+}
+
+// Testing webhook event filter.
+func TestWebhook_Accepts(t *testing.T) {
+	// Tests structures.
+	tests := []struct {
+		name   string
+		events []EventType
+		event  EventType
+		want   bool
+	}{
+		{name: "All", event: EventPostDeleted, want: true},
+		{name: "Subscribed", events: []EventType{EventPostCreated}, event: EventPostCreated, want: true},
+		{name: "Not Subscribed", events: []EventType{EventPostCreated}, event: EventPostUpdated},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Check is the webhook subscribed to the event.
+			if got := (Webhook{Events: tt.events}).Accepts(tt.event); got != tt.want {
+				t.Errorf("error webhook accepts: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
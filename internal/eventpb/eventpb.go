@@ -0,0 +1,59 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package eventpb
+
+import (
+	"github.com/durudex/dugopb/type/timestamp"
+	"github.com/durudex/durudex-post-service/internal/domain"
+	v1 "github.com/durudex/durudex-post-service/pkg/pb/durudex/v1"
+)
+
+// Post event types.
+var types = map[domain.EventType]v1.PostEventType{
+	domain.EventPostCreated: v1.PostEventType_POST_EVENT_TYPE_CREATED,
+	domain.EventPostUpdated: v1.PostEventType_POST_EVENT_TYPE_UPDATED,
+	domain.EventPostDeleted: v1.PostEventType_POST_EVENT_TYPE_DELETED,
+}
+
+// Getting the protobuf type of a post event type.
+func Type(t domain.EventType) v1.PostEventType { return types[t] }
+
+// Getting the post event type of a protobuf type.
+func DomainType(t v1.PostEventType) (domain.EventType, bool) {
+	for e, pt := range types {
+		if pt == t {
+			return e, true
+		}
+	}
+
+	return "", false
+}
+
+// Converting a post event to the protobuf message.
+func Event(event domain.PostEvent) *v1.PostEvent {
+	return &v1.PostEvent{
+		Id:   event.Id,
+		Type: Type(event.Type),
+		Post: &v1.Post{
+			Id:       event.Post.Id.Bytes(),
+			AuthorId: event.Post.AuthorId.Bytes(),
+			Text:     event.Post.Text,
+		},
+		CreatedAt: timestamp.New(event.CreatedAt),
+	}
+}
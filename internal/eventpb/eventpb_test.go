@@ -0,0 +1,57 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package eventpb_test
+
+import (
+	"testing"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/eventpb"
+	v1 "github.com/durudex/durudex-post-service/pkg/pb/durudex/v1"
+)
+
+// Testing converting post event types.
+func TestType(t *testing.T) {
+	// Tests structures.
+	tests := []struct {
+		name   string
+		domain domain.EventType
+		pb     v1.PostEventType
+		wantOk bool
+	}{
+		{name: "Created", domain: domain.EventPostCreated, pb: v1.PostEventType_POST_EVENT_TYPE_CREATED, wantOk: true},
+		{name: "Updated", domain: domain.EventPostUpdated, pb: v1.PostEventType_POST_EVENT_TYPE_UPDATED, wantOk: true},
+		{name: "Deleted", domain: domain.EventPostDeleted, pb: v1.PostEventType_POST_EVENT_TYPE_DELETED, wantOk: true},
+		{name: "Unspecified", pb: v1.PostEventType_POST_EVENT_TYPE_UNSPECIFIED},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Check for similarity of protobuf type.
+			if got := eventpb.Type(tt.domain); got != tt.pb {
+				t.Errorf("error protobuf types are not similar: %s", got)
+			}
+
+			// Check for similarity of domain type.
+			if got, ok := eventpb.DomainType(tt.pb); got != tt.domain || ok != tt.wantOk {
+				t.Errorf("error domain types are not similar: %s", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics namespace.
+const namespace = "durudex_post"
+
+// Service metrics registry.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// RPC requests counter by method and status code.
+	RequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rpc",
+		Name:      "requests_total",
+		Help:      "Total number of RPC requests by method and status code.",
+	}, []string{"method", "code"})
+
+	// RPC request latency by method and status code.
+	RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "rpc",
+		Name:      "request_duration_seconds",
+		Help:      "RPC request latency by method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	// Recovered handler panics counter by method.
+	PanicsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rpc",
+		Name:      "panics_total",
+		Help:      "Total number of recovered RPC handler panics by method.",
+	}, []string{"method"})
+
+	// Database query latency by query name.
+	QueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "database",
+		Name:      "query_duration_seconds",
+		Help:      "Database query latency by query name.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"query"})
+
+	// Post events counter by event type.
+	PostEventsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_total",
+		Help:      "Total number of created, updated and deleted posts.",
+	}, []string{"type"})
+
+	// Repaired author posts counts counter.
+	AuthorStatsRepairedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "stats",
+		Name:      "repaired_total",
+		Help:      "Total number of repaired drifted author posts counts.",
+	})
+)
+
+// Registering service metrics.
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		RequestsTotal,
+		RequestDuration,
+		PanicsTotal,
+		QueryDuration,
+		PostEventsTotal,
+		AuthorStatsRepairedTotal,
+	)
+}
+
+// Getting metrics HTTP handler.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Metrics server structure.
+type Server struct {
+	server *http.Server
+	config config.MetricsConfig
+}
+
+// Creating a new metrics server.
+func NewServer(cfg config.MetricsConfig) *Server {
+	mux := http.NewServeMux()
+	mux.Handle(cfg.Path, Handler())
+
+	return &Server{
+		server: &http.Server{
+			Addr:              cfg.Host + ":" + cfg.Port,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+		config: cfg,
+	}
+}
+
+// Running metrics server.
+func (s *Server) Run() {
+	log.Info().Msg("Running metrics server...")
+
+	// Running metrics server.
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal().Err(err).Msg("error running metrics server")
+	}
+}
+
+// Stopping metrics server.
+func (s *Server) Stop(ctx context.Context) error {
+	log.Info().Msg("Stopping metrics server...")
+
+	return s.server.Shutdown(ctx)
+}
@@ -0,0 +1,40 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Certificate expiry interface.
+type CertificateExpirer interface {
+	// Getting current certificate expiry time.
+	NotAfter() time.Time
+}
+
+// Creating a new TLS certificate expiry metric.
+func NewCertificateExpiry(cert CertificateExpirer) prometheus.GaugeFunc {
+	return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "tls",
+		Name:      "certificate_expiry_timestamp_seconds",
+		Help:      "Expiry time of the current server TLS certificate in unix seconds.",
+	}, func() float64 { return float64(cert.NotAfter().Unix()) })
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package publisher
+
+import (
+	"context"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Logging post event publisher structure.
+type LogPublisher struct{}
+
+// Creating a new logging post event publisher.
+func NewLogPublisher() *LogPublisher {
+	return &LogPublisher{}
+}
+
+// Publishing a post event.
+func (p *LogPublisher) Publish(ctx context.Context, event domain.PostEvent) error {
+	log.Debug().
+		Int64("id", event.Id).
+		Str("type", string(event.Type)).
+		Str("post_id", event.Post.Id.String()).
+		Msg("Publishing post event")
+
+	return nil
+}
+
+// Closing the publisher.
+func (p *LogPublisher) Close() error { return nil }
@@ -0,0 +1,60 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package publisher
+
+import (
+	"context"
+	"sync"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+)
+
+// In-memory post event publisher structure.
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []domain.PostEvent
+}
+
+// Creating a new in-memory post event publisher.
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+// Publishing a post event.
+func (p *MemoryPublisher) Publish(ctx context.Context, event domain.PostEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, event)
+
+	return nil
+}
+
+// Getting published post events.
+func (p *MemoryPublisher) Events() []domain.PostEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	events := make([]domain.PostEvent, len(p.events))
+	copy(events, p.events)
+
+	return events
+}
+
+// Closing the publisher.
+func (p *MemoryPublisher) Close() error { return nil }
@@ -0,0 +1,61 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package publisher
+
+import (
+	"context"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+)
+
+// Fan-out post event publisher structure.
+type MultiPublisher struct{ publishers []EventPublisher }
+
+// Creating a new fan-out post event publisher.
+func NewMultiPublisher(publishers ...EventPublisher) *MultiPublisher {
+	return &MultiPublisher{publishers: publishers}
+}
+
+// Publishing a post event to all publishers.
+//
+// The event is published to every publisher even if one of them fails, and the
+// first error is returned, so the event is relayed again to all of them.
+func (p *MultiPublisher) Publish(ctx context.Context, event domain.PostEvent) error {
+	var err error
+
+	for _, publisher := range p.publishers {
+		if e := publisher.Publish(ctx, event); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+// Closing all publishers.
+func (p *MultiPublisher) Close() error {
+	var err error
+
+	for _, publisher := range p.publishers {
+		if e := publisher.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/eventpb"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/proto"
+)
+
+// NATS JetStream interface.
+type JetStream interface {
+	// Publishing a message to the stream.
+	PublishMsg(m *nats.Msg, opts ...nats.PubOpt) (*nats.PubAck, error)
+}
+
+// NATS JetStream post event publisher structure.
+type NATSPublisher struct {
+	conn     *nats.Conn
+	js       JetStream
+	subjects map[domain.EventType]string
+	cfg      config.NATSConfig
+}
+
+// Creating a new NATS JetStream post event publisher.
+func NewNATSPublisher(cfg config.NATSConfig) (*NATSPublisher, error) {
+	log.Debug().Msg("Creating a new NATS publisher...")
+
+	// Connecting to the NATS server.
+	conn, err := nats.Connect(cfg.URL,
+		nats.Name("durudex-post-service"),
+		nats.Timeout(cfg.Timeout),
+		nats.RetryOnFailedConnect(true),
+		nats.ReconnectWait(cfg.ReconnectWait),
+		nats.MaxReconnects(cfg.MaxReconnects),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			log.Warn().Err(err).Msg("NATS connection lost")
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			log.Info().Msgf("NATS reconnected to %s", conn.ConnectedUrl())
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Creating a new JetStream context.
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	publisher := NewJetStreamPublisher(js, cfg)
+	publisher.conn = conn
+
+	return publisher, nil
+}
+
+// Creating a new post event publisher with the JetStream.
+func NewJetStreamPublisher(js JetStream, cfg config.NATSConfig) *NATSPublisher {
+	return &NATSPublisher{
+		js: js,
+		subjects: map[domain.EventType]string{
+			domain.EventPostCreated: cfg.Subjects.Created,
+			domain.EventPostUpdated: cfg.Subjects.Updated,
+			domain.EventPostDeleted: cfg.Subjects.Deleted,
+		},
+		cfg: cfg,
+	}
+}
+
+// Publishing a post event.
+//
+// The event id is sent as the JetStream message id, so events relayed again
+// after a failure are deduplicated by the stream. Failed publishes are retried
+// with an exponential backoff.
+func (p *NATSPublisher) Publish(ctx context.Context, event domain.PostEvent) error {
+	// Getting event subject.
+	subject, ok := p.subjects[event.Type]
+	if !ok || subject == "" {
+		return fmt.Errorf("unknown post event type: %s", event.Type)
+	}
+
+	// Encoding post event.
+	data, err := proto.Marshal(eventpb.Event(event))
+	if err != nil {
+		return err
+	}
+
+	backoff := p.cfg.Backoff
+
+	for attempt := 0; ; attempt++ {
+		if err = p.publish(ctx, subject, strconv.FormatInt(event.Id, 10), data); err == nil {
+			return nil
+		}
+
+		if attempt >= p.cfg.Retries {
+			return err
+		}
+
+		log.Warn().Err(err).Int64("id", event.Id).Msg("error publishing post event, retrying")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
+
+// Publishing a message to the JetStream.
+func (p *NATSPublisher) publish(ctx context.Context, subject, id string, data []byte) error {
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+
+	// Set deduplication headers.
+	msg.Header.Set(nats.MsgIdHdr, id)
+
+	if p.cfg.Stream != "" {
+		msg.Header.Set(nats.ExpectedStreamHdr, p.cfg.Stream)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+	defer cancel()
+
+	_, err := p.js.PublishMsg(msg, nats.Context(ctx))
+
+	return err
+}
+
+// Closing the NATS connection.
+func (p *NATSPublisher) Close() error {
+	if p.conn == nil {
+		return nil
+	}
+
+	return p.conn.Drain()
+}
@@ -0,0 +1,275 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package publisher_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/publisher"
+	v1 "github.com/durudex/durudex-post-service/pkg/pb/durudex/v1"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/segmentio/ksuid"
+	"google.golang.org/protobuf/proto"
+)
+
+// Running an embedded NATS server with the post stream.
+func runJetStream(t *testing.T) (string, nats.JetStreamContext) {
+	t.Helper()
+
+	// Creating a new NATS server.
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		t.Fatalf("error creating a new NATS server: %s", err.Error())
+	}
+
+	go srv.Start()
+	t.Cleanup(srv.Shutdown)
+
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("error NATS server is not ready")
+	}
+
+	// Connecting to the NATS server.
+	conn, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("error connecting to NATS server: %s", err.Error())
+	}
+	t.Cleanup(conn.Close)
+
+	js, err := conn.JetStream()
+	if err != nil {
+		t.Fatalf("error creating a new JetStream context: %s", err.Error())
+	}
+
+	// Creating the post stream.
+	if _, err := js.AddStream(&nats.StreamConfig{
+		Name:       "POST",
+		Subjects:   []string{"durudex.post.>"},
+		Duplicates: time.Minute,
+	}); err != nil {
+		t.Fatalf("error creating post stream: %s", err.Error())
+	}
+
+	return srv.ClientURL(), js
+}
+
+// JetStream failing the first publishes structure.
+type failingJetStream struct {
+	publisher.JetStream
+	fails, calls int
+}
+
+// Publishing a message to the stream.
+func (js *failingJetStream) PublishMsg(m *nats.Msg, opts ...nats.PubOpt) (*nats.PubAck, error) {
+	js.calls++
+
+	if js.calls <= js.fails {
+		return nil, errors.New("nats: timeout")
+	}
+
+	return js.JetStream.PublishMsg(m, opts...)
+}
+
+// Testing publishing a post event to the NATS JetStream.
+func TestNATSPublisher_Publish(t *testing.T) {
+	// NATS config.
+	cfg := config.NATSConfig{
+		Stream: "POST",
+		Subjects: config.NATSSubjectsConfig{
+			Created: "durudex.post.created",
+			Updated: "durudex.post.updated",
+			Deleted: "durudex.post.deleted",
+		},
+		Timeout:       time.Second,
+		ReconnectWait: time.Second,
+		Retries:       2,
+		Backoff:       time.Millisecond,
+	}
+
+	event := domain.PostEvent{
+		Id:        42,
+		Type:      domain.EventPostCreated,
+		Post:      domain.Post{Id: ksuid.New(), AuthorId: ksuid.New(), Text: "Example text"},
+		CreatedAt: time.Now(),
+	}
+
+	// Tests structures.
+	tests := []struct {
+		name      string
+		events    []domain.PostEvent
+		stream    string
+		fails     int
+		wantCalls int
+		wantMsgs  int
+		wantErr   bool
+	}{
+		{
+			name:      "OK",
+			events:    []domain.PostEvent{event},
+			wantCalls: 1,
+			wantMsgs:  1,
+		},
+		{
+			name:      "Deduplicated",
+			events:    []domain.PostEvent{event, event},
+			wantCalls: 2,
+			wantMsgs:  1,
+		},
+		{
+			name:      "Retry",
+			events:    []domain.PostEvent{event},
+			fails:     2,
+			wantCalls: 3,
+			wantMsgs:  1,
+		},
+		{
+			name:      "Retries Exhausted",
+			events:    []domain.PostEvent{event},
+			fails:     3,
+			wantCalls: 3,
+			wantErr:   true,
+		},
+		{
+			name:      "Wrong Stream",
+			events:    []domain.PostEvent{event},
+			stream:    "WEBHOOK",
+			wantCalls: 3,
+			wantErr:   true,
+		},
+		{
+			name:    "Unknown Type",
+			events:  []domain.PostEvent{{Id: 1, Type: "post.unknown"}},
+			wantErr: true,
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, stream := runJetStream(t)
+
+			cfg := cfg
+			cfg.URL = url
+
+			if tt.stream != "" {
+				cfg.Stream = tt.stream
+			}
+
+			// Creating a new NATS publisher.
+			conn, err := publisher.NewNATSPublisher(cfg)
+			if err != nil {
+				t.Fatalf("error creating a new NATS publisher: %s", err.Error())
+			}
+			defer conn.Close()
+
+			js := &failingJetStream{JetStream: stream, fails: tt.fails}
+
+			// Creating a new JetStream publisher.
+			pub := publisher.NewJetStreamPublisher(js, cfg)
+
+			// Publishing post events.
+			for _, event := range tt.events {
+				if err = pub.Publish(context.Background(), event); err != nil {
+					break
+				}
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error publishing post event: %v", err)
+			}
+
+			// Check for number of publish calls.
+			if js.calls != tt.wantCalls {
+				t.Errorf("error publish calls: got %d, want %d", js.calls, tt.wantCalls)
+			}
+
+			// Check for number of stored messages.
+			info, err := stream.StreamInfo("POST")
+			if err != nil {
+				t.Fatalf("error getting stream info: %s", err.Error())
+			}
+
+			if int(info.State.Msgs) != tt.wantMsgs {
+				t.Fatalf("error stored messages: got %d, want %d", info.State.Msgs, tt.wantMsgs)
+			}
+
+			if tt.wantMsgs == 0 {
+				return
+			}
+
+			// Publishing with the publisher connection.
+			if err := conn.Publish(context.Background(), domain.PostEvent{Id: 43, Type: domain.EventPostDeleted,
+				Post: event.Post}); err != nil {
+				t.Fatalf("error publishing post event: %s", err.Error())
+			}
+
+			// Getting stored messages.
+			sub, err := stream.SubscribeSync("durudex.post.>", nats.DeliverAll())
+			if err != nil {
+				t.Fatalf("error subscribing to post stream: %s", err.Error())
+			}
+
+			for _, want := range []struct {
+				domain.PostEvent
+				subject string
+				typ     v1.PostEventType
+			}{
+				{event, cfg.Subjects.Created, v1.PostEventType_POST_EVENT_TYPE_CREATED},
+				{domain.PostEvent{Id: 43, Post: event.Post}, cfg.Subjects.Deleted, v1.PostEventType_POST_EVENT_TYPE_DELETED},
+			} {
+				msg, err := sub.NextMsg(time.Second)
+				if err != nil {
+					t.Fatalf("error getting stored message: %s", err.Error())
+				}
+
+				// Check for message subject and headers.
+				if msg.Subject != want.subject || msg.Header.Get(nats.MsgIdHdr) != strconv.FormatInt(want.Id, 10) {
+					t.Errorf("error message subject or headers: %s %v", msg.Subject, msg.Header)
+				}
+
+				var got v1.PostEvent
+
+				// Decoding message data.
+				if err := proto.Unmarshal(msg.Data, &got); err != nil {
+					t.Fatalf("error decoding post event: %s", err.Error())
+				}
+
+				// Check for similarity of a post event.
+				if got.Id != want.Id || got.Type != want.typ || ksuid.FromBytesOrNil(got.Post.Id) != want.Post.Id ||
+					got.Post.Text != want.Post.Text {
+					t.Error("error post events are not similar")
+				}
+			}
+		})
+	}
+}
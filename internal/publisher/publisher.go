@@ -0,0 +1,46 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package publisher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/domain"
+)
+
+// Post event publisher interface.
+type EventPublisher interface {
+	// Publishing a post event.
+	Publish(ctx context.Context, event domain.PostEvent) error
+	// Closing the publisher.
+	Close() error
+}
+
+// Creating a new post event publisher by the config type.
+func NewPublisher(cfg config.PublisherConfig) (EventPublisher, error) {
+	switch cfg.Type {
+	case "nats":
+		return NewNATSPublisher(cfg.NATS)
+	case "log", "":
+		return NewLogPublisher(), nil
+	default:
+		return nil, fmt.Errorf("unknown publisher type: %s", cfg.Type)
+	}
+}
@@ -0,0 +1,198 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/pkg/database/postgres"
+
+	"github.com/leporo/sqlf"
+	"github.com/segmentio/ksuid"
+)
+
+// Post events notification channel.
+const eventChannel string = "post_event"
+
+// Advisory lock key that allows only one post events positioning at a time.
+const eventPositionLockKey int64 = 0x706f73745f706f73
+
+// Event repository interface.
+//
+// Event cursors are stream positions taken in commit order, so events are
+// never committed behind a cursor that was already read.
+type Event interface {
+	// Positioning committed post events in postgres database.
+	PositionEvents(ctx context.Context, limit int32) (int, error)
+	// Getting post events after the cursor in postgres database.
+	GetEvents(ctx context.Context, authorIds []ksuid.KSUID, cursor int64, limit int32) ([]domain.PostEvent, error)
+	// Getting the last post event cursor in postgres database.
+	GetLastCursor(ctx context.Context) (int64, error)
+	// Getting the cursor of the last deleted post event in postgres database.
+	GetExpiredCursor(ctx context.Context) (int64, error)
+	// Deleting post events created before the time in postgres database.
+	DeleteEvents(ctx context.Context, before time.Time) error
+	// Listening for new post events in postgres database.
+	Listen(ctx context.Context, handler func()) error
+}
+
+// Event repository structure.
+type EventRepository struct {
+	psql     postgres.Postgres
+	listener postgres.Listener
+}
+
+// Creating a new event repository.
+func NewEventRepository(psql postgres.Postgres, listener postgres.Listener) *EventRepository {
+	return &EventRepository{psql: psql, listener: listener}
+}
+
+// Positioning committed post events in postgres database.
+//
+// Only committed events are visible, and positioning transactions are
+// serialized by an advisory lock, so positions are taken in commit order
+// without post transactions waiting for each other. Returns the number of
+// positioned events.
+func (r *EventRepository) PositionEvents(ctx context.Context, limit int32) (int, error) {
+	// Begin a positioning transaction.
+	tx, err := r.psql.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Wait for the positioning of another dispatcher to commit.
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", eventPositionLockKey); err != nil {
+		return 0, err
+	}
+
+	// Query for positioning committed post events.
+	query := `UPDATE post_event SET position = nextval('post_event_position_seq')
+		WHERE id IN (SELECT id FROM post_event WHERE position IS NULL ORDER BY id ASC LIMIT $1)`
+
+	tag, err := tx.Exec(ctx, query, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// Getting post events after the cursor in postgres database.
+func (r *EventRepository) GetEvents(ctx context.Context, authorIds []ksuid.KSUID, cursor int64, limit int32) ([]domain.PostEvent, error) {
+	qb := sqlf.PostgreSQL.Select("position, type, post_id, author_id, COALESCE(text, ''), created_at").
+		From("post_event").Where("position > ?", cursor)
+	defer qb.Close()
+
+	// Added authors filter.
+	if authorIds != nil {
+		ids := make([]string, len(authorIds))
+
+		for i, id := range authorIds {
+			ids[i] = id.String()
+		}
+
+		qb.Where("author_id = ANY(?)", ids)
+	}
+
+	qb.OrderBy("position ASC").Limit(limit)
+
+	// Query for getting post events.
+	rows, err := r.psql.Query(ctx, qb.String(), qb.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]domain.PostEvent, 0, limit)
+
+	// Scanning query rows.
+	for rows.Next() {
+		var event domain.PostEvent
+
+		// Scanning query row.
+		if err := rows.Scan(&event.Id, &event.Type, &event.Post.Id, &event.Post.AuthorId,
+			&event.Post.Text, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	// Check is rows error.
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Getting the last post event cursor in postgres database.
+func (r *EventRepository) GetLastCursor(ctx context.Context) (int64, error) {
+	var cursor int64
+
+	// Query for getting the last post event position.
+	query := "SELECT COALESCE(max(position), 0) FROM post_event"
+
+	row := r.psql.QueryRow(ctx, query)
+
+	// Scanning query row.
+	if err := row.Scan(&cursor); err != nil {
+		return 0, err
+	}
+
+	return cursor, nil
+}
+
+// Getting the cursor of the last deleted post event in postgres database.
+func (r *EventRepository) GetExpiredCursor(ctx context.Context) (int64, error) {
+	var cursor int64
+
+	// Query for getting the last deleted post event position.
+	query := "SELECT position FROM post_event_retention"
+
+	row := r.psql.QueryRow(ctx, query)
+
+	// Scanning query row.
+	if err := row.Scan(&cursor); err != nil {
+		return 0, err
+	}
+
+	return cursor, nil
+}
+
+// Deleting post events created before the time in postgres database.
+func (r *EventRepository) DeleteEvents(ctx context.Context, before time.Time) error {
+	// Query for deleting old post events and keeping the last deleted position.
+	query := `WITH deleted AS (DELETE FROM post_event WHERE created_at < $1 RETURNING position)
+		UPDATE post_event_retention SET position = GREATEST(position, (SELECT max(position) FROM deleted))`
+	_, err := r.psql.Exec(ctx, query, before)
+
+	return err
+}
+
+// Listening for new post events in postgres database.
+func (r *EventRepository) Listen(ctx context.Context, handler func()) error {
+	return r.listener.Listen(ctx, eventChannel, func(string) { handler() })
+}
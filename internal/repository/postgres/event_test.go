@@ -0,0 +1,303 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/repository/postgres"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/segmentio/ksuid"
+)
+
+// Postgres listener mock structure.
+type mockListener struct{ payloads []string }
+
+// Calling the handler for each payload.
+func (l *mockListener) Listen(ctx context.Context, channel string, handler func(payload string)) error {
+	for _, payload := range l.payloads {
+		handler(payload)
+	}
+
+	return nil
+}
+
+// Testing getting post events after the cursor in postgres database.
+func TestEventRepository_GetEvents(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Testing args.
+	type args struct {
+		authorIds []ksuid.KSUID
+		cursor    int64
+		limit     int32
+	}
+
+	// Test behavior.
+	type mockBehavior func(args args, want []domain.PostEvent)
+
+	// Creating a new repository.
+	repos := postgres.NewEventRepository(mock, &mockListener{})
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		args         args
+		want         []domain.PostEvent
+		wantErr      bool
+		mockBehavior mockBehavior
+	}{
+		{
+			name: "OK",
+			args: args{authorIds: []ksuid.KSUID{ksuid.New()}, cursor: 10, limit: 100},
+			want: []domain.PostEvent{
+				{
+					Id:        11,
+					Type:      domain.EventPostCreated,
+					Post:      domain.Post{Id: ksuid.New(), AuthorId: ksuid.New(), Text: "text"},
+					CreatedAt: time.Now(),
+				},
+			},
+			mockBehavior: func(args args, want []domain.PostEvent) {
+				rows := mock.NewRows([]string{"position", "type", "post_id", "author_id", "text", "created_at"}).AddRow(
+					want[0].Id, want[0].Type, want[0].Post.Id, want[0].Post.AuthorId, want[0].Post.Text, want[0].CreatedAt,
+				)
+
+				mock.ExpectQuery("SELECT (.+) FROM post_event").
+					WithArgs(args.cursor, []string{args.authorIds[0].String()}, args.limit).
+					WillReturnRows(rows)
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args, tt.want)
+
+			// Getting post events in postgres database.
+			got, err := repos.GetEvents(context.Background(), tt.args.authorIds, tt.args.cursor, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error getting post events: %s", err.Error())
+			}
+
+			// Check for similarity of events.
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Error("error events are not similar")
+			}
+		})
+	}
+}
+
+// Testing getting the last post event cursor in postgres database.
+func TestEventRepository_GetLastCursor(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Creating a new repository.
+	repos := postgres.NewEventRepository(mock, &mockListener{})
+
+	// Tests structures.
+	tests := []struct {
+		name    string
+		want    int64
+		wantErr bool
+	}{
+		{name: "OK", want: 42},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery("SELECT (.+) FROM post_event").
+				WillReturnRows(mock.NewRows([]string{"max"}).AddRow(tt.want))
+
+			// Getting the last post event cursor in postgres database.
+			got, err := repos.GetLastCursor(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error getting last event cursor: %s", err.Error())
+			}
+
+			// Check for similarity of cursor.
+			if got != tt.want {
+				t.Error("error cursor are not similar")
+			}
+		})
+	}
+}
+
+// Testing getting the expired post event cursor in postgres database.
+func TestEventRepository_GetExpiredCursor(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Creating a new repository.
+	repos := postgres.NewEventRepository(mock, &mockListener{})
+
+	// Tests structures.
+	tests := []struct {
+		name    string
+		want    int64
+		wantErr bool
+	}{
+		{name: "OK", want: 7},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery("SELECT position FROM post_event_retention").
+				WillReturnRows(mock.NewRows([]string{"position"}).AddRow(tt.want))
+
+			// Getting the expired post event cursor in postgres database.
+			got, err := repos.GetExpiredCursor(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error getting expired event cursor: %s", err.Error())
+			}
+
+			// Check for similarity of cursor.
+			if got != tt.want {
+				t.Error("error cursor are not similar")
+			}
+		})
+	}
+}
+
+// Testing deleting post events in postgres database.
+func TestEventRepository_DeleteEvents(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Creating a new repository.
+	repos := postgres.NewEventRepository(mock, &mockListener{})
+
+	// Tests structures.
+	tests := []struct {
+		name    string
+		before  time.Time
+		wantErr bool
+	}{
+		{name: "OK", before: time.Now()},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectExec(`WITH deleted AS \(DELETE FROM post_event (.+) UPDATE post_event_retention`).
+				WithArgs(tt.before).
+				WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+			// Deleting post events in postgres database.
+			if err := repos.DeleteEvents(context.Background(), tt.before); (err != nil) != tt.wantErr {
+				t.Errorf("error deleting post events: %s", err.Error())
+			}
+		})
+	}
+}
+
+// Testing positioning committed post events in postgres database.
+func TestEventRepository_PositionEvents(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Creating a new repository.
+	repos := postgres.NewEventRepository(mock, &mockListener{})
+
+	// Tests structures.
+	tests := []struct {
+		name    string
+		limit   int32
+		want    int
+		wantErr bool
+	}{
+		{name: "OK", limit: 10, want: 3},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec("SELECT pg_advisory_xact_lock").
+				WillReturnResult(pgxmock.NewResult("SELECT", 1))
+			mock.ExpectExec(`UPDATE post_event SET position = nextval\('post_event_position_seq'\) (.+) LIMIT`).
+				WithArgs(tt.limit).
+				WillReturnResult(pgxmock.NewResult("UPDATE", int64(tt.want)))
+			mock.ExpectCommit()
+
+			// Positioning committed post events in postgres database.
+			got, err := repos.PositionEvents(context.Background(), tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error positioning post events: %s", err.Error())
+			}
+
+			// Check for positioned events.
+			if got != tt.want {
+				t.Errorf("error positioned events: got %d, want %d", got, tt.want)
+			}
+
+			// Check for all expectations.
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("error expectations were not met: %s", err.Error())
+			}
+		})
+	}
+}
+
+// Testing listening for new post events in postgres database.
+func TestEventRepository_Listen(t *testing.T) {
+	// Creating a new repository.
+	repos := postgres.NewEventRepository(nil, &mockListener{payloads: []string{"", "1", "2"}})
+
+	var calls int
+
+	// Listening for new post events.
+	if err := repos.Listen(context.Background(), func() { calls++ }); err != nil {
+		t.Errorf("error listening post events: %s", err.Error())
+	}
+
+	// Check number of handler calls.
+	if calls != 3 {
+		t.Errorf("error handler calls %d are not 3", calls)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres
+
+import (
+	"context"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/pkg/database/postgres"
+)
+
+// Fingerprint repository interface.
+type Fingerprint interface {
+	// Getting posts without a content fingerprint in postgres database.
+	GetUnfingerprinted(ctx context.Context, limit int32) ([]domain.Post, error)
+	// Setting missing post content fingerprints in postgres database.
+	SetFingerprints(ctx context.Context, posts []domain.Post) error
+}
+
+// Fingerprint repository structure.
+type FingerprintRepository struct{ psql postgres.Postgres }
+
+// Creating a new fingerprint repository.
+func NewFingerprintRepository(psql postgres.Postgres) *FingerprintRepository {
+	return &FingerprintRepository{psql: psql}
+}
+
+// Getting posts without a content fingerprint in postgres database.
+//
+// Posts created before the fingerprint migration are found with a partial
+// index, so the query is cheap once every post is fingerprinted.
+func (r *FingerprintRepository) GetUnfingerprinted(ctx context.Context, limit int32) ([]domain.Post, error) {
+	// Query for getting posts without a content fingerprint.
+	query := "SELECT id, text FROM post WHERE hash IS NULL LIMIT $1"
+
+	rows, err := r.psql.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	posts := make([]domain.Post, 0, limit)
+
+	// Scanning query rows.
+	for rows.Next() {
+		var post domain.Post
+
+		// Scanning query row.
+		if err := rows.Scan(&post.Id, &post.Text); err != nil {
+			return nil, err
+		}
+
+		posts = append(posts, post)
+	}
+
+	// Check is rows error.
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// Setting missing post content fingerprints in postgres database.
+//
+// Fingerprints set by a concurrent post update are kept.
+func (r *FingerprintRepository) SetFingerprints(ctx context.Context, posts []domain.Post) error {
+	// Begin a fingerprints transaction.
+	tx, err := r.psql.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Query for setting a missing post content fingerprint.
+	query := "UPDATE post SET hash=$2, simhash=$3 WHERE id=$1 AND hash IS NULL"
+
+	for _, post := range posts {
+		if _, err := tx.Exec(ctx, query, postgres.KSUIDArg(post.Id), post.Fingerprint.Hash,
+			int64(post.Fingerprint.SimHash)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/repository/postgres"
+	database "github.com/durudex/durudex-post-service/pkg/database/postgres"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/segmentio/ksuid"
+)
+
+// Testing getting posts without a content fingerprint in postgres database.
+func TestFingerprintRepository_GetUnfingerprinted(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Creating a new repository.
+	repos := postgres.NewFingerprintRepository(mock)
+
+	want := []domain.Post{{Id: ksuid.New(), Text: "Hello, World!"}}
+
+	mock.ExpectQuery("SELECT id, text FROM post WHERE hash IS NULL LIMIT").
+		WithArgs(int32(10)).
+		WillReturnRows(mock.NewRows([]string{"id", "text"}).AddRow(want[0].Id, want[0].Text))
+
+	// Getting posts without a content fingerprint in postgres database.
+	got, err := repos.GetUnfingerprinted(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("error getting posts: %s", err.Error())
+	}
+
+	// Check for similarity of posts.
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("error posts are not similar: got %+v, want %+v", got, want)
+	}
+}
+
+// Testing setting missing post content fingerprints in postgres database.
+func TestFingerprintRepository_SetFingerprints(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Creating a new repository.
+	repos := postgres.NewFingerprintRepository(mock)
+
+	post := domain.Post{Id: ksuid.New(), Fingerprint: domain.NewFingerprint("Hello, World!")}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE post SET hash=(.+) WHERE id=(.+) AND hash IS NULL").
+		WithArgs(database.KSUIDArg(post.Id), post.Fingerprint.Hash, int64(post.Fingerprint.SimHash)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectCommit()
+
+	// Setting missing post content fingerprints in postgres database.
+	if err := repos.SetFingerprints(context.Background(), []domain.Post{post}); err != nil {
+		t.Errorf("error setting fingerprints: %s", err.Error())
+	}
+
+	// Check for all expectations.
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("error expectations were not met: %s", err.Error())
+	}
+}
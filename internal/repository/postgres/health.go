@@ -0,0 +1,43 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres
+
+import (
+	"context"
+
+	"github.com/durudex/durudex-post-service/pkg/database/postgres"
+)
+
+// Health repository interface.
+type Health interface {
+	// Pinging postgres database.
+	Ping(ctx context.Context) error
+}
+
+// Health repository structure.
+type HealthRepository struct{ psql postgres.Postgres }
+
+// Creating a new health repository.
+func NewHealthRepository(psql postgres.Postgres) *HealthRepository {
+	return &HealthRepository{psql: psql}
+}
+
+// Pinging postgres database.
+func (r *HealthRepository) Ping(ctx context.Context) error {
+	return r.psql.Ping(ctx)
+}
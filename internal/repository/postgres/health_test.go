@@ -0,0 +1,74 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/internal/repository/postgres"
+
+	"github.com/pashagolub/pgxmock"
+)
+
+// Testing pinging postgres database.
+func TestHealthRepository_Ping(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn(pgxmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Test behavior.
+	type mockBehavior func()
+
+	// Creating a new repository.
+	repos := postgres.NewHealthRepository(mock)
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		wantErr      bool
+		mockBehavior mockBehavior
+	}{
+		{
+			name:         "OK",
+			mockBehavior: func() { mock.ExpectPing() },
+		},
+		{
+			name:         "Error",
+			wantErr:      true,
+			mockBehavior: func() { mock.ExpectPing().WillReturnError(errors.New("connection refused")) },
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			// Pinging postgres database.
+			err := repos.Ping(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error pinging postgres: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/metrics"
+
+	"github.com/segmentio/ksuid"
+)
+
+// Observing query latency since the start time.
+func observeQuery(query string, start time.Time) {
+	metrics.QueryDuration.WithLabelValues(query).Observe(time.Since(start).Seconds())
+}
+
+// Post repository metrics structure.
+//
+// Records latency of every post repository query.
+type PostMetrics struct{ repos Post }
+
+// Creating a new post repository metrics.
+func NewPostMetrics(repos Post) *PostMetrics {
+	return &PostMetrics{repos: repos}
+}
+
+// Creating a new post in postgres database.
+func (m *PostMetrics) Create(ctx context.Context, post domain.Post) error {
+	defer observeQuery("post_create", time.Now())
+	return m.repos.Create(ctx, post)
+}
+
+// Getting a post fields by id in postgres database.
+func (m *PostMetrics) Get(ctx context.Context, id ksuid.KSUID, mask domain.FieldMask) (domain.Post, error) {
+	defer observeQuery("post_get", time.Now())
+	return m.repos.Get(ctx, id, mask)
+}
+
+// Getting posts by ids in postgres database.
+func (m *PostMetrics) GetByIds(ctx context.Context, ids []ksuid.KSUID) ([]domain.Post, error) {
+	defer observeQuery("post_get_by_ids", time.Now())
+	return m.repos.GetByIds(ctx, ids)
+}
+
+// Getting author posts fields by author id in postgres database.
+func (m *PostMetrics) GetPosts(ctx context.Context, authorId ksuid.KSUID, sort domain.SortOptions, filter domain.PostFilter, mask domain.FieldMask) ([]domain.Post, error) {
+	defer observeQuery("post_get_posts", time.Now())
+	return m.repos.GetPosts(ctx, authorId, sort, filter, mask)
+}
+
+// Deleting a post in postgres database.
+func (m *PostMetrics) Delete(ctx context.Context, id, authorId ksuid.KSUID) error {
+	defer observeQuery("post_delete", time.Now())
+	return m.repos.Delete(ctx, id, authorId)
+}
+
+// Updating a post fields in postgres database.
+func (m *PostMetrics) Update(ctx context.Context, post domain.Post, mask domain.FieldMask) error {
+	defer observeQuery("post_update", time.Now())
+	return m.repos.Update(ctx, post, mask)
+}
+
+// Getting total author posts count in postgres database.
+func (m *PostMetrics) GetTotalCount(ctx context.Context, authorId ksuid.KSUID, filter domain.PostFilter) (int32, error) {
+	defer observeQuery("post_get_total_count", time.Now())
+	return m.repos.GetTotalCount(ctx, authorId, filter)
+}
+
+// Finding posts similar to the fingerprint in postgres database.
+func (m *PostMetrics) FindSimilar(ctx context.Context, fingerprint domain.Fingerprint, opts domain.SimilarOptions) ([]domain.Post, error) {
+	defer observeQuery("post_find_similar", time.Now())
+	return m.repos.FindSimilar(ctx, fingerprint, opts)
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/metrics"
+	"github.com/durudex/durudex-post-service/internal/repository/postgres"
+	mock_postgres "github.com/durudex/durudex-post-service/internal/repository/postgres/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/segmentio/ksuid"
+)
+
+// Testing recording post repository query latency.
+func TestPostMetrics_Get(t *testing.T) {
+	// Creating a new mock controller.
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// Creating a new mock repository.
+	psql := mock_postgres.NewMockPost(c)
+
+	id := ksuid.New()
+	want := domain.Post{AuthorId: ksuid.New(), Text: "text"}
+
+	psql.EXPECT().Get(context.Background(), id, nil).Return(want, nil)
+
+	// Creating a new post repository metrics.
+	repos := postgres.NewPostMetrics(psql)
+
+	before := testutil.CollectAndCount(metrics.QueryDuration)
+
+	// Getting a post by id.
+	got, err := repos.Get(context.Background(), id, nil)
+	if err != nil {
+		t.Errorf("error getting post by id: %s", err.Error())
+	}
+
+	// Check for similarity of post.
+	if !reflect.DeepEqual(got, want) {
+		t.Error("error post are not similar")
+	}
+
+	// Check for observed query series.
+	if after := testutil.CollectAndCount(metrics.QueryDuration); after != before+1 {
+		t.Errorf("error query latency was not observed: %d series", after)
+	}
+}
@@ -64,61 +64,91 @@ func (mr *MockPostMockRecorder) Delete(ctx, id, authorId interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPost)(nil).Delete), ctx, id, authorId)
 }
 
+// FindSimilar mocks base method.
+func (m *MockPost) FindSimilar(ctx context.Context, fingerprint domain.Fingerprint, opts domain.SimilarOptions) ([]domain.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSimilar", ctx, fingerprint, opts)
+	ret0, _ := ret[0].([]domain.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSimilar indicates an expected call of FindSimilar.
+func (mr *MockPostMockRecorder) FindSimilar(ctx, fingerprint, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSimilar", reflect.TypeOf((*MockPost)(nil).FindSimilar), ctx, fingerprint, opts)
+}
+
 // Get mocks base method.
-func (m *MockPost) Get(ctx context.Context, id ksuid.KSUID) (domain.Post, error) {
+func (m *MockPost) Get(ctx context.Context, id ksuid.KSUID, mask domain.FieldMask) (domain.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret := m.ctrl.Call(m, "Get", ctx, id, mask)
 	ret0, _ := ret[0].(domain.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockPostMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+func (mr *MockPostMockRecorder) Get(ctx, id, mask interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPost)(nil).Get), ctx, id, mask)
+}
+
+// GetByIds mocks base method.
+func (m *MockPost) GetByIds(ctx context.Context, ids []ksuid.KSUID) ([]domain.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIds", ctx, ids)
+	ret0, _ := ret[0].([]domain.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIds indicates an expected call of GetByIds.
+func (mr *MockPostMockRecorder) GetByIds(ctx, ids interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPost)(nil).Get), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIds", reflect.TypeOf((*MockPost)(nil).GetByIds), ctx, ids)
 }
 
 // GetPosts mocks base method.
-func (m *MockPost) GetPosts(ctx context.Context, authorId ksuid.KSUID, sort domain.SortOptions) ([]domain.Post, error) {
+func (m *MockPost) GetPosts(ctx context.Context, authorId ksuid.KSUID, sort domain.SortOptions, filter domain.PostFilter, mask domain.FieldMask) ([]domain.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPosts", ctx, authorId, sort)
+	ret := m.ctrl.Call(m, "GetPosts", ctx, authorId, sort, filter, mask)
 	ret0, _ := ret[0].([]domain.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPosts indicates an expected call of GetPosts.
-func (mr *MockPostMockRecorder) GetPosts(ctx, authorId, sort interface{}) *gomock.Call {
+func (mr *MockPostMockRecorder) GetPosts(ctx, authorId, sort, filter, mask interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockPost)(nil).GetPosts), ctx, authorId, sort)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockPost)(nil).GetPosts), ctx, authorId, sort, filter, mask)
 }
 
 // GetTotalCount mocks base method.
-func (m *MockPost) GetTotalCount(ctx context.Context, authorId ksuid.KSUID) (int32, error) {
+func (m *MockPost) GetTotalCount(ctx context.Context, authorId ksuid.KSUID, filter domain.PostFilter) (int32, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTotalCount", ctx, authorId)
+	ret := m.ctrl.Call(m, "GetTotalCount", ctx, authorId, filter)
 	ret0, _ := ret[0].(int32)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTotalCount indicates an expected call of GetTotalCount.
-func (mr *MockPostMockRecorder) GetTotalCount(ctx, authorId interface{}) *gomock.Call {
+func (mr *MockPostMockRecorder) GetTotalCount(ctx, authorId, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalCount", reflect.TypeOf((*MockPost)(nil).GetTotalCount), ctx, authorId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalCount", reflect.TypeOf((*MockPost)(nil).GetTotalCount), ctx, authorId, filter)
 }
 
 // Update mocks base method.
-func (m *MockPost) Update(ctx context.Context, post domain.Post) error {
+func (m *MockPost) Update(ctx context.Context, post domain.Post, mask domain.FieldMask) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, post)
+	ret := m.ctrl.Call(m, "Update", ctx, post, mask)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockPostMockRecorder) Update(ctx, post interface{}) *gomock.Call {
+func (mr *MockPostMockRecorder) Update(ctx, post, mask interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPost)(nil).Update), ctx, post)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPost)(nil).Update), ctx, post, mask)
 }
@@ -0,0 +1,247 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/pkg/database/postgres"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/segmentio/ksuid"
+)
+
+// Advisory lock key that allows only one outbox relay at a time.
+const outboxLockKey int64 = 0x706f73745f6f7574
+
+// Outbox repository interface.
+type Outbox interface {
+	// Relaying pending outbox events to the publish function in postgres database.
+	RelayOutbox(ctx context.Context, limit, maxAttempts int32, lease time.Duration, publish func(context.Context, domain.PostEvent) error) (int, error)
+}
+
+// Outbox repository structure.
+type OutboxRepository struct{ psql postgres.Postgres }
+
+// Creating a new outbox repository.
+func NewOutboxRepository(psql postgres.Postgres) *OutboxRepository {
+	return &OutboxRepository{psql: psql}
+}
+
+// Outbox event structure.
+type outboxEvent struct {
+	event    domain.PostEvent
+	attempts int32
+}
+
+// Relaying pending outbox events to the publish function in postgres database.
+//
+// Events are claimed for the lease in a short transaction and published after it
+// is committed. Published events are deleted, failed events are retried on the
+// next relay and moved to the dead letter table after the maximum number of
+// attempts. Events of a post that failed are not published until the failed event
+// is relayed, and events of a post with a dead letter are parked until the dead
+// letter is deleted, so the order of events is kept per post. Returns the number
+// of relayed events.
+func (r *OutboxRepository) RelayOutbox(ctx context.Context, limit, maxAttempts int32, lease time.Duration, publish func(context.Context, domain.PostEvent) error) (int, error) {
+	// Claiming pending outbox events.
+	events, err := r.claimOutboxEvents(ctx, limit, lease)
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	var (
+		published, failed, released []int64
+		dead                        []outboxEvent
+		reasons                     []string
+	)
+
+	blocked := make(map[ksuid.KSUID]struct{})
+
+	for _, e := range events {
+		// Keep the order of post events.
+		if _, ok := blocked[e.event.Post.Id]; ok {
+			released = append(released, e.event.Id)
+			continue
+		}
+
+		// Publishing an outbox event.
+		err := publish(ctx, e.event)
+		if err == nil {
+			published = append(published, e.event.Id)
+			continue
+		}
+
+		blocked[e.event.Post.Id] = struct{}{}
+
+		// Move an event to the dead letter table.
+		if e.attempts+1 >= maxAttempts {
+			dead, reasons = append(dead, e), append(reasons, err.Error())
+			continue
+		}
+
+		failed = append(failed, e.event.Id)
+	}
+
+	// Begin an outbox transaction.
+	tx, err := r.psql.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Deleting published outbox events.
+	if len(published) != 0 {
+		query := "DELETE FROM post_outbox WHERE id = ANY($1)"
+
+		if _, err := tx.Exec(ctx, query, published); err != nil {
+			return 0, err
+		}
+	}
+
+	// Incrementing failed outbox event attempts.
+	if len(failed) != 0 {
+		query := "UPDATE post_outbox SET attempts=attempts+1, locked_until=NULL WHERE id = ANY($1)"
+
+		if _, err := tx.Exec(ctx, query, failed); err != nil {
+			return 0, err
+		}
+	}
+
+	// Releasing blocked outbox events.
+	if len(released) != 0 {
+		query := "UPDATE post_outbox SET locked_until=NULL WHERE id = ANY($1)"
+
+		if _, err := tx.Exec(ctx, query, released); err != nil {
+			return 0, err
+		}
+	}
+
+	for i, e := range dead {
+		if err := deadLetterOutboxEvent(ctx, tx, e.event.Id, reasons[i]); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return len(published) + len(dead), nil
+}
+
+// Claiming pending outbox events for the lease in postgres database.
+func (r *OutboxRepository) claimOutboxEvents(ctx context.Context, limit int32, lease time.Duration) ([]outboxEvent, error) {
+	// Begin a claim transaction.
+	tx, err := r.psql.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+
+	// Check is another relay claiming, so events of a post are claimed by one relay.
+	if err := tx.QueryRow(ctx, "SELECT pg_try_advisory_xact_lock($1)", outboxLockKey).Scan(&locked); err != nil {
+		return nil, err
+	}
+
+	if !locked {
+		return nil, nil
+	}
+
+	// Getting pending outbox events.
+	events, err := getOutboxEvents(ctx, tx, limit, lease)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Writing a post event to the outbox in the transaction.
+func insertOutboxEvent(ctx context.Context, tx pgx.Tx, eventType domain.EventType, post domain.Post) error {
+	// Query for creating an outbox event.
+	query := "INSERT INTO post_outbox (type, post_id, author_id, text) VALUES ($1, $2, $3, $4)"
+	_, err := tx.Exec(ctx, query, eventType, post.Id, post.AuthorId, post.Text)
+
+	return err
+}
+
+// Getting and leasing pending outbox events in the transaction.
+func getOutboxEvents(ctx context.Context, tx pgx.Tx, limit int32, lease time.Duration) ([]outboxEvent, error) {
+	// Query for leasing pending outbox events, skipping events of posts with an
+	// earlier leased event or a dead letter.
+	query := `WITH claimed AS (SELECT o.id FROM post_outbox o
+			WHERE (o.locked_until IS NULL OR o.locked_until < now())
+				AND NOT EXISTS (SELECT 1 FROM post_outbox p
+					WHERE p.post_id = o.post_id AND p.id < o.id AND p.locked_until >= now())
+				AND NOT EXISTS (SELECT 1 FROM post_outbox_dead d WHERE d.post_id = o.post_id)
+			ORDER BY o.id ASC LIMIT $1 FOR UPDATE SKIP LOCKED)
+		UPDATE post_outbox o SET locked_until = now() + $2::interval FROM claimed WHERE o.id = claimed.id
+		RETURNING o.id, o.type, o.post_id, o.author_id, o.text, o.attempts, o.created_at`
+
+	rows, err := tx.Query(ctx, query, limit, lease)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]outboxEvent, 0, limit)
+
+	// Scanning query rows.
+	for rows.Next() {
+		var e outboxEvent
+
+		// Scanning query row.
+		if err := rows.Scan(&e.event.Id, &e.event.Type, &e.event.Post.Id, &e.event.Post.AuthorId,
+			&e.event.Post.Text, &e.attempts, &e.event.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		events = append(events, e)
+	}
+
+	// Check is rows error.
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Returned rows are not ordered.
+	sort.Slice(events, func(i, j int) bool { return events[i].event.Id < events[j].event.Id })
+
+	return events, nil
+}
+
+// Moving an outbox event to the dead letter table in the transaction.
+func deadLetterOutboxEvent(ctx context.Context, tx pgx.Tx, id int64, reason string) error {
+	// Query for moving an outbox event.
+	query := `WITH event AS (DELETE FROM post_outbox WHERE id=$1 RETURNING *)
+		INSERT INTO post_outbox_dead (id, type, post_id, author_id, text, attempts, error, created_at)
+		SELECT id, type, post_id, author_id, text, attempts+1, $2, created_at FROM event`
+	_, err := tx.Exec(ctx, query, id, reason)
+
+	return err
+}
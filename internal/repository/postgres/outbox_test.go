@@ -0,0 +1,180 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/repository/postgres"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/segmentio/ksuid"
+)
+
+// Testing relaying pending outbox events in postgres database.
+func TestOutboxRepository_RelayOutbox(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Testing args.
+	type args struct {
+		limit, maxAttempts int32
+		lease              time.Duration
+		failed             map[int64]bool
+	}
+
+	// Outbox event with attempts.
+	type outboxEvent struct {
+		event    domain.PostEvent
+		attempts int32
+	}
+
+	// Test behavior.
+	type mockBehavior func(args args, events []outboxEvent)
+
+	// Creating a new repository.
+	repos := postgres.NewOutboxRepository(mock)
+
+	first, second := ksuid.New(), ksuid.New()
+
+	// Tests structures.
+	tests := []struct {
+		name          string
+		args          args
+		events        []outboxEvent
+		wantPublished []int64
+		want          int
+		wantErr       bool
+		mockBehavior  mockBehavior
+	}{
+		{
+			name: "OK",
+			args: args{limit: 10, maxAttempts: 3, lease: time.Minute, failed: map[int64]bool{1: true, 4: true}},
+			events: []outboxEvent{
+				{event: domain.PostEvent{Id: 1, Type: domain.EventPostCreated, Post: domain.Post{Id: first}}},
+				{event: domain.PostEvent{Id: 2, Type: domain.EventPostUpdated, Post: domain.Post{Id: first}}},
+				{event: domain.PostEvent{Id: 3, Type: domain.EventPostCreated, Post: domain.Post{Id: second}}},
+				{event: domain.PostEvent{Id: 4, Type: domain.EventPostUpdated, Post: domain.Post{Id: second}}, attempts: 2},
+				{event: domain.PostEvent{Id: 5, Type: domain.EventPostDeleted, Post: domain.Post{Id: second}}},
+			},
+			wantPublished: []int64{3},
+			want:          2,
+			mockBehavior: func(args args, events []outboxEvent) {
+				rows := mock.NewRows([]string{"id", "type", "post_id", "author_id", "text", "attempts", "created_at"})
+
+				// Claimed rows are returned in any order.
+				for i := len(events) - 1; i >= 0; i-- {
+					e := events[i]
+
+					rows.AddRow(e.event.Id, e.event.Type, e.event.Post.Id, e.event.Post.AuthorId,
+						e.event.Post.Text, e.attempts, e.event.CreatedAt)
+				}
+
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT pg_try_advisory_xact_lock").
+					WillReturnRows(mock.NewRows([]string{"locked"}).AddRow(true))
+				mock.ExpectQuery(`WITH claimed AS \(SELECT (.+) FROM post_outbox`).
+					WithArgs(args.limit, args.lease).
+					WillReturnRows(rows)
+				mock.ExpectCommit()
+
+				// Completing events after publishing.
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM post_outbox").
+					WithArgs([]int64{3}).
+					WillReturnResult(pgxmock.NewResult("DELETE", 1))
+				mock.ExpectExec("UPDATE post_outbox SET attempts").
+					WithArgs([]int64{1}).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mock.ExpectExec("UPDATE post_outbox SET locked_until").
+					WithArgs([]int64{2, 5}).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+				mock.ExpectExec("INSERT INTO post_outbox_dead").
+					WithArgs(int64(4), "publish failed").
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Empty",
+			args: args{limit: 10, maxAttempts: 3, lease: time.Minute},
+			mockBehavior: func(args args, events []outboxEvent) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT pg_try_advisory_xact_lock").
+					WillReturnRows(mock.NewRows([]string{"locked"}).AddRow(true))
+				mock.ExpectQuery(`WITH claimed AS \(SELECT (.+) FROM post_outbox`).
+					WithArgs(args.limit, args.lease).
+					WillReturnRows(mock.NewRows([]string{"id", "type", "post_id", "author_id", "text", "attempts", "created_at"}))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Locked",
+			args: args{limit: 10, maxAttempts: 3, lease: time.Minute},
+			mockBehavior: func(args args, events []outboxEvent) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT pg_try_advisory_xact_lock").
+					WillReturnRows(mock.NewRows([]string{"locked"}).AddRow(false))
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args, tt.events)
+
+			var published []int64
+
+			// Relaying pending outbox events in postgres database.
+			got, err := repos.RelayOutbox(context.Background(), tt.args.limit, tt.args.maxAttempts, tt.args.lease,
+				func(_ context.Context, event domain.PostEvent) error {
+					if tt.args.failed[event.Id] {
+						return errors.New("publish failed")
+					}
+
+					published = append(published, event.Id)
+
+					return nil
+				})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error relaying outbox events: %s", err.Error())
+			}
+
+			// Check for similarity of relayed events.
+			if got != tt.want || !reflect.DeepEqual(published, tt.wantPublished) {
+				t.Error("error relayed events are not similar")
+			}
+
+			// Check for all expectations.
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("error expectations were not met: %s", err.Error())
+			}
+		})
+	}
+}
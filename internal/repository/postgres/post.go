@@ -20,6 +20,8 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/durudex/durudex-post-service/internal/domain"
 	"github.com/durudex/durudex-post-service/pkg/database/postgres"
@@ -33,16 +35,68 @@ import (
 type Post interface {
 	// Creating a new post in postgres database.
 	Create(ctx context.Context, post domain.Post) error
-	// Getting a post by id in postgres database.
-	Get(ctx context.Context, id ksuid.KSUID) (domain.Post, error)
-	// Getting author posts by author id in postgres database.
-	GetPosts(ctx context.Context, authorId ksuid.KSUID, sort domain.SortOptions) ([]domain.Post, error)
+	// Getting a post fields by id in postgres database.
+	Get(ctx context.Context, id ksuid.KSUID, mask domain.FieldMask) (domain.Post, error)
+	// Getting posts by ids in postgres database.
+	GetByIds(ctx context.Context, ids []ksuid.KSUID) ([]domain.Post, error)
+	// Getting author posts fields by author id in postgres database.
+	GetPosts(ctx context.Context, authorId ksuid.KSUID, sort domain.SortOptions, filter domain.PostFilter, mask domain.FieldMask) ([]domain.Post, error)
 	// Deleting a post in postgres database.
 	Delete(ctx context.Context, id, authorId ksuid.KSUID) error
-	// Updating a post in postgres database.
-	Update(ctx context.Context, post domain.Post) error
+	// Updating a post fields in postgres database.
+	Update(ctx context.Context, post domain.Post, mask domain.FieldMask) error
 	// Getting total author posts count in postgres database.
-	GetTotalCount(ctx context.Context, authorId ksuid.KSUID) (int32, error)
+	GetTotalCount(ctx context.Context, authorId ksuid.KSUID, filter domain.PostFilter) (int32, error)
+	// Finding posts similar to the fingerprint in postgres database.
+	FindSimilar(ctx context.Context, fingerprint domain.Fingerprint, opts domain.SimilarOptions) ([]domain.Post, error)
+}
+
+// Maximum preallocated number of read author posts.
+const maxPostsCapacity = 100
+
+// Default fields of a read post.
+var defaultPostFields = []string{domain.PostFieldAuthorId, domain.PostFieldText, domain.PostFieldCreatedAt,
+	domain.PostFieldUpdatedAt}
+
+// Default fields of read author posts.
+var defaultPostsFields = []string{domain.PostFieldId, domain.PostFieldText, domain.PostFieldFlagged,
+	domain.PostFieldCreatedAt, domain.PostFieldUpdatedAt}
+
+// Post scan targets by field name, field names are the column names.
+var postTargets = map[string]func(post *domain.Post) interface{}{
+	domain.PostFieldId:        func(post *domain.Post) interface{} { return &post.Id },
+	domain.PostFieldAuthorId:  func(post *domain.Post) interface{} { return &post.AuthorId },
+	domain.PostFieldText:      func(post *domain.Post) interface{} { return &post.Text },
+	domain.PostFieldFlagged:   func(post *domain.Post) interface{} { return &post.Flagged },
+	domain.PostFieldCreatedAt: func(post *domain.Post) interface{} { return &post.CreatedAt },
+	domain.PostFieldUpdatedAt: func(post *domain.Post) interface{} { return &post.UpdatedAt },
+}
+
+// Getting the read post fields of the mask or the default fields.
+func readFields(mask domain.FieldMask, defaults []string) ([]string, error) {
+	if len(mask) == 0 {
+		return defaults, nil
+	}
+
+	// Check is fields known.
+	for _, field := range mask {
+		if _, ok := postTargets[field]; !ok {
+			return nil, fmt.Errorf("unknown post field: %s", field)
+		}
+	}
+
+	return mask, nil
+}
+
+// Getting post scan targets of the fields.
+func scanTargets(post *domain.Post, fields []string) []interface{} {
+	targets := make([]interface{}, len(fields))
+
+	for i, field := range fields {
+		targets[i] = postTargets[field](post)
+	}
+
+	return targets
 }
 
 // Post repository structure.
@@ -55,30 +109,58 @@ func NewPostRepository(psql postgres.Postgres) *PostRepository {
 
 // Creating a new post in postgres database.
 func (r *PostRepository) Create(ctx context.Context, post domain.Post) error {
+	// Begin a post transaction.
+	tx, err := r.psql.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	// Query to create post.
-	query := "INSERT INTO post (id, author_id, text) VALUES ($1, $2, $3)"
+	query := `INSERT INTO post (id, author_id, text, hash, simhash, flagged, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
 
 	// Scan post id.
-	if _, err := r.psql.Exec(ctx, query, post.Id, post.AuthorId, post.Text); err != nil {
+	if _, err := tx.Exec(ctx, query, postgres.KSUIDArg(post.Id), postgres.KSUIDArg(post.AuthorId), post.Text, post.Fingerprint.Hash,
+		int64(post.Fingerprint.SimHash), post.Flagged, post.CreatedAt); err != nil {
 		return err
 	}
 
-	return nil
+	// Incrementing author posts count.
+	if err := addAuthorPosts(ctx, tx, post.AuthorId, 1); err != nil {
+		return err
+	}
+
+	// Writing a post created event.
+	if err := insertOutboxEvent(ctx, tx, domain.EventPostCreated, post); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-// Getting a post by id in postgres database.
-func (r *PostRepository) Get(ctx context.Context, id ksuid.KSUID) (domain.Post, error) {
+// Getting a post fields by id in postgres database.
+func (r *PostRepository) Get(ctx context.Context, id ksuid.KSUID, mask domain.FieldMask) (domain.Post, error) {
 	var post domain.Post
 
+	fields, err := readFields(mask, defaultPostFields)
+	if err != nil {
+		return domain.Post{}, err
+	}
+
 	// Query for get post by id.
-	query := "SELECT author_id, text, updated_at FROM post WHERE id=$1"
+	query := "SELECT " + strings.Join(fields, ", ") + " FROM post WHERE id=$1"
 
-	row := r.psql.QueryRow(ctx, query, id)
+	row := r.psql.QueryRow(ctx, query, postgres.KSUIDArg(id))
 
 	// Scanning query row.
-	if err := row.Scan(&post.AuthorId, &post.Text, &post.UpdatedAt); err != nil {
+	if err := row.Scan(scanTargets(&post, fields)...); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return domain.Post{}, &domain.Error{Code: domain.CodeNotFound, Message: "Post not found"}
+			return domain.Post{}, &domain.Error{
+				Code:    domain.CodeNotFound,
+				Message: "Post not found",
+				Reason:  domain.ReasonPostNotFound,
+			}
 		}
 
 		return domain.Post{}, &domain.Error{Code: domain.CodeInternal, Message: "Internal Server Error"}
@@ -87,11 +169,57 @@ func (r *PostRepository) Get(ctx context.Context, id ksuid.KSUID) (domain.Post,
 	return post, nil
 }
 
-// Getting author posts by author id in postgres database.
-func (r *PostRepository) GetPosts(ctx context.Context, authorId ksuid.KSUID, sort domain.SortOptions) ([]domain.Post, error) {
+// Getting posts by ids in postgres database.
+//
+// Posts are returned in no particular order, missing posts are skipped.
+func (r *PostRepository) GetByIds(ctx context.Context, ids []ksuid.KSUID) ([]domain.Post, error) {
+	// Query for getting posts by ids.
+	query := "SELECT id, author_id, text, flagged, created_at, updated_at FROM post WHERE id = ANY($1)"
+
+	rows, err := r.psql.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	posts := make([]domain.Post, 0, len(ids))
+
+	// Scanning query rows.
+	for rows.Next() {
+		var post domain.Post
+
+		// Scanning query row.
+		if err := rows.Scan(&post.Id, &post.AuthorId, &post.Text, &post.Flagged, &post.CreatedAt,
+			&post.UpdatedAt); err != nil {
+			return nil, err
+		}
+
+		posts = append(posts, post)
+	}
+
+	// Check is rows error.
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// Getting author posts fields by author id in postgres database.
+func (r *PostRepository) GetPosts(ctx context.Context, authorId ksuid.KSUID, sort domain.SortOptions, filter domain.PostFilter, mask domain.FieldMask) ([]domain.Post, error) {
 	var n int32
 
-	qb := sqlf.Select("id, text, updated_at").From("post").Where("author_id = ?", authorId)
+	fields, err := readFields(mask, defaultPostsFields)
+	if err != nil {
+		return nil, err
+	}
+
+	qb := sqlf.PostgreSQL.Select(strings.Join(fields, ", ")).From("post").
+		Where("author_id = ?", postgres.KSUIDArg(authorId))
+	defer qb.Close()
+
+	// Added creation time filter.
+	whereCreated(qb, filter)
 
 	// Added first or last sort option.
 	if sort.First != nil {
@@ -104,14 +232,19 @@ func (r *PostRepository) GetPosts(ctx context.Context, authorId ksuid.KSUID, sor
 
 	// Added before sort option.
 	if sort.Before != ksuid.Nil {
-		qb.Where("(created_at, id) < (?, ?)", sort.Before.Time(), sort.Before)
+		qb.Where("(created_at, id) < (?, ?)", sort.Before.Time(), postgres.KSUIDArg(sort.Before))
 	}
 	// Added after sort option.
 	if sort.After != ksuid.Nil {
-		qb.Where("(created_at, id) > (?, ?)", sort.After.Time(), sort.After)
+		qb.Where("(created_at, id) > (?, ?)", sort.After.Time(), postgres.KSUIDArg(sort.After))
 	}
 
-	posts := make([]domain.Post, n)
+	// The page size is checked by the transports, preallocate at most a page.
+	if n < 0 || n > maxPostsCapacity {
+		n = maxPostsCapacity
+	}
+
+	posts := make([]domain.Post, 0, n)
 
 	// Query for getting author posts by author id.
 	rows, err := r.psql.Query(ctx, qb.String(), qb.Args()...)
@@ -120,20 +253,16 @@ func (r *PostRepository) GetPosts(ctx context.Context, authorId ksuid.KSUID, sor
 	}
 	defer rows.Close()
 
-	var i int
-
 	// Scanning query rows.
 	for rows.Next() {
 		var post domain.Post
 
 		// Scanning query row.
-		if err := rows.Scan(&post.Id, &post.Text, &post.UpdatedAt); err != nil {
+		if err := rows.Scan(scanTargets(&post, fields)...); err != nil {
 			return nil, err
 		}
 
-		posts[i] = post
-
-		i++
+		posts = append(posts, post)
 	}
 
 	// Check is rows error.
@@ -141,43 +270,124 @@ func (r *PostRepository) GetPosts(ctx context.Context, authorId ksuid.KSUID, sor
 		return nil, err
 	}
 
-	// Check for fullness of the slice.
-	if i == int(n) {
-		return posts, nil
-	}
-
-	res := make([]domain.Post, i)
-	copy(res, posts[:i])
-
-	return res, nil
+	return posts, nil
 }
 
 // Deleting a post in postgres database.
 func (r *PostRepository) Delete(ctx context.Context, id, authorId ksuid.KSUID) error {
+	// Begin a post transaction.
+	tx, err := r.psql.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	// Query for delete post by id.
 	query := "DELETE FROM post WHERE id=$1 AND author_id=$2"
-	_, err := r.psql.Exec(ctx, query, id, authorId)
 
-	return err
+	tag, err := tx.Exec(ctx, query, postgres.KSUIDArg(id), postgres.KSUIDArg(authorId))
+	if err != nil {
+		return err
+	}
+
+	// Check if post not found.
+	if tag.RowsAffected() == 0 {
+		return &domain.Error{Code: domain.CodeNotFound, Message: "Post not found", Reason: domain.ReasonPostNotFound}
+	}
+
+	// Decrementing author posts count.
+	if err := addAuthorPosts(ctx, tx, authorId, -1); err != nil {
+		return err
+	}
+
+	// Writing a post deleted event.
+	if err := insertOutboxEvent(ctx, tx, domain.EventPostDeleted, domain.Post{Id: id, AuthorId: authorId}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-// Updating a post in postgres database.
-func (r *PostRepository) Update(ctx context.Context, post domain.Post) error {
+// Updating a post fields in postgres database. All updatable fields are
+// updated when the mask is empty.
+func (r *PostRepository) Update(ctx context.Context, post domain.Post, mask domain.FieldMask) error {
+	qb := sqlf.PostgreSQL.Update("post")
+	defer qb.Close()
+
+	// Added updated post fields.
+	if mask.Has(domain.PostFieldText) {
+		qb.Set("text", post.Text).
+			Set("hash", post.Fingerprint.Hash).
+			Set("simhash", int64(post.Fingerprint.SimHash))
+	}
+
+	// Check is fields updatable.
+	for _, field := range mask {
+		if field != domain.PostFieldText {
+			return fmt.Errorf("post field is not updatable: %s", field)
+		}
+	}
+
+	qb.SetExpr("updated_at", "now()").Where("id = ?", postgres.KSUIDArg(post.Id)).
+		Where("author_id = ?", postgres.KSUIDArg(post.AuthorId))
+
+	// Begin a post transaction.
+	tx, err := r.psql.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	// Query for update post by id.
-	query := "UPDATE post SET text=$1, updated_at=now() WHERE id=$2 AND author_id=$3"
-	_, err := r.psql.Exec(ctx, query, post.Text, post.Id, post.AuthorId)
+	tag, err := tx.Exec(ctx, qb.String(), qb.Args()...)
+	if err != nil {
+		return err
+	}
 
-	return err
+	// Check if post not found.
+	if tag.RowsAffected() == 0 {
+		return &domain.Error{Code: domain.CodeNotFound, Message: "Post not found", Reason: domain.ReasonPostNotFound}
+	}
+
+	// Writing a post updated event.
+	if err := insertOutboxEvent(ctx, tx, domain.EventPostUpdated, post); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 // Getting total author posts count in postgres database.
-func (r *PostRepository) GetTotalCount(ctx context.Context, authorId ksuid.KSUID) (int32, error) {
+//
+// Unfiltered counts are read from the author stats maintained by post
+// transactions.
+func (r *PostRepository) GetTotalCount(ctx context.Context, authorId ksuid.KSUID, filter domain.PostFilter) (int32, error) {
 	var count int32
 
+	if filter.IsZero() {
+		// Query to get author posts count.
+		query := "SELECT posts_count FROM author_stats WHERE author_id=$1"
+
+		if err := r.psql.QueryRow(ctx, query, authorId.Bytes()).Scan(&count); err != nil {
+			// Author without stats has no posts.
+			if errors.Is(err, pgx.ErrNoRows) {
+				return 0, nil
+			}
+
+			return 0, err
+		}
+
+		return count, nil
+	}
+
 	// Query to get author posts total count.
-	query := "SELECT count(*) FROM post WHERE author_id=$1"
+	qb := sqlf.PostgreSQL.Select("count(*)").From("post").Where("author_id = ?", postgres.KSUIDArg(authorId))
+	defer qb.Close()
+
+	// Added creation time filter.
+	whereCreated(qb, filter)
 
-	row := r.psql.QueryRow(ctx, query, authorId)
+	row := r.psql.QueryRow(ctx, qb.String(), qb.Args()...)
 
 	// Scanning query row.
 	if err := row.Scan(&count); err != nil {
@@ -186,3 +396,67 @@ func (r *PostRepository) GetTotalCount(ctx context.Context, authorId ksuid.KSUID
 
 	return count, nil
 }
+
+// Adding creation time filter conditions to the query.
+func whereCreated(qb *sqlf.Stmt, filter domain.PostFilter) {
+	if !filter.CreatedAfter.IsZero() {
+		qb.Where("created_at > ?", filter.CreatedAfter)
+	}
+
+	if !filter.CreatedBefore.IsZero() {
+		qb.Where("created_at < ?", filter.CreatedBefore)
+	}
+}
+
+// Finding posts similar to the fingerprint in postgres database.
+func (r *PostRepository) FindSimilar(ctx context.Context, fingerprint domain.Fingerprint, opts domain.SimilarOptions) ([]domain.Post, error) {
+	// Posts with the same content hash or close SimHash.
+	qb := sqlf.PostgreSQL.Select("id, author_id, text, flagged, created_at, updated_at").From("post").
+		Where("(hash = ? OR length(replace((simhash # ?)::bit(64)::text, '0', '')) <= ?)",
+			fingerprint.Hash, int64(fingerprint.SimHash), opts.Distance)
+	defer qb.Close()
+
+	// Added author filter.
+	if !opts.AuthorId.IsNil() {
+		qb.Where("author_id = ?", postgres.KSUIDArg(opts.AuthorId))
+	}
+	// Added excluded post filter.
+	if !opts.Exclude.IsNil() {
+		qb.Where("id <> ?", postgres.KSUIDArg(opts.Exclude))
+	}
+	// Added creation time filter.
+	if !opts.Since.IsZero() {
+		qb.Where("created_at > ?", opts.Since)
+	}
+
+	qb.OrderBy("created_at DESC, id DESC").Limit(opts.Limit)
+
+	// Query for finding similar posts.
+	rows, err := r.psql.Query(ctx, qb.String(), qb.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	posts := make([]domain.Post, 0, opts.Limit)
+
+	// Scanning query rows.
+	for rows.Next() {
+		var post domain.Post
+
+		// Scanning query row.
+		if err := rows.Scan(&post.Id, &post.AuthorId, &post.Text, &post.Flagged, &post.CreatedAt,
+			&post.UpdatedAt); err != nil {
+			return nil, err
+		}
+
+		posts = append(posts, post)
+	}
+
+	// Check is rows error.
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
@@ -19,12 +19,19 @@ package postgres_test
 
 import (
 	"context"
+	"errors"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/durudex/durudex-post-service/internal/domain"
 	"github.com/durudex/durudex-post-service/internal/repository/postgres"
+	database "github.com/durudex/durudex-post-service/pkg/database/postgres"
+	"github.com/durudex/durudex-post-service/pkg/database/postgres/postgrestest"
 
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
 	"github.com/pashagolub/pgxmock"
 	"github.com/segmentio/ksuid"
 )
@@ -56,11 +63,26 @@ func TestPostRepository_Create(t *testing.T) {
 	}{
 		{
 			name: "OK",
-			args: args{post: domain.Post{Id: ksuid.New(), AuthorId: ksuid.New(), Text: "text"}},
+			args: args{post: domain.Post{
+				Id:          ksuid.New(),
+				AuthorId:    ksuid.New(),
+				Text:        "text",
+				CreatedAt:   time.Now(),
+				Fingerprint: domain.NewFingerprint("text"),
+			}},
 			mockBehavior: func(args args) {
+				mock.ExpectBegin()
 				mock.ExpectExec("INSERT INTO post").
-					WithArgs(args.post.Id, args.post.AuthorId, args.post.Text).
+					WithArgs(database.KSUIDArg(args.post.Id), database.KSUIDArg(args.post.AuthorId), args.post.Text, args.post.Fingerprint.Hash,
+						int64(args.post.Fingerprint.SimHash), args.post.Flagged, args.post.CreatedAt).
 					WillReturnResult(pgxmock.NewResult("", 1))
+				mock.ExpectExec("INSERT INTO author_stats").
+					WithArgs(args.post.AuthorId.Bytes(), int64(1)).
+					WillReturnResult(pgxmock.NewResult("", 1))
+				mock.ExpectExec("INSERT INTO post_outbox").
+					WithArgs(domain.EventPostCreated, args.post.Id, args.post.AuthorId, args.post.Text).
+					WillReturnResult(pgxmock.NewResult("", 1))
+				mock.ExpectCommit()
 			},
 		},
 	}
@@ -89,7 +111,10 @@ func TestPostRepository_Get(t *testing.T) {
 	defer mock.Close(context.Background())
 
 	// Testing args.
-	type args struct{ id ksuid.KSUID }
+	type args struct {
+		id   ksuid.KSUID
+		mask domain.FieldMask
+	}
 
 	// Test behavior.
 	type mockBehavior func(args args, post domain.Post)
@@ -111,17 +136,36 @@ func TestPostRepository_Get(t *testing.T) {
 			want: domain.Post{
 				AuthorId:  ksuid.New(),
 				Text:      "text",
+				CreatedAt: time.Now(),
 				UpdatedAt: nil,
 			},
 			mockBehavior: func(args args, post domain.Post) {
-				rows := mock.NewRows([]string{"author_id", "text", "updated_at"}).AddRow(
-					post.AuthorId, post.Text, post.UpdatedAt)
+				rows := mock.NewRows([]string{"author_id", "text", "created_at", "updated_at"}).AddRow(
+					post.AuthorId, post.Text, post.CreatedAt, post.UpdatedAt)
+
+				mock.ExpectQuery("SELECT author_id, text, created_at, updated_at FROM post").
+					WithArgs(database.KSUIDArg(args.id)).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "Field mask",
+			args: args{id: ksuid.New(), mask: domain.FieldMask{domain.PostFieldText}},
+			want: domain.Post{Text: "text"},
+			mockBehavior: func(args args, post domain.Post) {
+				rows := mock.NewRows([]string{"text"}).AddRow(post.Text)
 
-				mock.ExpectQuery("SELECT (.+) FROM post").
-					WithArgs(args.id).
+				mock.ExpectQuery("SELECT text FROM post").
+					WithArgs(database.KSUIDArg(args.id)).
 					WillReturnRows(rows)
 			},
 		},
+		{
+			name:         "Unknown field",
+			args:         args{id: ksuid.New(), mask: domain.FieldMask{"hash"}},
+			wantErr:      true,
+			mockBehavior: func(args args, post domain.Post) {},
+		},
 	}
 
 	// Conducting tests in various structures.
@@ -130,9 +174,9 @@ func TestPostRepository_Get(t *testing.T) {
 			tt.mockBehavior(tt.args, tt.want)
 
 			// Getting a post by id in postgres database.
-			got, err := repos.Get(context.Background(), tt.args.id)
+			got, err := repos.Get(context.Background(), tt.args.id, tt.args.mask)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("error getting post by id: %s", err.Error())
+				t.Errorf("error getting post by id: %v", err)
 			}
 
 			// Check for similarity of post.
@@ -143,6 +187,76 @@ func TestPostRepository_Get(t *testing.T) {
 	}
 }
 
+// Testing getting posts by ids in postgres database.
+func TestPostRepository_GetByIds(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Testing args.
+	type args struct{ ids []ksuid.KSUID }
+
+	// Test behavior.
+	type mockBehavior func(args args, want []domain.Post)
+
+	// Creating a new repository.
+	repos := postgres.NewPostRepository(mock)
+
+	id := ksuid.New()
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		args         args
+		want         []domain.Post
+		wantErr      bool
+		mockBehavior mockBehavior
+	}{
+		{
+			name: "OK",
+			args: args{ids: []ksuid.KSUID{id, ksuid.New()}},
+			want: []domain.Post{
+				{
+					Id:        id,
+					AuthorId:  ksuid.New(),
+					Text:      "text",
+					CreatedAt: time.Now(),
+				},
+			},
+			mockBehavior: func(args args, want []domain.Post) {
+				rows := mock.NewRows([]string{"id", "author_id", "text", "flagged", "created_at", "updated_at"}).AddRow(
+					want[0].Id, want[0].AuthorId, want[0].Text, want[0].Flagged, want[0].CreatedAt, want[0].UpdatedAt,
+				)
+
+				mock.ExpectQuery("SELECT (.+) FROM post WHERE id = ANY").
+					WithArgs(args.ids).
+					WillReturnRows(rows)
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args, tt.want)
+
+			// Getting posts by ids in postgres database.
+			got, err := repos.GetByIds(context.Background(), tt.args.ids)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error getting posts by ids: %s", err.Error())
+			}
+
+			// Check for similarity of posts.
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Error("error posts are not similar")
+			}
+		})
+	}
+}
+
 // Testing getting author posts by author id in postgres database.
 func TestPostRepository_GetPosts(t *testing.T) {
 	// Creating a new mock connection.
@@ -156,6 +270,7 @@ func TestPostRepository_GetPosts(t *testing.T) {
 	type args struct {
 		authorId ksuid.KSUID
 		sort     domain.SortOptions
+		filter   domain.PostFilter
 	}
 
 	// Test behavior.
@@ -188,16 +303,36 @@ func TestPostRepository_GetPosts(t *testing.T) {
 				{
 					Id:        ksuid.New(),
 					Text:      "text",
+					CreatedAt: time.Now(),
 					UpdatedAt: nil,
 				},
 			},
 			mockBehavior: func(args args, want []domain.Post) {
-				rows := mock.NewRows([]string{"id", "text", "updated_at"}).AddRow(
-					want[0].Id, want[0].Text, want[0].UpdatedAt,
+				rows := mock.NewRows([]string{"id", "text", "flagged", "created_at", "updated_at"}).AddRow(
+					want[0].Id, want[0].Text, want[0].Flagged, want[0].CreatedAt, want[0].UpdatedAt,
 				)
 
-				mock.ExpectQuery("SELECT (.+) FROM post").
-					WithArgs(args.authorId, args.sort.Before.Time(), args.sort.Before, *args.sort.First).
+				mock.ExpectQuery(`SELECT (.+) FROM post WHERE author_id = \$1 (.+) LIMIT \$4`).
+					WithArgs(database.KSUIDArg(args.authorId), args.sort.Before.Time(), database.KSUIDArg(args.sort.Before), *args.sort.First).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "Creation time filter",
+			args: args{
+				authorId: ksuid.New(),
+				sort:     domain.SortOptions{First: &filer},
+				filter: domain.PostFilter{
+					CreatedAfter:  time.Now().Add(-time.Hour),
+					CreatedBefore: time.Now(),
+				},
+			},
+			want: []domain.Post{},
+			mockBehavior: func(args args, want []domain.Post) {
+				rows := mock.NewRows([]string{"id", "text", "flagged", "created_at", "updated_at"})
+
+				mock.ExpectQuery(`SELECT (.+) FROM post WHERE author_id = \$1 AND created_at > \$2 AND created_at < \$3 (.+) LIMIT \$4`).
+					WithArgs(database.KSUIDArg(args.authorId), args.filter.CreatedAfter, args.filter.CreatedBefore, *args.sort.First).
 					WillReturnRows(rows)
 			},
 		},
@@ -209,7 +344,7 @@ func TestPostRepository_GetPosts(t *testing.T) {
 			tt.mockBehavior(tt.args, tt.want)
 
 			// Getting a post by id in postgres database.
-			got, err := repos.GetPosts(context.Background(), tt.args.authorId, tt.args.sort)
+			got, err := repos.GetPosts(context.Background(), tt.args.authorId, tt.args.sort, tt.args.filter, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("error getting author posts: %s", err.Error())
 			}
@@ -252,9 +387,29 @@ func TestPostRepository_Delete(t *testing.T) {
 			args:    args{id: ksuid.New(), authorId: ksuid.New()},
 			wantErr: false,
 			mockBehavior: func(args args) {
+				mock.ExpectBegin()
 				mock.ExpectExec("DELETE FROM post").
-					WithArgs(args.id, args.authorId).
+					WithArgs(database.KSUIDArg(args.id), database.KSUIDArg(args.authorId)).
 					WillReturnResult(pgxmock.NewResult("", 1))
+				mock.ExpectExec("INSERT INTO author_stats").
+					WithArgs(args.authorId.Bytes(), int64(-1)).
+					WillReturnResult(pgxmock.NewResult("", 1))
+				mock.ExpectExec("INSERT INTO post_outbox").
+					WithArgs(domain.EventPostDeleted, args.id, args.authorId, "").
+					WillReturnResult(pgxmock.NewResult("", 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:    "Not found",
+			args:    args{id: ksuid.New(), authorId: ksuid.New()},
+			wantErr: true,
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM post").
+					WithArgs(database.KSUIDArg(args.id), database.KSUIDArg(args.authorId)).
+					WillReturnResult(pgxmock.NewResult("", 0))
+				mock.ExpectRollback()
 			},
 		},
 	}
@@ -283,7 +438,10 @@ func TestPostRepository_Update(t *testing.T) {
 	defer mock.Close(context.Background())
 
 	// Testing args.
-	type args struct{ post domain.Post }
+	type args struct {
+		post domain.Post
+		mask domain.FieldMask
+	}
 
 	// Test behavior.
 	type mockBehavior func(args args)
@@ -299,15 +457,51 @@ func TestPostRepository_Update(t *testing.T) {
 		mockBehavior mockBehavior
 	}{
 		{
-			name:    "OK",
-			args:    args{post: domain.Post{Id: ksuid.New(), AuthorId: ksuid.New(), Text: "text"}},
+			name: "OK",
+			args: args{post: domain.Post{
+				Id:          ksuid.New(),
+				AuthorId:    ksuid.New(),
+				Text:        "text",
+				Fingerprint: domain.NewFingerprint("text"),
+			}},
 			wantErr: false,
 			mockBehavior: func(args args) {
+				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE post").
-					WithArgs(args.post.Text, args.post.Id, args.post.AuthorId).
+					WithArgs(args.post.Text, args.post.Fingerprint.Hash, int64(args.post.Fingerprint.SimHash),
+						database.KSUIDArg(args.post.Id), database.KSUIDArg(args.post.AuthorId)).
+					WillReturnResult(pgxmock.NewResult("", 1))
+				mock.ExpectExec("INSERT INTO post_outbox").
+					WithArgs(domain.EventPostUpdated, args.post.Id, args.post.AuthorId, args.post.Text).
 					WillReturnResult(pgxmock.NewResult("", 1))
+				mock.ExpectCommit()
 			},
 		},
+		{
+			name: "Not found",
+			args: args{
+				post: domain.Post{Id: ksuid.New(), AuthorId: ksuid.New(), Text: "text"},
+				mask: domain.FieldMask{domain.PostFieldText},
+			},
+			wantErr: true,
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE post").
+					WithArgs(args.post.Text, args.post.Fingerprint.Hash, int64(args.post.Fingerprint.SimHash),
+						database.KSUIDArg(args.post.Id), database.KSUIDArg(args.post.AuthorId)).
+					WillReturnResult(pgxmock.NewResult("", 0))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name: "Not updatable field",
+			args: args{
+				post: domain.Post{Id: ksuid.New(), AuthorId: ksuid.New()},
+				mask: domain.FieldMask{domain.PostFieldFlagged},
+			},
+			wantErr:      true,
+			mockBehavior: func(args args) {},
+		},
 	}
 
 	// Conducting tests in various structures.
@@ -316,9 +510,9 @@ func TestPostRepository_Update(t *testing.T) {
 			tt.mockBehavior(tt.args)
 
 			// Updating a post in postgres database.
-			err := repos.Update(context.Background(), tt.args.post)
+			err := repos.Update(context.Background(), tt.args.post, tt.args.mask)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("error updating post by id: %s", err.Error())
+				t.Errorf("error updating post by id: %v", err)
 			}
 		})
 	}
@@ -334,7 +528,10 @@ func TestPostRepository_GetTotalCount(t *testing.T) {
 	defer mock.Close(context.Background())
 
 	// Testing args.
-	type args struct{ authorId ksuid.KSUID }
+	type args struct {
+		authorId ksuid.KSUID
+		filter   domain.PostFilter
+	}
 
 	// Test behavior.
 	type mockBehavior func(args args, want int32)
@@ -354,11 +551,36 @@ func TestPostRepository_GetTotalCount(t *testing.T) {
 			name: "OK",
 			args: args{authorId: ksuid.New()},
 			want: 10,
+			mockBehavior: func(args args, want int32) {
+				rows := mock.NewRows([]string{"posts_count"}).AddRow(want)
+
+				mock.ExpectQuery("SELECT posts_count FROM author_stats").
+					WithArgs(args.authorId.Bytes()).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "Without stats",
+			args: args{authorId: ksuid.New()},
+			want: 0,
+			mockBehavior: func(args args, want int32) {
+				mock.ExpectQuery("SELECT posts_count FROM author_stats").
+					WithArgs(args.authorId.Bytes()).
+					WillReturnError(pgx.ErrNoRows)
+			},
+		},
+		{
+			name: "Creation time filter",
+			args: args{
+				authorId: ksuid.New(),
+				filter:   domain.PostFilter{CreatedAfter: time.Now().Add(-time.Hour)},
+			},
+			want: 2,
 			mockBehavior: func(args args, want int32) {
 				rows := mock.NewRows([]string{"count(*)"}).AddRow(want)
 
-				mock.ExpectQuery("SELECT (.+) FROM post").
-					WithArgs(args.authorId).
+				mock.ExpectQuery(`SELECT count\(\*\) FROM post WHERE author_id = \$1 AND created_at > \$2`).
+					WithArgs(database.KSUIDArg(args.authorId), args.filter.CreatedAfter).
 					WillReturnRows(rows)
 			},
 		},
@@ -370,7 +592,7 @@ func TestPostRepository_GetTotalCount(t *testing.T) {
 			tt.mockBehavior(tt.args, tt.want)
 
 			// Getting total author posts count in postgres database.
-			got, err := repos.GetTotalCount(context.Background(), tt.args.authorId)
+			got, err := repos.GetTotalCount(context.Background(), tt.args.authorId, tt.args.filter)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("error getting total post count: %s", err.Error())
 			}
@@ -382,3 +604,317 @@ func TestPostRepository_GetTotalCount(t *testing.T) {
 		})
 	}
 }
+
+// Testing finding posts similar to the fingerprint in postgres database.
+func TestPostRepository_FindSimilar(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Testing args.
+	type args struct {
+		fingerprint domain.Fingerprint
+		opts        domain.SimilarOptions
+	}
+
+	// Test behavior.
+	type mockBehavior func(args args, want []domain.Post)
+
+	// Creating a new repository.
+	repos := postgres.NewPostRepository(mock)
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		args         args
+		want         []domain.Post
+		wantErr      bool
+		mockBehavior mockBehavior
+	}{
+		{
+			name: "OK",
+			args: args{
+				fingerprint: domain.NewFingerprint("text"),
+				opts: domain.SimilarOptions{
+					AuthorId: ksuid.New(),
+					Distance: 6,
+					Since:    time.Now(),
+					Limit:    1,
+				},
+			},
+			want: []domain.Post{
+				{
+					Id:        ksuid.New(),
+					AuthorId:  ksuid.New(),
+					Text:      "text",
+					CreatedAt: time.Now(),
+				},
+			},
+			mockBehavior: func(args args, want []domain.Post) {
+				rows := mock.NewRows([]string{"id", "author_id", "text", "flagged", "created_at", "updated_at"}).AddRow(
+					want[0].Id, want[0].AuthorId, want[0].Text, want[0].Flagged, want[0].CreatedAt, want[0].UpdatedAt,
+				)
+
+				mock.ExpectQuery(`SELECT (.+) FROM post WHERE (.+) AND author_id = \$4 AND created_at > \$5`).
+					WithArgs(args.fingerprint.Hash, int64(args.fingerprint.SimHash), args.opts.Distance,
+						database.KSUIDArg(args.opts.AuthorId), args.opts.Since, args.opts.Limit).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "Unbounded",
+			args: args{
+				fingerprint: domain.NewFingerprint("text"),
+				opts:        domain.SimilarOptions{Exclude: ksuid.New(), Distance: 6, Limit: 1},
+			},
+			want: []domain.Post{},
+			mockBehavior: func(args args, want []domain.Post) {
+				rows := mock.NewRows([]string{"id", "author_id", "text", "flagged", "created_at", "updated_at"})
+
+				mock.ExpectQuery(`SELECT (.+) FROM post WHERE (.+) AND id <> \$4 ORDER BY`).
+					WithArgs(args.fingerprint.Hash, int64(args.fingerprint.SimHash), args.opts.Distance,
+						database.KSUIDArg(args.opts.Exclude), args.opts.Limit).
+					WillReturnRows(rows)
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args, tt.want)
+
+			// Finding similar posts in postgres database.
+			got, err := repos.FindSimilar(context.Background(), tt.args.fingerprint, tt.args.opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error finding similar posts: %s", err.Error())
+			}
+
+			// Check for similarity of posts.
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Error("error posts are not similar")
+			}
+		})
+	}
+}
+
+// Encoded statement structure.
+type encodedStatement struct {
+	// Part of the statement query.
+	query string
+	// Parameter type OIDs of the ksuid contracted schema.
+	oids []uint32
+	// Indexes of ksuid parameters.
+	ids []int
+}
+
+// Statements of the ksuid contracted schema, post and author stats ids are bytea
+// and outbox ids are bpchar.
+var encodedStatements = []encodedStatement{
+	{
+		query: "INSERT INTO post (",
+		oids: []uint32{pgtype.ByteaOID, pgtype.ByteaOID, pgtype.TextOID, pgtype.ByteaOID, pgtype.Int8OID,
+			pgtype.BoolOID, pgtype.TimestamptzOID},
+		ids: []int{0, 1},
+	},
+	{
+		query: "DELETE FROM post WHERE id=$1 AND author_id=$2",
+		oids:  []uint32{pgtype.ByteaOID, pgtype.ByteaOID},
+		ids:   []int{0, 1},
+	},
+	{
+		query: "INSERT INTO author_stats (author_id, posts_count)",
+		oids:  []uint32{pgtype.ByteaOID, pgtype.Int8OID},
+		ids:   []int{0},
+	},
+	{
+		query: "SELECT posts_count FROM author_stats WHERE author_id=$1",
+		oids:  []uint32{pgtype.ByteaOID},
+		ids:   []int{0},
+	},
+	{
+		query: "FROM post WHERE id=$1",
+		oids:  []uint32{pgtype.ByteaOID},
+		ids:   []int{0},
+	},
+	{
+		query: "FROM post WHERE author_id = $1 AND (created_at, id) < ($2, $3)",
+		oids:  []uint32{pgtype.ByteaOID, pgtype.TimestamptzOID, pgtype.ByteaOID, pgtype.Int8OID},
+		ids:   []int{0, 2},
+	},
+	{
+		query: "SELECT count(*) FROM post WHERE author_id = $1",
+		oids:  []uint32{pgtype.ByteaOID, pgtype.TimestamptzOID},
+		ids:   []int{0},
+	},
+	{
+		query: "UPDATE post SET",
+		oids:  []uint32{pgtype.TextOID, pgtype.ByteaOID, pgtype.Int8OID, pgtype.ByteaOID, pgtype.ByteaOID},
+		ids:   []int{3, 4},
+	},
+	{
+		query: "INSERT INTO post_outbox",
+		oids:  []uint32{pgtype.TextOID, pgtype.BPCharOID, pgtype.BPCharOID, pgtype.TextOID},
+		ids:   []int{1, 2},
+	},
+	{
+		query: "FROM post WHERE (hash =",
+		oids: []uint32{pgtype.ByteaOID, pgtype.Int8OID, pgtype.Int4OID, pgtype.ByteaOID, pgtype.ByteaOID,
+			pgtype.TimestamptzOID, pgtype.Int8OID},
+		ids: []int{3, 4},
+	},
+}
+
+// Getting encoded statement by query.
+func getEncodedStatement(sql string) (encodedStatement, bool) {
+	for _, statement := range encodedStatements {
+		if strings.Contains(sql, statement.query) {
+			return statement, true
+		}
+	}
+
+	return encodedStatement{}, false
+}
+
+// Testing encoding post ids as the type of the compared columns.
+func TestPostRepository_Encoding(t *testing.T) {
+	ctx := context.Background()
+
+	// Creating a new fake backend with the ksuid contracted schema.
+	backend := postgrestest.NewBackend(func(sql string) []uint32 {
+		statement, _ := getEncodedStatement(sql)
+		return statement.oids
+	})
+
+	conn, err := backend.Connect(ctx)
+	if err != nil {
+		t.Fatalf("error connecting to backend: %s", err.Error())
+	}
+	defer conn.Close(ctx)
+
+	// Registering ksuid types.
+	database.RegisterKSUID(conn.ConnInfo())
+
+	// Creating a new repository.
+	repos := postgres.NewPostRepository(conn)
+
+	first := int32(10)
+	post := domain.Post{Id: ksuid.New(), AuthorId: ksuid.New(), Text: "text", Fingerprint: domain.NewFingerprint("text")}
+
+	// Tests structures. Results of the fake backend are empty, so missing posts
+	// are not errors of encoding.
+	tests := []struct {
+		name       string
+		call       func() error
+		statements int
+	}{
+		{
+			name:       "Create",
+			call:       func() error { return repos.Create(ctx, post) },
+			statements: 3,
+		},
+		{
+			name:       "Delete",
+			call:       func() error { return repos.Delete(ctx, post.Id, post.AuthorId) },
+			statements: 3,
+		},
+		{
+			name: "Get",
+			call: func() error {
+				var e *domain.Error
+
+				_, err := repos.Get(ctx, post.Id, nil)
+				if errors.As(err, &e) && e.Code == domain.CodeNotFound {
+					return nil
+				}
+
+				return err
+			},
+			statements: 1,
+		},
+		{
+			name: "GetPosts",
+			call: func() error {
+				_, err := repos.GetPosts(ctx, post.AuthorId, domain.SortOptions{First: &first, Before: ksuid.New()},
+					domain.PostFilter{}, nil)
+				return err
+			},
+			statements: 1,
+		},
+		{
+			name: "GetTotalCount stats",
+			call: func() error {
+				_, err := repos.GetTotalCount(ctx, post.AuthorId, domain.PostFilter{})
+				return err
+			},
+			statements: 1,
+		},
+		{
+			name: "GetTotalCount",
+			call: func() error {
+				_, err := repos.GetTotalCount(ctx, post.AuthorId, domain.PostFilter{CreatedAfter: time.Now()})
+				if errors.Is(err, pgx.ErrNoRows) {
+					return nil
+				}
+
+				return err
+			},
+			statements: 1,
+		},
+		{
+			name:       "Update",
+			call:       func() error { return repos.Update(ctx, post, domain.FieldMask{domain.PostFieldText}) },
+			statements: 2,
+		},
+		{
+			name: "FindSimilar",
+			call: func() error {
+				_, err := repos.FindSimilar(ctx, post.Fingerprint, domain.SimilarOptions{
+					AuthorId: post.AuthorId, Exclude: post.Id, Distance: 3, Since: time.Now(), Limit: 5,
+				})
+				return err
+			},
+			statements: 1,
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(backend.Binds())
+
+			if err := tt.call(); err != nil {
+				t.Fatalf("error calling repository: %s", err.Error())
+			}
+
+			binds := backend.Binds()[before:]
+			if len(binds) != tt.statements {
+				t.Fatalf("error bound statements: got %d, want %d", len(binds), tt.statements)
+			}
+
+			for _, bind := range binds {
+				statement, ok := getEncodedStatement(bind.SQL)
+				if !ok {
+					t.Fatalf("error unknown statement: %s", bind.SQL)
+				}
+
+				// Check for ksuid parameters encoded as the column type.
+				for _, i := range statement.ids {
+					switch statement.oids[i] {
+					case pgtype.ByteaOID:
+						if _, err := ksuid.FromBytes(bind.Params[i]); err != nil {
+							t.Errorf("error bytea id $%d of %q: %q", i+1, statement.query, bind.Params[i])
+						}
+					case pgtype.BPCharOID:
+						if _, err := ksuid.Parse(string(bind.Params[i])); err != nil {
+							t.Errorf("error bpchar id $%d of %q: %q", i+1, statement.query, bind.Params[i])
+						}
+					}
+				}
+			}
+		})
+	}
+}
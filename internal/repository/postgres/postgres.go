@@ -20,12 +20,25 @@ package postgres
 import (
 	"github.com/durudex/durudex-post-service/internal/config"
 	"github.com/durudex/durudex-post-service/pkg/database/postgres"
+	"github.com/durudex/durudex-post-service/schema"
 
+	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
 )
 
 // Postgres repository structure.
-type PostgresRepository struct{ Post }
+type PostgresRepository struct {
+	Post
+	Fingerprint
+	Event
+	Outbox
+	Stats
+	Webhook
+	Health
+	Migrator *postgres.Migrator
+	pool     *pgxpool.Pool
+}
 
 // Creating a new postgres repository.
 func NewPostgresRepository(cfg config.PostgresConfig) *PostgresRepository {
@@ -41,5 +54,36 @@ func NewPostgresRepository(cfg config.PostgresConfig) *PostgresRepository {
 		log.Fatal().Err(err).Msg("failed to create postgres client")
 	}
 
-	return &PostgresRepository{Post: NewPostRepository(client)}
+	// Creating a new schema migrator of the embedded migrations.
+	migrator, err := postgres.NewMigrator(client, schema.FS)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load schema migrations")
+	}
+
+	// Every repository query is traced, listening connections are not.
+	psql := postgres.NewTracingPostgres(client, otel.GetTracerProvider())
+
+	return &PostgresRepository{
+		Post:        NewPostMetrics(NewPostRepository(psql)),
+		Fingerprint: NewFingerprintRepository(psql),
+		Event:       NewEventRepository(psql, postgres.NewListener(client)),
+		Outbox:      NewOutboxRepository(psql),
+		Stats:       NewStatsRepository(psql),
+		Webhook:     NewWebhookRepository(psql),
+		Health:      NewHealthRepository(psql),
+		Migrator:    migrator,
+		pool:        client,
+	}
+}
+
+// Getting postgres pool statistics.
+func (r *PostgresRepository) Stat() *pgxpool.Stat {
+	return r.pool.Stat()
+}
+
+// Closing postgres pool connections.
+func (r *PostgresRepository) Close() {
+	log.Debug().Msg("Closing postgres pool connections")
+
+	r.pool.Close()
 }
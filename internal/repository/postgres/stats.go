@@ -0,0 +1,303 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres
+
+import (
+	"context"
+
+	"github.com/durudex/durudex-post-service/pkg/database/postgres"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/segmentio/ksuid"
+)
+
+// Advisory lock key that allows only one author stats reconciliation at a time.
+const statsLockKey int64 = 0x706f73745f737461
+
+// Stats repository interface.
+type Stats interface {
+	// Repairing drifted author posts counts of an authors batch in postgres database.
+	ReconcileAuthorStats(ctx context.Context, after ksuid.KSUID, limit int32) (ksuid.KSUID, int, error)
+	// Creating missing author posts counts of a post authors batch in postgres database.
+	CreateMissingAuthorStats(ctx context.Context, after ksuid.KSUID, limit int32) (ksuid.KSUID, int, error)
+}
+
+// Stats repository structure.
+type StatsRepository struct{ psql postgres.Postgres }
+
+// Creating a new stats repository.
+func NewStatsRepository(psql postgres.Postgres) *StatsRepository {
+	return &StatsRepository{psql: psql}
+}
+
+// Repairing drifted author posts counts of an authors batch in postgres database.
+//
+// Authors are paginated by the stats primary key after the given author, and
+// posts of each author are counted with the author timeline index, so a batch
+// costs limit index scans instead of counting all posts. Drifted authors are
+// recounted with the stats row locked, so that counts changed by concurrent
+// post transactions are not overwritten. Authors without a stats row are
+// created by CreateMissingAuthorStats. Returns the last author of the batch, or
+// nil ksuid when there are no more authors or another reconciliation is
+// running, and the number of repaired authors.
+func (r *StatsRepository) ReconcileAuthorStats(ctx context.Context, after ksuid.KSUID, limit int32) (ksuid.KSUID, int, error) {
+	// Begin a stats transaction.
+	tx, err := r.psql.Begin(ctx)
+	if err != nil {
+		return ksuid.Nil, 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+
+	// Check is another reconciliation running.
+	if err := tx.QueryRow(ctx, "SELECT pg_try_advisory_xact_lock($1)", statsLockKey).Scan(&locked); err != nil {
+		return ksuid.Nil, 0, err
+	}
+
+	if !locked {
+		return ksuid.Nil, 0, nil
+	}
+
+	// Getting authors batch stats.
+	stats, err := getAuthorStats(ctx, tx, after, limit)
+	if err != nil {
+		return ksuid.Nil, 0, err
+	}
+
+	var repaired int
+
+	for _, s := range stats {
+		var count int64
+
+		// Query for counting author posts.
+		query := "SELECT count(*) FROM post WHERE author_id=$1"
+
+		if err := tx.QueryRow(ctx, query, postgres.KSUIDArg(s.authorId)).Scan(&count); err != nil {
+			return ksuid.Nil, 0, err
+		}
+
+		if count == s.postsCount {
+			continue
+		}
+
+		ok, err := recountAuthorPosts(ctx, tx, s.authorId)
+		if err != nil {
+			return ksuid.Nil, 0, err
+		}
+
+		if ok {
+			repaired++
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ksuid.Nil, 0, err
+	}
+
+	// Check is it the last batch.
+	if len(stats) < int(limit) {
+		return ksuid.Nil, repaired, nil
+	}
+
+	return stats[len(stats)-1].authorId, repaired, nil
+}
+
+// Creating missing author posts counts of a post authors batch in postgres
+// database.
+//
+// Post authors are paginated by the author timeline index after the given
+// author, and a stats row with the counted posts is created for each author
+// without one. A stats row created by a concurrent post transaction is kept, as
+// it already counts the post. Returns the last author of the batch, or nil
+// ksuid when there are no more authors or another reconciliation is running,
+// and the number of created authors.
+func (r *StatsRepository) CreateMissingAuthorStats(ctx context.Context, after ksuid.KSUID, limit int32) (ksuid.KSUID, int, error) {
+	// Begin a stats transaction.
+	tx, err := r.psql.Begin(ctx)
+	if err != nil {
+		return ksuid.Nil, 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+
+	// Check is another reconciliation running.
+	if err := tx.QueryRow(ctx, "SELECT pg_try_advisory_xact_lock($1)", statsLockKey).Scan(&locked); err != nil {
+		return ksuid.Nil, 0, err
+	}
+
+	if !locked {
+		return ksuid.Nil, 0, nil
+	}
+
+	// Getting post authors batch.
+	authors, err := getPostAuthors(ctx, tx, after, limit)
+	if err != nil {
+		return ksuid.Nil, 0, err
+	}
+
+	var created int
+
+	for _, authorId := range authors {
+		// Query for creating missing author stats with the counted posts.
+		query := `INSERT INTO author_stats (author_id, posts_count)
+			SELECT $1, count(*) FROM post WHERE author_id=$2 ON CONFLICT (author_id) DO NOTHING`
+
+		tag, err := tx.Exec(ctx, query, authorId.Bytes(), postgres.KSUIDArg(authorId))
+		if err != nil {
+			return ksuid.Nil, 0, err
+		}
+
+		created += int(tag.RowsAffected())
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ksuid.Nil, 0, err
+	}
+
+	// Check is it the last batch.
+	if len(authors) < int(limit) {
+		return ksuid.Nil, created, nil
+	}
+
+	return authors[len(authors)-1], created, nil
+}
+
+// Getting post authors after the given author in the transaction.
+func getPostAuthors(ctx context.Context, tx pgx.Tx, after ksuid.KSUID, limit int32) ([]ksuid.KSUID, error) {
+	// Query for getting post authors batch.
+	query := "SELECT DISTINCT author_id FROM post WHERE author_id > $1 ORDER BY author_id ASC LIMIT $2"
+
+	rows, err := tx.Query(ctx, query, postgres.KSUIDArg(after), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	authors := make([]ksuid.KSUID, 0, limit)
+
+	// Scanning query rows.
+	for rows.Next() {
+		var authorId ksuid.KSUID
+
+		// Scanning query row.
+		if err := rows.Scan(&authorId); err != nil {
+			return nil, err
+		}
+
+		authors = append(authors, authorId)
+	}
+
+	// Check is rows error.
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return authors, nil
+}
+
+// Author posts count structure.
+type authorStats struct {
+	authorId   ksuid.KSUID
+	postsCount int64
+}
+
+// Getting stored posts counts of the authors after the given author in the transaction.
+func getAuthorStats(ctx context.Context, tx pgx.Tx, after ksuid.KSUID, limit int32) ([]authorStats, error) {
+	// Query for getting authors batch stats.
+	query := "SELECT author_id, posts_count FROM author_stats WHERE author_id > $1 ORDER BY author_id ASC LIMIT $2"
+
+	rows, err := tx.Query(ctx, query, after.Bytes(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]authorStats, 0, limit)
+
+	// Scanning query rows.
+	for rows.Next() {
+		var s authorStats
+
+		// Scanning query row.
+		if err := rows.Scan(&s.authorId, &s.postsCount); err != nil {
+			return nil, err
+		}
+
+		stats = append(stats, s)
+	}
+
+	// Check is rows error.
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// Recounting author posts with the stats row locked in the transaction.
+// Returns true when the stored count was repaired.
+func recountAuthorPosts(ctx context.Context, tx pgx.Tx, authorId ksuid.KSUID) (bool, error) {
+	var stored, count int64
+
+	// Query for creating missing author stats.
+	query := "INSERT INTO author_stats (author_id) VALUES ($1) ON CONFLICT (author_id) DO NOTHING"
+
+	if _, err := tx.Exec(ctx, query, authorId.Bytes()); err != nil {
+		return false, err
+	}
+
+	// Query for locking author stats.
+	query = "SELECT posts_count FROM author_stats WHERE author_id=$1 FOR UPDATE"
+
+	if err := tx.QueryRow(ctx, query, authorId.Bytes()).Scan(&stored); err != nil {
+		return false, err
+	}
+
+	// Query for counting author posts.
+	query = "SELECT count(*) FROM post WHERE author_id=$1"
+
+	if err := tx.QueryRow(ctx, query, postgres.KSUIDArg(authorId)).Scan(&count); err != nil {
+		return false, err
+	}
+
+	if stored == count {
+		return false, nil
+	}
+
+	// Query for repairing author posts count.
+	query = "UPDATE author_stats SET posts_count=$2, updated_at=now() WHERE author_id=$1"
+
+	if _, err := tx.Exec(ctx, query, authorId.Bytes(), count); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Adding to the author posts count in the transaction. Author stats ids are
+// always binary, so they are bound as the ksuid bytes.
+func addAuthorPosts(ctx context.Context, tx pgx.Tx, authorId ksuid.KSUID, delta int64) error {
+	// Query for updating author posts count.
+	query := `INSERT INTO author_stats (author_id, posts_count) VALUES ($1, $2)
+		ON CONFLICT (author_id) DO UPDATE SET posts_count = author_stats.posts_count + $2, updated_at = now()`
+	_, err := tx.Exec(ctx, query, authorId.Bytes(), delta)
+
+	return err
+}
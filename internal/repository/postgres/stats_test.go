@@ -0,0 +1,267 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/internal/repository/postgres"
+	database "github.com/durudex/durudex-post-service/pkg/database/postgres"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/segmentio/ksuid"
+)
+
+// Testing repairing drifted author posts counts in postgres database.
+func TestStatsRepository_ReconcileAuthorStats(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Testing args.
+	type args struct {
+		after ksuid.KSUID
+		limit int32
+	}
+
+	// Test behavior.
+	type mockBehavior func(args args)
+
+	// Creating a new repository.
+	repos := postgres.NewStatsRepository(mock)
+
+	drifted, counted := ksuid.New(), ksuid.New()
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		args         args
+		want         int
+		wantLast     ksuid.KSUID
+		wantErr      bool
+		mockBehavior mockBehavior
+	}{
+		{
+			name:     "OK",
+			args:     args{after: ksuid.New(), limit: 2},
+			want:     1,
+			wantLast: counted,
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT pg_try_advisory_xact_lock").
+					WillReturnRows(mock.NewRows([]string{"locked"}).AddRow(true))
+				mock.ExpectQuery("SELECT author_id, posts_count FROM author_stats WHERE author_id > (.+) LIMIT").
+					WithArgs(args.after.Bytes(), args.limit).
+					WillReturnRows(mock.NewRows([]string{"author_id", "posts_count"}).
+						AddRow(drifted, int64(3)).AddRow(counted, int64(2)))
+
+				// Repairing a drifted author.
+				mock.ExpectQuery(`SELECT count\(\*\) FROM post`).
+					WithArgs(database.KSUIDArg(drifted)).
+					WillReturnRows(mock.NewRows([]string{"count"}).AddRow(int64(5)))
+				mock.ExpectExec("INSERT INTO author_stats").
+					WithArgs(drifted.Bytes()).
+					WillReturnResult(pgxmock.NewResult("INSERT", 0))
+				mock.ExpectQuery("SELECT posts_count FROM author_stats (.+) FOR UPDATE").
+					WithArgs(drifted.Bytes()).
+					WillReturnRows(mock.NewRows([]string{"posts_count"}).AddRow(int64(3)))
+				mock.ExpectQuery(`SELECT count\(\*\) FROM post`).
+					WithArgs(database.KSUIDArg(drifted)).
+					WillReturnRows(mock.NewRows([]string{"count"}).AddRow(int64(5)))
+				mock.ExpectExec("UPDATE author_stats").
+					WithArgs(drifted.Bytes(), int64(5)).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+				// Author with a stored count of the posts.
+				mock.ExpectQuery(`SELECT count\(\*\) FROM post`).
+					WithArgs(database.KSUIDArg(counted)).
+					WillReturnRows(mock.NewRows([]string{"count"}).AddRow(int64(2)))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Last Batch",
+			args: args{limit: 2},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT pg_try_advisory_xact_lock").
+					WillReturnRows(mock.NewRows([]string{"locked"}).AddRow(true))
+				mock.ExpectQuery("SELECT author_id, posts_count FROM author_stats").
+					WithArgs(args.after.Bytes(), args.limit).
+					WillReturnRows(mock.NewRows([]string{"author_id", "posts_count"}).AddRow(counted, int64(2)))
+				mock.ExpectQuery(`SELECT count\(\*\) FROM post`).
+					WithArgs(database.KSUIDArg(counted)).
+					WillReturnRows(mock.NewRows([]string{"count"}).AddRow(int64(2)))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Locked",
+			args: args{limit: 10},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT pg_try_advisory_xact_lock").
+					WillReturnRows(mock.NewRows([]string{"locked"}).AddRow(false))
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			// Repairing drifted author posts counts in postgres database.
+			last, got, err := repos.ReconcileAuthorStats(context.Background(), tt.args.after, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error reconciling author stats: %s", err.Error())
+			}
+
+			// Check for last author of the batch.
+			if last != tt.wantLast {
+				t.Errorf("error last author: got %s, want %s", last, tt.wantLast)
+			}
+
+			// Check for repaired authors.
+			if got != tt.want {
+				t.Errorf("error repaired authors: got %d, want %d", got, tt.want)
+			}
+
+			// Check for all expectations.
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("error expectations were not met: %s", err.Error())
+			}
+		})
+	}
+}
+
+// Testing creating missing author posts counts in postgres database.
+func TestStatsRepository_CreateMissingAuthorStats(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Testing args.
+	type args struct {
+		after ksuid.KSUID
+		limit int32
+	}
+
+	// Test behavior.
+	type mockBehavior func(args args)
+
+	// Creating a new repository.
+	repos := postgres.NewStatsRepository(mock)
+
+	missing, existing := ksuid.New(), ksuid.New()
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		args         args
+		want         int
+		wantLast     ksuid.KSUID
+		wantErr      bool
+		mockBehavior mockBehavior
+	}{
+		{
+			name:     "Missing",
+			args:     args{after: ksuid.New(), limit: 2},
+			want:     1,
+			wantLast: existing,
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT pg_try_advisory_xact_lock").
+					WillReturnRows(mock.NewRows([]string{"locked"}).AddRow(true))
+				mock.ExpectQuery("SELECT DISTINCT author_id FROM post WHERE author_id > (.+) LIMIT").
+					WithArgs(database.KSUIDArg(args.after), args.limit).
+					WillReturnRows(mock.NewRows([]string{"author_id"}).AddRow(missing).AddRow(existing))
+
+				// Creating a missing author stats row.
+				mock.ExpectExec("INSERT INTO author_stats (.+) SELECT (.+) ON CONFLICT").
+					WithArgs(missing.Bytes(), database.KSUIDArg(missing)).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+				// Author with a stats row.
+				mock.ExpectExec("INSERT INTO author_stats (.+) SELECT (.+) ON CONFLICT").
+					WithArgs(existing.Bytes(), database.KSUIDArg(existing)).
+					WillReturnResult(pgxmock.NewResult("INSERT", 0))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Last Batch",
+			args: args{limit: 2},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT pg_try_advisory_xact_lock").
+					WillReturnRows(mock.NewRows([]string{"locked"}).AddRow(true))
+				mock.ExpectQuery("SELECT DISTINCT author_id FROM post").
+					WithArgs(database.KSUIDArg(args.after), args.limit).
+					WillReturnRows(mock.NewRows([]string{"author_id"}))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Locked",
+			args: args{limit: 10},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT pg_try_advisory_xact_lock").
+					WillReturnRows(mock.NewRows([]string{"locked"}).AddRow(false))
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			// Creating missing author posts counts in postgres database.
+			last, got, err := repos.CreateMissingAuthorStats(context.Background(), tt.args.after, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error creating missing author stats: %s", err.Error())
+			}
+
+			// Check for last author of the batch.
+			if last != tt.wantLast {
+				t.Errorf("error last author: got %s, want %s", last, tt.wantLast)
+			}
+
+			// Check for created authors.
+			if got != tt.want {
+				t.Errorf("error created authors: got %d, want %d", got, tt.want)
+			}
+
+			// Check for all expectations.
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("error expectations were not met: %s", err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/pkg/database/postgres"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/segmentio/ksuid"
+)
+
+// Webhook repository interface.
+type Webhook interface {
+	// Creating a new webhook in postgres database.
+	CreateWebhook(ctx context.Context, webhook domain.Webhook) error
+	// Getting all webhooks in postgres database.
+	GetWebhooks(ctx context.Context) ([]domain.Webhook, error)
+	// Deleting a webhook in postgres database.
+	DeleteWebhook(ctx context.Context, id ksuid.KSUID) error
+	// Creating new webhook deliveries in postgres database.
+	CreateDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error
+	// Claiming due pending webhook deliveries in postgres database.
+	ClaimDeliveries(ctx context.Context, limit int32, lease time.Duration) ([]domain.WebhookDelivery, error)
+	// Updating a webhook delivery in postgres database.
+	UpdateDelivery(ctx context.Context, delivery domain.WebhookDelivery) error
+	// Getting webhook deliveries in postgres database.
+	GetDeliveries(ctx context.Context, webhookId ksuid.KSUID, limit int32) ([]domain.WebhookDelivery, error)
+	// Replaying a webhook delivery in postgres database.
+	ReplayDelivery(ctx context.Context, id ksuid.KSUID) error
+}
+
+// Webhook repository structure.
+type WebhookRepository struct{ psql postgres.Postgres }
+
+// Creating a new webhook repository.
+func NewWebhookRepository(psql postgres.Postgres) *WebhookRepository {
+	return &WebhookRepository{psql: psql}
+}
+
+// Creating a new webhook in postgres database.
+func (r *WebhookRepository) CreateWebhook(ctx context.Context, webhook domain.Webhook) error {
+	events := make([]string, len(webhook.Events))
+
+	for i, e := range webhook.Events {
+		events[i] = string(e)
+	}
+
+	// Query for creating a new webhook.
+	query := "INSERT INTO webhook (id, url, events, secret) VALUES ($1, $2, $3, $4)"
+	_, err := r.psql.Exec(ctx, query, webhook.Id, webhook.URL, events, webhook.Secret)
+
+	return err
+}
+
+// Getting all webhooks in postgres database.
+func (r *WebhookRepository) GetWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	// Query for getting all webhooks.
+	query := "SELECT id, url, events, secret, created_at FROM webhook ORDER BY created_at ASC"
+
+	rows, err := r.psql.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []domain.Webhook
+
+	// Scanning query rows.
+	for rows.Next() {
+		var (
+			webhook domain.Webhook
+			events  []string
+		)
+
+		// Scanning query row.
+		if err := rows.Scan(&webhook.Id, &webhook.URL, &events, &webhook.Secret, &webhook.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		for _, e := range events {
+			webhook.Events = append(webhook.Events, domain.EventType(e))
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	// Check is rows error.
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// Deleting a webhook in postgres database.
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, id ksuid.KSUID) error {
+	// Query for deleting a webhook.
+	query := "DELETE FROM webhook WHERE id=$1"
+
+	tag, err := r.psql.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	// Check if webhook not found.
+	if tag.RowsAffected() == 0 {
+		return &domain.Error{Code: domain.CodeNotFound, Message: "Webhook not found", Reason: domain.ReasonWebhookNotFound}
+	}
+
+	return nil
+}
+
+// Creating new webhook deliveries in postgres database.
+//
+// Deliveries of an event that was already recorded for the webhook are skipped,
+// so relaying an event again does not send it twice.
+func (r *WebhookRepository) CreateDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	// Begin a deliveries transaction.
+	tx, err := r.psql.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Query for creating a new webhook delivery.
+	query := `INSERT INTO webhook_delivery (id, webhook_id, event_id, event_type, payload)
+		VALUES ($1, $2, $3, $4, $5) ON CONFLICT (webhook_id, event_id) DO NOTHING`
+
+	for _, d := range deliveries {
+		if _, err := tx.Exec(ctx, query, d.Id, d.WebhookId, d.EventId, d.EventType, d.Payload); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Claiming due pending webhook deliveries in postgres database.
+//
+// Claimed deliveries are postponed by the lease, so other replicas skip them and
+// they are retried if the worker stops before updating them.
+func (r *WebhookRepository) ClaimDeliveries(ctx context.Context, limit int32, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	// Query for claiming due pending webhook deliveries.
+	query := `UPDATE webhook_delivery SET next_attempt_at = now() + $2 * interval '1 microsecond'
+		WHERE id IN (SELECT id FROM webhook_delivery WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at ASC LIMIT $1 FOR UPDATE SKIP LOCKED)
+		RETURNING id, webhook_id, event_id, event_type, payload, status, attempts, response_code,
+			error, next_attempt_at, created_at, updated_at`
+
+	rows, err := r.psql.Query(ctx, query, limit, lease.Microseconds())
+	if err != nil {
+		return nil, err
+	}
+
+	return scanDeliveries(rows, limit)
+}
+
+// Updating a webhook delivery in postgres database.
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	// Query for updating a webhook delivery.
+	query := `UPDATE webhook_delivery SET status=$1, attempts=$2, response_code=$3, error=$4,
+		next_attempt_at=$5, updated_at=now() WHERE id=$6`
+	_, err := r.psql.Exec(ctx, query, delivery.Status, delivery.Attempts, delivery.ResponseCode,
+		delivery.Error, delivery.NextAttemptAt, delivery.Id)
+
+	return err
+}
+
+// Getting webhook deliveries in postgres database.
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, webhookId ksuid.KSUID, limit int32) ([]domain.WebhookDelivery, error) {
+	// Query for getting webhook deliveries.
+	query := `SELECT id, webhook_id, event_id, event_type, payload, status, attempts, response_code,
+		error, next_attempt_at, created_at, updated_at FROM webhook_delivery WHERE webhook_id=$1
+		ORDER BY created_at DESC, id DESC LIMIT $2`
+
+	rows, err := r.psql.Query(ctx, query, webhookId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanDeliveries(rows, limit)
+}
+
+// Replaying a webhook delivery in postgres database.
+func (r *WebhookRepository) ReplayDelivery(ctx context.Context, id ksuid.KSUID) error {
+	// Query for replaying a webhook delivery.
+	query := `UPDATE webhook_delivery SET status='pending', attempts=0, error='', response_code=0,
+		next_attempt_at=now(), updated_at=now() WHERE id=$1`
+
+	tag, err := r.psql.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	// Check if webhook delivery not found.
+	if tag.RowsAffected() == 0 {
+		return &domain.Error{
+			Code:    domain.CodeNotFound,
+			Message: "Webhook delivery not found",
+			Reason:  domain.ReasonWebhookDeliveryNotFound,
+		}
+	}
+
+	return nil
+}
+
+// Scanning webhook deliveries query rows.
+func scanDeliveries(rows pgx.Rows, limit int32) ([]domain.WebhookDelivery, error) {
+	defer rows.Close()
+
+	deliveries := make([]domain.WebhookDelivery, 0, limit)
+
+	// Scanning query rows.
+	for rows.Next() {
+		var d domain.WebhookDelivery
+
+		// Scanning query row.
+		if err := rows.Scan(&d.Id, &d.WebhookId, &d.EventId, &d.EventType, &d.Payload, &d.Status,
+			&d.Attempts, &d.ResponseCode, &d.Error, &d.NextAttemptAt, &d.CreatedAt, &d.UpdatedAt); err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	// Check is rows error.
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,418 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/repository/postgres"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/segmentio/ksuid"
+)
+
+// Testing creating a new webhook in postgres database.
+func TestWebhookRepository_CreateWebhook(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Testing args.
+	type args struct{ webhook domain.Webhook }
+
+	// Test behavior.
+	type mockBehavior func(args args)
+
+	// Creating a new repository.
+	repos := postgres.NewWebhookRepository(mock)
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		args         args
+		wantErr      bool
+		mockBehavior mockBehavior
+	}{
+		{
+			name: "OK",
+			args: args{webhook: domain.Webhook{
+				Id:     ksuid.New(),
+				URL:    "https://example.com/hook",
+				Events: []domain.EventType{domain.EventPostCreated},
+				Secret: "secret",
+			}},
+			mockBehavior: func(args args) {
+				mock.ExpectExec("INSERT INTO webhook").
+					WithArgs(args.webhook.Id, args.webhook.URL, []string{"post.created"}, args.webhook.Secret).
+					WillReturnResult(pgxmock.NewResult("", 1))
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			// Creating a new webhook in postgres database.
+			err := repos.CreateWebhook(context.Background(), tt.args.webhook)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error creating webhook: %v", err)
+			}
+		})
+	}
+}
+
+// Testing getting all webhooks in postgres database.
+func TestWebhookRepository_GetWebhooks(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Test behavior.
+	type mockBehavior func(want []domain.Webhook)
+
+	// Creating a new repository.
+	repos := postgres.NewWebhookRepository(mock)
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		want         []domain.Webhook
+		wantErr      bool
+		mockBehavior mockBehavior
+	}{
+		{
+			name: "OK",
+			want: []domain.Webhook{
+				{
+					Id:        ksuid.New(),
+					URL:       "https://example.com/hook",
+					Events:    []domain.EventType{domain.EventPostCreated, domain.EventPostDeleted},
+					Secret:    "secret",
+					CreatedAt: time.Now(),
+				},
+				{Id: ksuid.New(), URL: "https://example.com/all", Secret: "secret", CreatedAt: time.Now()},
+			},
+			mockBehavior: func(want []domain.Webhook) {
+				rows := mock.NewRows([]string{"id", "url", "events", "secret", "created_at"})
+
+				for _, w := range want {
+					events := []string{}
+
+					for _, e := range w.Events {
+						events = append(events, string(e))
+					}
+
+					rows.AddRow(w.Id, w.URL, events, w.Secret, w.CreatedAt)
+				}
+
+				mock.ExpectQuery("SELECT (.+) FROM webhook").WillReturnRows(rows)
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.want)
+
+			// Getting all webhooks in postgres database.
+			got, err := repos.GetWebhooks(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error getting webhooks: %v", err)
+			}
+
+			// Check for similarity of webhooks.
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Error("error webhooks are not similar")
+			}
+		})
+	}
+}
+
+// Testing deleting a webhook in postgres database.
+func TestWebhookRepository_DeleteWebhook(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Testing args.
+	type args struct{ id ksuid.KSUID }
+
+	// Test behavior.
+	type mockBehavior func(args args)
+
+	// Creating a new repository.
+	repos := postgres.NewWebhookRepository(mock)
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		args         args
+		wantErr      bool
+		mockBehavior mockBehavior
+	}{
+		{
+			name: "OK",
+			args: args{id: ksuid.New()},
+			mockBehavior: func(args args) {
+				mock.ExpectExec("DELETE FROM webhook").
+					WithArgs(args.id).
+					WillReturnResult(pgxmock.NewResult("", 1))
+			},
+		},
+		{
+			name:    "Not found",
+			args:    args{id: ksuid.New()},
+			wantErr: true,
+			mockBehavior: func(args args) {
+				mock.ExpectExec("DELETE FROM webhook").
+					WithArgs(args.id).
+					WillReturnResult(pgxmock.NewResult("", 0))
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			// Deleting a webhook in postgres database.
+			err := repos.DeleteWebhook(context.Background(), tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error deleting webhook: %v", err)
+			}
+		})
+	}
+}
+
+// Testing creating new webhook deliveries in postgres database.
+func TestWebhookRepository_CreateDeliveries(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Testing args.
+	type args struct{ deliveries []domain.WebhookDelivery }
+
+	// Test behavior.
+	type mockBehavior func(args args)
+
+	// Creating a new repository.
+	repos := postgres.NewWebhookRepository(mock)
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		args         args
+		wantErr      bool
+		mockBehavior mockBehavior
+	}{
+		{
+			name: "OK",
+			args: args{deliveries: []domain.WebhookDelivery{
+				{Id: ksuid.New(), WebhookId: ksuid.New(), EventId: 1, EventType: domain.EventPostCreated, Payload: []byte(`{}`)},
+				{Id: ksuid.New(), WebhookId: ksuid.New(), EventId: 1, EventType: domain.EventPostCreated, Payload: []byte(`{}`)},
+			}},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				for _, d := range args.deliveries {
+					mock.ExpectExec("INSERT INTO webhook_delivery").
+						WithArgs(d.Id, d.WebhookId, d.EventId, d.EventType, d.Payload).
+						WillReturnResult(pgxmock.NewResult("", 1))
+				}
+
+				mock.ExpectCommit()
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			// Creating new webhook deliveries in postgres database.
+			err := repos.CreateDeliveries(context.Background(), tt.args.deliveries)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error creating webhook deliveries: %v", err)
+			}
+
+			// Check for all expectations.
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("error expectations were not met: %s", err.Error())
+			}
+		})
+	}
+}
+
+// Testing claiming due pending webhook deliveries in postgres database.
+func TestWebhookRepository_ClaimDeliveries(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Testing args.
+	type args struct {
+		limit int32
+		lease time.Duration
+	}
+
+	// Test behavior.
+	type mockBehavior func(args args, want []domain.WebhookDelivery)
+
+	// Creating a new repository.
+	repos := postgres.NewWebhookRepository(mock)
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		args         args
+		want         []domain.WebhookDelivery
+		wantErr      bool
+		mockBehavior mockBehavior
+	}{
+		{
+			name: "OK",
+			args: args{limit: 10, lease: 20 * time.Second},
+			want: []domain.WebhookDelivery{{
+				Id:            ksuid.New(),
+				WebhookId:     ksuid.New(),
+				EventId:       1,
+				EventType:     domain.EventPostCreated,
+				Payload:       []byte(`{"id":1}`),
+				Status:        domain.DeliveryPending,
+				NextAttemptAt: time.Now(),
+				CreatedAt:     time.Now(),
+			}},
+			mockBehavior: func(args args, want []domain.WebhookDelivery) {
+				rows := mock.NewRows([]string{"id", "webhook_id", "event_id", "event_type", "payload", "status",
+					"attempts", "response_code", "error", "next_attempt_at", "created_at", "updated_at"})
+
+				for _, d := range want {
+					rows.AddRow(d.Id, d.WebhookId, d.EventId, d.EventType, d.Payload, d.Status, d.Attempts,
+						d.ResponseCode, d.Error, d.NextAttemptAt, d.CreatedAt, d.UpdatedAt)
+				}
+
+				mock.ExpectQuery("UPDATE webhook_delivery").
+					WithArgs(args.limit, args.lease.Microseconds()).
+					WillReturnRows(rows)
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args, tt.want)
+
+			// Claiming due pending webhook deliveries in postgres database.
+			got, err := repos.ClaimDeliveries(context.Background(), tt.args.limit, tt.args.lease)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error claiming webhook deliveries: %v", err)
+			}
+
+			// Check for similarity of webhook deliveries.
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Error("error webhook deliveries are not similar")
+			}
+		})
+	}
+}
+
+// Testing replaying a webhook delivery in postgres database.
+func TestWebhookRepository_ReplayDelivery(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Testing args.
+	type args struct{ id ksuid.KSUID }
+
+	// Test behavior.
+	type mockBehavior func(args args)
+
+	// Creating a new repository.
+	repos := postgres.NewWebhookRepository(mock)
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		args         args
+		wantErr      bool
+		mockBehavior mockBehavior
+	}{
+		{
+			name: "OK",
+			args: args{id: ksuid.New()},
+			mockBehavior: func(args args) {
+				mock.ExpectExec("UPDATE webhook_delivery").
+					WithArgs(args.id).
+					WillReturnResult(pgxmock.NewResult("", 1))
+			},
+		},
+		{
+			name:    "Not found",
+			args:    args{id: ksuid.New()},
+			wantErr: true,
+			mockBehavior: func(args args) {
+				mock.ExpectExec("UPDATE webhook_delivery").
+					WithArgs(args.id).
+					WillReturnResult(pgxmock.NewResult("", 0))
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			// Replaying a webhook delivery in postgres database.
+			err := repos.ReplayDelivery(context.Background(), tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error replaying webhook delivery: %v", err)
+			}
+		})
+	}
+}
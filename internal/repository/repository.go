@@ -29,3 +29,8 @@ type Repository struct{ Postgres *postgres.PostgresRepository }
 func NewRepository(config config.DatabaseConfig) *Repository {
 	return &Repository{Postgres: postgres.NewPostgresRepository(config.Postgres)}
 }
+
+// Closing repository connections.
+func (r *Repository) Close() {
+	r.Postgres.Close()
+}
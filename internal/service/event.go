@@ -0,0 +1,346 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/repository/postgres"
+
+	"github.com/rs/zerolog/log"
+	"github.com/segmentio/ksuid"
+)
+
+const (
+	// Interval between deleting expired post events.
+	eventCleanupInterval = time.Hour
+	// Delay before a slow consumer may resume watching.
+	overflowRetryDelay = time.Second
+	// Delay before a consumer may resume watching on another server.
+	shutdownRetryDelay = time.Second
+)
+
+// Event interface.
+type Event interface {
+	// Watching author post events after the cursor.
+	Watch(ctx context.Context, authorIds []ksuid.KSUID, cursor *int64, send func(domain.PostEvent) error) error
+	// Running post events dispatcher.
+	Run(ctx context.Context)
+	// Stopping watching post events.
+	Stop(ctx context.Context) error
+}
+
+// Post events subscriber structure.
+type subscriber struct {
+	authors  map[ksuid.KSUID]struct{}
+	events   chan domain.PostEvent
+	overflow chan struct{}
+}
+
+// Event service structure.
+type EventService struct {
+	repos postgres.Event
+	cfg   config.StreamConfig
+
+	mu     sync.Mutex
+	subs   map[*subscriber]struct{}
+	cursor int64
+	ready  bool
+
+	stop     sync.Once
+	stopping chan struct{}
+}
+
+// Creating a new event service.
+func NewEventService(repos postgres.Event, cfg config.StreamConfig) *EventService {
+	return &EventService{
+		repos:    repos,
+		cfg:      cfg,
+		subs:     make(map[*subscriber]struct{}),
+		stopping: make(chan struct{}),
+	}
+}
+
+// Watching author post events after the cursor.
+func (s *EventService) Watch(ctx context.Context, authorIds []ksuid.KSUID, cursor *int64, send func(domain.PostEvent) error) error {
+	// Check number of watched authors.
+	if len(authorIds) == 0 || len(authorIds) > s.cfg.MaxAuthors {
+		return &domain.Error{
+			Code:     domain.CodeInvalidArgument,
+			Message:  "Invalid number of authors",
+			Reason:   domain.ReasonInvalidAuthors,
+			Metadata: map[string]string{"max_authors": strconv.Itoa(s.cfg.MaxAuthors)},
+			Violations: []domain.FieldViolation{
+				{Field: "author_ids", Description: "Number of authors must be from 1 to " + strconv.Itoa(s.cfg.MaxAuthors)},
+			},
+		}
+	}
+
+	// Check is service stopping.
+	select {
+	case <-s.stopping:
+		return errShuttingDown()
+	default:
+	}
+
+	sub := &subscriber{
+		authors:  make(map[ksuid.KSUID]struct{}, len(authorIds)),
+		events:   make(chan domain.PostEvent, s.cfg.Buffer),
+		overflow: make(chan struct{}),
+	}
+
+	for _, id := range authorIds {
+		sub.authors[id] = struct{}{}
+	}
+
+	// Subscribe before replaying, so no event is lost in between.
+	s.subscribe(sub)
+	defer s.unsubscribe(sub)
+
+	var last int64
+
+	// Replaying stored events after the cursor.
+	if cursor != nil {
+		// Check is events after the cursor deleted.
+		expired, err := s.repos.GetExpiredCursor(ctx)
+		if err != nil {
+			return err
+		}
+
+		if *cursor < expired {
+			return &domain.Error{
+				Code:     domain.CodeOutOfRange,
+				Message:  "Cursor is older than the event retention, watch without a cursor",
+				Reason:   domain.ReasonCursorExpired,
+				Metadata: map[string]string{"expired_cursor": strconv.FormatInt(expired, 10)},
+			}
+		}
+
+		last = *cursor
+
+		for {
+			events, err := s.repos.GetEvents(ctx, authorIds, last, s.cfg.Batch)
+			if err != nil {
+				return err
+			}
+
+			for _, event := range events {
+				if err := send(event); err != nil {
+					return err
+				}
+
+				last = event.Id
+			}
+
+			if len(events) < int(s.cfg.Batch) {
+				break
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.stopping:
+			return errShuttingDown()
+		case <-sub.overflow:
+			return &domain.Error{
+				Code:       domain.CodeResourceExhausted,
+				Message:    "Consumer is too slow, resume from the last cursor",
+				Reason:     domain.ReasonConsumerTooSlow,
+				RetryDelay: overflowRetryDelay,
+			}
+		case event := <-sub.events:
+			// Skip already replayed events.
+			if event.Id <= last {
+				continue
+			}
+
+			if err := send(event); err != nil {
+				return err
+			}
+
+			last = event.Id
+		}
+	}
+}
+
+// Stopping watching post events. Open watch streams end with an unavailable
+// error, so servers are drained without waiting for them.
+func (s *EventService) Stop(context.Context) error {
+	s.stop.Do(func() { close(s.stopping) })
+
+	return nil
+}
+
+// Getting a server shutting down error.
+func errShuttingDown() error {
+	return &domain.Error{
+		Code:       domain.CodeUnavailable,
+		Message:    "Server is shutting down, resume from the last cursor",
+		Reason:     domain.ReasonServerShuttingDown,
+		RetryDelay: shutdownRetryDelay,
+	}
+}
+
+// Running post events dispatcher.
+func (s *EventService) Run(ctx context.Context) {
+	log.Debug().Msg("Running post events dispatcher...")
+
+	go s.cleanup(ctx)
+
+	for {
+		if err := s.listen(ctx); err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msg("error listening post events")
+		}
+
+		// Reconnect after a delay.
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.cfg.Reconnect):
+		}
+	}
+}
+
+// Listening for new post events.
+func (s *EventService) listen(ctx context.Context) error {
+	// Dispatch only events created after the start.
+	if !s.ready {
+		cursor, err := s.repos.GetLastCursor(ctx)
+		if err != nil {
+			return err
+		}
+
+		s.cursor, s.ready = cursor, true
+	}
+
+	// Events missed while reconnecting are dispatched once the listener is ready.
+	return s.repos.Listen(ctx, func() {
+		if err := s.dispatch(ctx); err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msg("error dispatching post events")
+		}
+	})
+}
+
+// Dispatching new post events to subscribers.
+func (s *EventService) dispatch(ctx context.Context) error {
+	ctx, span := startJob(ctx, "EventService.Dispatch")
+	defer span.End()
+
+	err := s.dispatchEvents(ctx)
+	recordError(span, err)
+
+	return err
+}
+
+// Positioning and publishing new post events.
+func (s *EventService) dispatchEvents(ctx context.Context) error {
+	// Positioning committed post events.
+	for {
+		n, err := s.repos.PositionEvents(ctx, s.cfg.Batch)
+		if err != nil {
+			return err
+		}
+
+		if n < int(s.cfg.Batch) {
+			break
+		}
+	}
+
+	for {
+		events, err := s.repos.GetEvents(ctx, nil, s.cursor, s.cfg.Batch)
+		if err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+
+		for _, event := range events {
+			s.publish(event)
+			s.cursor = event.Id
+		}
+
+		s.mu.Unlock()
+
+		if len(events) < int(s.cfg.Batch) {
+			return nil
+		}
+	}
+}
+
+// Publishing a post event to subscribers. Must be called with the lock held.
+func (s *EventService) publish(event domain.PostEvent) {
+	for sub := range s.subs {
+		if _, ok := sub.authors[event.Post.AuthorId]; !ok {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			// Disconnect a slow subscriber.
+			close(sub.overflow)
+			delete(s.subs, sub)
+		}
+	}
+}
+
+// Adding a post events subscriber.
+func (s *EventService) subscribe(sub *subscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subs[sub] = struct{}{}
+}
+
+// Removing a post events subscriber.
+func (s *EventService) unsubscribe(sub *subscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.subs, sub)
+}
+
+// Deleting expired post events.
+func (s *EventService) cleanup(ctx context.Context) {
+	ticker := time.NewTicker(eventCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			jobCtx, span := startJob(ctx, "EventService.Cleanup")
+
+			if err := s.repos.DeleteEvents(jobCtx, time.Now().Add(-s.cfg.Retention)); err != nil {
+				recordError(span, err)
+				log.Error().Err(err).Msg("error deleting expired post events")
+			}
+
+			span.End()
+		}
+	}
+}
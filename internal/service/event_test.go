@@ -0,0 +1,342 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package service_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	mock_postgres "github.com/durudex/durudex-post-service/internal/repository/postgres/mock"
+	"github.com/durudex/durudex-post-service/internal/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/segmentio/ksuid"
+)
+
+// Testing watching author post events.
+func TestEventService_Watch(t *testing.T) {
+	// Creating a new mock controller.
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// Creating a new mock repository.
+	psql := mock_postgres.NewMockEvent(c)
+
+	// Testing args.
+	type args struct {
+		authorIds []ksuid.KSUID
+		cursor    int64
+	}
+
+	// Test behavior.
+	type mockBehavior func(r *mock_postgres.MockEvent, args args, want []domain.PostEvent)
+
+	authorId := ksuid.New()
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		args         args
+		want         []domain.PostEvent
+		wantErr      bool
+		wantCode     domain.Code
+		mockBehavior mockBehavior
+	}{
+		{
+			name: "OK",
+			args: args{authorIds: []ksuid.KSUID{authorId}, cursor: 5},
+			want: []domain.PostEvent{
+				{Id: 6, Type: domain.EventPostCreated, Post: domain.Post{Id: ksuid.New(), AuthorId: authorId}},
+				{Id: 7, Type: domain.EventPostDeleted, Post: domain.Post{Id: ksuid.New(), AuthorId: authorId}},
+			},
+			mockBehavior: func(r *mock_postgres.MockEvent, args args, want []domain.PostEvent) {
+				ready := make(chan struct{})
+
+				// Replaying stored events.
+				r.EXPECT().GetExpiredCursor(gomock.Any()).Return(int64(2), nil)
+				r.EXPECT().GetEvents(gomock.Any(), args.authorIds, args.cursor, testConfig.Stream.Batch).
+					DoAndReturn(func(context.Context, []ksuid.KSUID, int64, int32) ([]domain.PostEvent, error) {
+						close(ready)
+
+						return want[:1], nil
+					})
+
+				// Dispatching new events.
+				r.EXPECT().GetLastCursor(gomock.Any()).Return(int64(5), nil)
+				r.EXPECT().Listen(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, handler func()) error {
+						<-ready
+						handler()
+						<-ctx.Done()
+
+						return ctx.Err()
+					})
+				r.EXPECT().PositionEvents(gomock.Any(), testConfig.Stream.Batch).Return(2, nil)
+				r.EXPECT().GetEvents(gomock.Any(), nil, int64(5), testConfig.Stream.Batch).
+					Return([]domain.PostEvent{want[0], want[1], {Id: 8, Post: domain.Post{AuthorId: ksuid.New()}}}, nil)
+			},
+		},
+		{
+			name:     "Cursor expired",
+			args:     args{authorIds: []ksuid.KSUID{authorId}, cursor: 5},
+			wantErr:  true,
+			wantCode: domain.CodeOutOfRange,
+			mockBehavior: func(r *mock_postgres.MockEvent, args args, want []domain.PostEvent) {
+				r.EXPECT().GetExpiredCursor(gomock.Any()).Return(int64(6), nil)
+
+				// Dispatching new events.
+				r.EXPECT().GetLastCursor(gomock.Any()).Return(int64(5), nil).AnyTimes()
+				r.EXPECT().Listen(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, handler func()) error {
+						<-ctx.Done()
+
+						return ctx.Err()
+					}).AnyTimes()
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setting a mock behavior.
+			tt.mockBehavior(psql, tt.args, tt.want)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// Creating a new event service.
+			service := service.NewEventService(psql, testConfig.Stream)
+
+			// Running post events dispatcher.
+			go service.Run(ctx)
+
+			var got []domain.PostEvent
+
+			// Watching author post events.
+			err := service.Watch(ctx, tt.args.authorIds, &tt.args.cursor, func(event domain.PostEvent) error {
+				got = append(got, event)
+
+				// Stop watching after all events.
+				if len(got) == len(tt.want) {
+					cancel()
+				}
+
+				return nil
+			})
+			if tt.wantErr {
+				var e *domain.Error
+
+				if !errors.As(err, &e) || e.Code != tt.wantCode {
+					t.Errorf("error watching post events: got %v, want code %d", err, tt.wantCode)
+				}
+			} else if !errors.Is(err, context.Canceled) {
+				t.Errorf("error watching post events: %v", err)
+			}
+
+			// Check for similarity of events.
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Error("error events are not similar")
+			}
+		})
+	}
+}
+
+// Post events table fake structure.
+//
+// Events take ids when inserted and stream positions when positioned after
+// the commit, as the post events dispatcher does.
+type eventTable struct {
+	mu        sync.Mutex
+	inserted  map[int64]domain.PostEvent
+	committed []domain.PostEvent
+	events    []domain.PostEvent
+	notify    chan struct{}
+}
+
+// Inserting an uncommitted post event.
+func (t *eventTable) insert(id int64, event domain.PostEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.inserted[id] = event
+}
+
+// Committing a post event.
+func (t *eventTable) commit(id int64) {
+	t.mu.Lock()
+	t.committed = append(t.committed, t.inserted[id])
+	t.mu.Unlock()
+
+	t.notify <- struct{}{}
+}
+
+// Positioning committed post events.
+func (t *eventTable) PositionEvents(_ context.Context, limit int32) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var n int
+
+	for ; n < len(t.committed) && n < int(limit); n++ {
+		event := t.committed[n]
+		event.Id = int64(len(t.events) + 1)
+		t.events = append(t.events, event)
+	}
+
+	t.committed = t.committed[n:]
+
+	return n, nil
+}
+
+// Getting committed post events after the cursor.
+func (t *eventTable) GetEvents(_ context.Context, _ []ksuid.KSUID, cursor int64, limit int32) ([]domain.PostEvent, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var events []domain.PostEvent
+
+	for _, event := range t.events {
+		if event.Id > cursor && len(events) < int(limit) {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// Getting the last committed post event cursor.
+func (t *eventTable) GetLastCursor(context.Context) (int64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return int64(len(t.events)), nil
+}
+
+// Getting the cursor of the last deleted post event.
+func (t *eventTable) GetExpiredCursor(context.Context) (int64, error) { return 0, nil }
+
+// Deleting post events created before the time.
+func (t *eventTable) DeleteEvents(context.Context, time.Time) error { return nil }
+
+// Calling the handler on every commit.
+func (t *eventTable) Listen(ctx context.Context, handler func()) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.notify:
+			handler()
+		}
+	}
+}
+
+// Testing watching post events committed out of insertion order.
+func TestEventService_WatchCommitOrder(t *testing.T) {
+	authorId := ksuid.New()
+	first, second := ksuid.New(), ksuid.New()
+
+	table := &eventTable{inserted: make(map[int64]domain.PostEvent), notify: make(chan struct{})}
+
+	// Events of two overlapping post transactions.
+	table.insert(1, domain.PostEvent{Type: domain.EventPostCreated, Post: domain.Post{Id: first, AuthorId: authorId}})
+	table.insert(2, domain.PostEvent{Type: domain.EventPostCreated, Post: domain.Post{Id: second, AuthorId: authorId}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Creating a new event service.
+	service := service.NewEventService(table, testConfig.Stream)
+
+	// Running post events dispatcher.
+	go service.Run(ctx)
+
+	received := make(chan domain.PostEvent)
+	cursor := int64(0)
+
+	// Watching author post events, events committed before subscribing are replayed.
+	go service.Watch(ctx, []ksuid.KSUID{authorId}, &cursor, func(event domain.PostEvent) error {
+		received <- event
+		return nil
+	})
+
+	// Committing the later inserted event first.
+	for _, id := range []int64{2, 1} {
+		table.commit(id)
+	}
+
+	var got []ksuid.KSUID
+
+	for len(got) < 2 {
+		select {
+		case event := <-received:
+			got = append(got, event.Post.Id)
+		case <-time.After(time.Second):
+			t.Fatalf("error events are missed: got %v", got)
+		}
+	}
+
+	// Check for commit order of events.
+	if !reflect.DeepEqual(got, []ksuid.KSUID{second, first}) {
+		t.Errorf("error events are not in commit order: %v", got)
+	}
+}
+
+// Testing ending open watch streams on stopping.
+func TestEventService_Stop(t *testing.T) {
+	// Creating a new event service.
+	service := service.NewEventService(nil, testConfig.Stream)
+
+	errs := make(chan error)
+
+	// Watching author post events until stopping.
+	go func() {
+		errs <- service.Watch(context.Background(), []ksuid.KSUID{ksuid.New()}, nil, func(domain.PostEvent) error {
+			return nil
+		})
+	}()
+
+	if err := service.Stop(context.Background()); err != nil {
+		t.Fatalf("error stopping event service: %s", err.Error())
+	}
+
+	var e *domain.Error
+
+	// Check for unavailable error of the open stream.
+	select {
+	case err := <-errs:
+		if !errors.As(err, &e) || e.Code != domain.CodeUnavailable || e.RetryDelay == 0 {
+			t.Errorf("error watching post events: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("error watch stream is not ended")
+	}
+
+	// Check for unavailable error of a new stream.
+	err := service.Watch(context.Background(), []ksuid.KSUID{ksuid.New()}, nil, func(domain.PostEvent) error {
+		return nil
+	})
+	if !errors.As(err, &e) || e.Code != domain.CodeUnavailable {
+		t.Errorf("error watching post events: %v", err)
+	}
+}
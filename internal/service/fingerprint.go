@@ -0,0 +1,122 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/repository/postgres"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// Number of posts fingerprinted in a transaction.
+	fingerprintBatch int32 = 1000
+	// Delay before retrying a failed fingerprints backfill.
+	fingerprintRetryDelay = time.Minute
+)
+
+// Fingerprint interface.
+type Fingerprint interface {
+	// Fingerprinting posts created before the fingerprint migration.
+	Backfill(ctx context.Context) (int, error)
+	// Running post fingerprints backfill worker.
+	Run(ctx context.Context)
+}
+
+// Fingerprint service structure.
+type FingerprintService struct{ repos postgres.Fingerprint }
+
+// Creating a new fingerprint service.
+func NewFingerprintService(repos postgres.Fingerprint) *FingerprintService {
+	return &FingerprintService{repos: repos}
+}
+
+// Fingerprinting posts created before the fingerprint migration, so they are
+// found by duplicate detection and similar posts. Returns the number of
+// fingerprinted posts.
+func (s *FingerprintService) Backfill(ctx context.Context) (int, error) {
+	ctx, span := startJob(ctx, "FingerprintService.Backfill")
+	defer span.End()
+
+	n, err := s.backfill(ctx)
+	recordError(span, err)
+
+	return n, err
+}
+
+// Fingerprinting posts without a content fingerprint in batches.
+func (s *FingerprintService) backfill(ctx context.Context) (int, error) {
+	var n int
+
+	for {
+		// Getting posts without a content fingerprint.
+		posts, err := s.repos.GetUnfingerprinted(ctx, fingerprintBatch)
+		if err != nil {
+			return n, err
+		}
+
+		if len(posts) == 0 {
+			return n, nil
+		}
+
+		for i := range posts {
+			posts[i].Fingerprint = domain.NewFingerprint(posts[i].Text)
+		}
+
+		// Setting post content fingerprints.
+		if err := s.repos.SetFingerprints(ctx, posts); err != nil {
+			return n, err
+		}
+
+		n += len(posts)
+
+		if len(posts) < int(fingerprintBatch) {
+			return n, nil
+		}
+	}
+}
+
+// Running post fingerprints backfill worker. The backfill is retried until
+// every post is fingerprinted.
+func (s *FingerprintService) Run(ctx context.Context) {
+	for {
+		n, err := s.Backfill(ctx)
+		if n != 0 {
+			log.Info().Int("posts", n).Msg("Fingerprinted posts")
+		}
+
+		if err == nil {
+			return
+		}
+
+		if ctx.Err() == nil {
+			log.Error().Err(err).Msg("error fingerprinting posts")
+		}
+
+		// Retry after a delay.
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(fingerprintRetryDelay):
+		}
+	}
+}
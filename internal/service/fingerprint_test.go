@@ -0,0 +1,108 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	mock_postgres "github.com/durudex/durudex-post-service/internal/repository/postgres/mock"
+	"github.com/durudex/durudex-post-service/internal/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/segmentio/ksuid"
+)
+
+// Testing fingerprinting posts created before the fingerprint migration.
+func TestFingerprintService_Backfill(t *testing.T) {
+	// Creating a new mock controller.
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// Creating a new mock repository.
+	psql := mock_postgres.NewMockFingerprint(c)
+
+	// Test behavior.
+	type mockBehavior func(r *mock_postgres.MockFingerprint)
+
+	post := domain.Post{Id: ksuid.New(), Text: "Hello, World!"}
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		want         int
+		wantErr      bool
+		mockBehavior mockBehavior
+	}{
+		{
+			name: "OK",
+			want: 1,
+			mockBehavior: func(r *mock_postgres.MockFingerprint) {
+				r.EXPECT().GetUnfingerprinted(gomock.Any(), int32(1000)).Return([]domain.Post{post}, nil)
+				r.EXPECT().SetFingerprints(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, posts []domain.Post) error {
+						// Check for the post text fingerprint.
+						want := domain.NewFingerprint(post.Text)
+
+						if len(posts) != 1 || posts[0].Id != post.Id || posts[0].Fingerprint.SimHash != want.SimHash {
+							t.Errorf("error fingerprinted posts: %+v", posts)
+						}
+
+						return nil
+					})
+			},
+		},
+		{
+			name: "Fingerprinted",
+			mockBehavior: func(r *mock_postgres.MockFingerprint) {
+				r.EXPECT().GetUnfingerprinted(gomock.Any(), int32(1000)).Return(nil, nil)
+			},
+		},
+		{
+			name:    "Error",
+			wantErr: true,
+			mockBehavior: func(r *mock_postgres.MockFingerprint) {
+				r.EXPECT().GetUnfingerprinted(gomock.Any(), int32(1000)).Return(nil, errors.New("query failed"))
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Call mock behavior.
+			tt.mockBehavior(psql)
+
+			// Creating a new fingerprint service.
+			service := service.NewFingerprintService(psql)
+
+			// Fingerprinting posts.
+			got, err := service.Backfill(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error fingerprinting posts: %v", err)
+			}
+
+			// Check for fingerprinted posts.
+			if got != tt.want {
+				t.Errorf("error fingerprinted posts: got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/repository/postgres"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Health interface.
+type Health interface {
+	// Checking service dependencies.
+	Check(ctx context.Context) error
+	// Running health checker.
+	Run(ctx context.Context, handler func(serving bool))
+}
+
+// Health service structure.
+type HealthService struct {
+	repos postgres.Health
+	cfg   config.HealthConfig
+}
+
+// Creating a new health service.
+func NewHealthService(repos postgres.Health, cfg config.HealthConfig) *HealthService {
+	return &HealthService{repos: repos, cfg: cfg}
+}
+
+// Checking service dependencies.
+func (s *HealthService) Check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	// Pinging postgres database.
+	return s.repos.Ping(ctx)
+}
+
+// Running health checker.
+//
+// The handler is called with the initial serving status and then every time the
+// status changes. The service stops serving after the threshold of consecutive
+// failed checks and serves again after the first successful check.
+func (s *HealthService) Run(ctx context.Context, handler func(serving bool)) {
+	log.Debug().Msg("Running health checker...")
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	var (
+		serving  = s.Check(ctx) == nil
+		failures int
+	)
+
+	handler(serving)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Check(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+
+				failures++
+
+				log.Warn().Err(err).Int("failures", failures).Msg("health check failed")
+
+				if serving && failures >= s.cfg.Threshold {
+					serving = false
+					handler(serving)
+				}
+
+				continue
+			}
+
+			failures = 0
+
+			if !serving {
+				serving = true
+				handler(serving)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package service_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+	mock_postgres "github.com/durudex/durudex-post-service/internal/repository/postgres/mock"
+	"github.com/durudex/durudex-post-service/internal/service"
+
+	"github.com/golang/mock/gomock"
+)
+
+// Testing running health checker.
+func TestHealthService_Run(t *testing.T) {
+	// Creating a new mock controller.
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// Creating a new mock repository.
+	psql := mock_postgres.NewMockHealth(c)
+
+	// Health config.
+	cfg := config.HealthConfig{Interval: time.Millisecond, Timeout: time.Second, Threshold: 2}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Ping results of consecutive checks.
+	pings := []error{nil, errors.New("connection refused"), nil, errors.New("connection refused"),
+		errors.New("connection refused"), nil}
+	want := []bool{true, false, true}
+
+	var (
+		mu  sync.Mutex
+		i   int
+		got []bool
+	)
+
+	psql.EXPECT().Ping(gomock.Any()).AnyTimes().DoAndReturn(func(context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// Stop checking after all ping results.
+		if i >= len(pings) {
+			cancel()
+			return nil
+		}
+
+		i++
+
+		return pings[i-1]
+	})
+
+	// Creating a new health service.
+	service := service.NewHealthService(psql, cfg)
+
+	// Running health checker.
+	service.Run(ctx, func(serving bool) { got = append(got, serving) })
+
+	// Check for similarity of serving statuses.
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("error serving statuses: got %v, want %v", got, want)
+	}
+}
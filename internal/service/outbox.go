@@ -0,0 +1,91 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/publisher"
+	"github.com/durudex/durudex-post-service/internal/repository/postgres"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Outbox interface.
+type Outbox interface {
+	// Relaying pending outbox events.
+	Relay(ctx context.Context) (int, error)
+	// Running outbox relay worker.
+	Run(ctx context.Context)
+}
+
+// Outbox service structure.
+type OutboxService struct {
+	repos     postgres.Outbox
+	publisher publisher.EventPublisher
+	cfg       config.OutboxConfig
+}
+
+// Creating a new outbox service.
+func NewOutboxService(repos postgres.Outbox, publisher publisher.EventPublisher, cfg config.OutboxConfig) *OutboxService {
+	return &OutboxService{repos: repos, publisher: publisher, cfg: cfg}
+}
+
+// Relaying pending outbox events.
+func (s *OutboxService) Relay(ctx context.Context) (int, error) {
+	ctx, span := startJob(ctx, "OutboxService.Relay")
+	defer span.End()
+
+	n, err := s.repos.RelayOutbox(ctx, s.cfg.Batch, s.cfg.MaxAttempts, s.cfg.Lease, s.publisher.Publish)
+	recordError(span, err)
+
+	return n, err
+}
+
+// Running outbox relay worker.
+func (s *OutboxService) Run(ctx context.Context) {
+	log.Debug().Msg("Running outbox relay worker...")
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Relay while there are full batches of events.
+			for {
+				n, err := s.Relay(ctx)
+				if err != nil {
+					if ctx.Err() == nil {
+						log.Error().Err(err).Msg("error relaying outbox events")
+					}
+
+					break
+				}
+
+				if n < int(s.cfg.Batch) {
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package service_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/publisher"
+	mock_postgres "github.com/durudex/durudex-post-service/internal/repository/postgres/mock"
+	"github.com/durudex/durudex-post-service/internal/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/segmentio/ksuid"
+)
+
+// Testing relaying pending outbox events.
+func TestOutboxService_Relay(t *testing.T) {
+	// Creating a new mock controller.
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// Creating a new mock repository.
+	psql := mock_postgres.NewMockOutbox(c)
+
+	// Outbox config.
+	cfg := config.OutboxConfig{Interval: time.Second, Batch: 100, MaxAttempts: 10, Lease: time.Minute}
+
+	// Test behavior.
+	type mockBehavior func(r *mock_postgres.MockOutbox, events []domain.PostEvent)
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		events       []domain.PostEvent
+		want         int
+		wantErr      bool
+		mockBehavior mockBehavior
+	}{
+		{
+			name: "OK",
+			events: []domain.PostEvent{
+				{Id: 1, Type: domain.EventPostCreated, Post: domain.Post{Id: ksuid.New()}},
+				{Id: 2, Type: domain.EventPostDeleted, Post: domain.Post{Id: ksuid.New()}},
+			},
+			want: 2,
+			mockBehavior: func(r *mock_postgres.MockOutbox, events []domain.PostEvent) {
+				r.EXPECT().RelayOutbox(gomock.Any(), cfg.Batch, cfg.MaxAttempts, cfg.Lease, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, _, _ int32, _ time.Duration, publish func(context.Context, domain.PostEvent) error) (int, error) {
+						for _, e := range events {
+							if err := publish(ctx, e); err != nil {
+								return 0, err
+							}
+						}
+
+						return len(events), nil
+					})
+			},
+		},
+		{
+			name:    "Error",
+			wantErr: true,
+			mockBehavior: func(r *mock_postgres.MockOutbox, events []domain.PostEvent) {
+				r.EXPECT().RelayOutbox(gomock.Any(), cfg.Batch, cfg.MaxAttempts, cfg.Lease, gomock.Any()).
+					Return(0, errors.New("relay failed"))
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Call mock behavior.
+			tt.mockBehavior(psql, tt.events)
+
+			// Creating a new in-memory publisher.
+			pub := publisher.NewMemoryPublisher()
+
+			// Creating a new outbox service.
+			service := service.NewOutboxService(psql, pub, cfg)
+
+			// Relaying pending outbox events.
+			got, err := service.Relay(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error relaying outbox events: %v", err)
+			}
+
+			// Check for similarity of relayed events.
+			if got != tt.want {
+				t.Errorf("error relayed count: got %d, want %d", got, tt.want)
+			}
+
+			// Check for similarity of published events.
+			if len(tt.events) != 0 && !reflect.DeepEqual(pub.Events(), tt.events) {
+				t.Error("error published events are not similar")
+			}
+		})
+	}
+}
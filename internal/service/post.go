@@ -19,46 +19,96 @@ package service
 
 import (
 	"context"
+	"time"
 
+	"github.com/durudex/durudex-post-service/internal/auth"
+	"github.com/durudex/durudex-post-service/internal/config"
 	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/metrics"
 	"github.com/durudex/durudex-post-service/internal/repository/postgres"
 
 	"github.com/segmentio/ksuid"
 )
 
+// Default maximum number of similar posts, used when the maximum is not
+// configured.
+const defaultSimilarLimit int32 = 50
+
 // Post interface.
 type Post interface {
 	// Creating a new post.
 	Create(ctx context.Context, post domain.Post) (ksuid.KSUID, error)
-	// Getting a post.
-	Get(ctx context.Context, id ksuid.KSUID) (domain.Post, error)
-	// Getting author posts.
-	GetPosts(ctx context.Context, authorId ksuid.KSUID, sort domain.SortOptions) ([]domain.Post, error)
+	// Getting a post fields.
+	Get(ctx context.Context, id ksuid.KSUID, mask domain.FieldMask) (domain.Post, error)
+	// Getting posts by ids.
+	GetByIds(ctx context.Context, ids []ksuid.KSUID) ([]domain.Post, error)
+	// Getting author posts fields.
+	GetPosts(ctx context.Context, authorId ksuid.KSUID, sort domain.SortOptions, filter domain.PostFilter, mask domain.FieldMask) ([]domain.Post, error)
 	// Deleting a post.
 	Delete(ctx context.Context, id, authorId ksuid.KSUID) error
-	// Updating a post.
-	Update(ctx context.Context, post domain.Post) error
+	// Updating a post fields.
+	Update(ctx context.Context, post domain.Post, mask domain.FieldMask) error
 	// Getting total author posts count.
-	GetTotalCount(ctx context.Context, authorId ksuid.KSUID) (int32, error)
+	GetTotalCount(ctx context.Context, authorId ksuid.KSUID, filter domain.PostFilter) (int32, error)
+	// Finding posts similar to the post.
+	FindSimilar(ctx context.Context, id ksuid.KSUID, limit int32) ([]domain.Post, error)
 }
 
 // Post service structure.
-type PostService struct{ repos postgres.Post }
+type PostService struct {
+	repos postgres.Post
+	cfg   config.PostConfig
+}
 
 // Creating a new post service.
-func NewPostService(repos postgres.Post) *PostService {
-	return &PostService{repos: repos}
+func NewPostService(repos postgres.Post, cfg config.PostConfig) *PostService {
+	return &PostService{repos: repos, cfg: cfg}
 }
 
 // Creating a new post.
 func (s *PostService) Create(ctx context.Context, post domain.Post) (ksuid.KSUID, error) {
 	var err error
 
+	// Getting authenticated post author.
+	post.AuthorId, err = s.author(ctx, post.AuthorId)
+	if err != nil {
+		return ksuid.Nil, err
+	}
+
 	// Validate a post.
 	if err := post.Validate(); err != nil {
 		return ksuid.Nil, err
 	}
 
+	// Computing a post content fingerprint.
+	post.Fingerprint = domain.NewFingerprint(post.Text)
+
+	// Check for duplicate author posts.
+	if s.cfg.Duplicate.Window > 0 && post.Fingerprint.Comparable() {
+		similar, err := s.repos.FindSimilar(ctx, post.Fingerprint, domain.SimilarOptions{
+			AuthorId: post.AuthorId,
+			Distance: s.cfg.Duplicate.Distance,
+			Since:    time.Now().Add(-s.cfg.Duplicate.Window),
+			Limit:    1,
+		})
+		if err != nil {
+			return ksuid.Nil, err
+		}
+
+		if len(similar) != 0 {
+			if s.cfg.Duplicate.Reject {
+				return ksuid.Nil, &domain.Error{
+					Code:    domain.CodeAlreadyExists,
+					Message: "Post is a duplicate",
+					Reason:  domain.ReasonPostDuplicate,
+				}
+			}
+
+			// Flag a post for moderators.
+			post.Flagged = true
+		}
+	}
+
 	// Generating a new user id.
 	if post.Id.IsNil() {
 		post.Id, err = ksuid.NewRandom()
@@ -67,18 +117,23 @@ func (s *PostService) Create(ctx context.Context, post domain.Post) (ksuid.KSUID
 		}
 	}
 
+	// Post creation time is the ksuid time, so that ksuid cursors match it.
+	post.CreatedAt = post.Id.Time()
+
 	// Create a new post.
 	if err := s.repos.Create(ctx, post); err != nil {
 		return ksuid.Nil, err
 	}
 
+	metrics.PostEventsTotal.WithLabelValues(string(domain.EventPostCreated)).Inc()
+
 	return post.Id, nil
 }
 
-// Getting a post.
-func (s *PostService) Get(ctx context.Context, id ksuid.KSUID) (domain.Post, error) {
+// Getting a post fields.
+func (s *PostService) Get(ctx context.Context, id ksuid.KSUID, mask domain.FieldMask) (domain.Post, error) {
 	// Get post by id.
-	post, err := s.repos.Get(ctx, id)
+	post, err := s.repos.Get(ctx, id, mask)
 	if err != nil {
 		return domain.Post{}, err
 	}
@@ -86,21 +141,37 @@ func (s *PostService) Get(ctx context.Context, id ksuid.KSUID) (domain.Post, err
 	return post, nil
 }
 
-// Getting author posts.
-func (s *PostService) GetPosts(ctx context.Context, authorId ksuid.KSUID, sort domain.SortOptions) ([]domain.Post, error) {
+// Getting posts by ids.
+func (s *PostService) GetByIds(ctx context.Context, ids []ksuid.KSUID) ([]domain.Post, error) {
+	// Check is ids are set.
+	if len(ids) == 0 {
+		return []domain.Post{}, nil
+	}
+
+	return s.repos.GetByIds(ctx, ids)
+}
+
+// Getting author posts fields.
+func (s *PostService) GetPosts(ctx context.Context, authorId ksuid.KSUID, sort domain.SortOptions, filter domain.PostFilter, mask domain.FieldMask) ([]domain.Post, error) {
 	// Check is first and last are set.
 	if sort.First == nil && sort.Last == nil {
 		return nil, &domain.Error{
 			Message: "Must be `first` or `last`",
 			Code:    domain.CodeInvalidArgument,
+			Reason:  domain.ReasonSortRequired,
+			Violations: []domain.FieldViolation{
+				{Field: "sort_options.first", Description: "One of `first` or `last` is required"},
+			},
 		}
 	}
 
+	// Validate posts filter.
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Getting author posts.
-	posts, err := s.repos.GetPosts(ctx, authorId, domain.SortOptions{
-		First: sort.First,
-		Last:  sort.Last,
-	})
+	posts, err := s.repos.GetPosts(ctx, authorId, sort, filter, mask)
 	if err != nil {
 		return nil, err
 	}
@@ -110,20 +181,119 @@ func (s *PostService) GetPosts(ctx context.Context, authorId ksuid.KSUID, sort d
 
 // Deleting a post.
 func (s *PostService) Delete(ctx context.Context, id, authorId ksuid.KSUID) error {
-	return s.repos.Delete(ctx, id, authorId)
+	// Getting authenticated post author.
+	authorId, err := s.author(ctx, authorId)
+	if err != nil {
+		return err
+	}
+
+	// Deleting post.
+	if err := s.repos.Delete(ctx, id, authorId); err != nil {
+		return err
+	}
+
+	metrics.PostEventsTotal.WithLabelValues(string(domain.EventPostDeleted)).Inc()
+
+	return nil
 }
 
-// Updating a post.
-func (s *PostService) Update(ctx context.Context, post domain.Post) error {
-	// Validate a post.
-	if err := post.Validate(); err != nil {
+// Updating a post fields.
+func (s *PostService) Update(ctx context.Context, post domain.Post, mask domain.FieldMask) error {
+	var err error
+
+	// Getting authenticated post author.
+	post.AuthorId, err = s.author(ctx, post.AuthorId)
+	if err != nil {
+		return err
+	}
+
+	// Check is post text updated.
+	if mask.Has(domain.PostFieldText) {
+		// Validate a post.
+		if err := post.Validate(); err != nil {
+			return err
+		}
+
+		// Computing a post content fingerprint.
+		post.Fingerprint = domain.NewFingerprint(post.Text)
+	}
+
+	// Updating post.
+	if err := s.repos.Update(ctx, post, mask); err != nil {
 		return err
 	}
 
-	return s.repos.Update(ctx, post)
+	metrics.PostEventsTotal.WithLabelValues(string(domain.EventPostUpdated)).Inc()
+
+	return nil
 }
 
 // Getting total author posts count.
-func (s *PostService) GetTotalCount(ctx context.Context, authorId ksuid.KSUID) (int32, error) {
-	return s.repos.GetTotalCount(ctx, authorId)
+func (s *PostService) GetTotalCount(ctx context.Context, authorId ksuid.KSUID, filter domain.PostFilter) (int32, error) {
+	// Validate posts filter.
+	if err := filter.Validate(); err != nil {
+		return 0, err
+	}
+
+	return s.repos.GetTotalCount(ctx, authorId, filter)
+}
+
+// Finding posts similar to the post.
+func (s *PostService) FindSimilar(ctx context.Context, id ksuid.KSUID, limit int32) ([]domain.Post, error) {
+	max := s.cfg.Duplicate.Limit
+	if max <= 0 {
+		max = defaultSimilarLimit
+	}
+
+	// Check limit of similar posts.
+	if limit <= 0 || limit > max {
+		limit = max
+	}
+
+	// Getting a post text by id.
+	post, err := s.repos.Get(ctx, id, domain.FieldMask{domain.PostFieldText})
+	if err != nil {
+		return nil, err
+	}
+
+	opts := domain.SimilarOptions{Exclude: id, Distance: s.cfg.Duplicate.Distance, Limit: limit}
+
+	// Check is search window limited. Moderators search independently of
+	// the duplicate detection window on post creation.
+	if s.cfg.Duplicate.SearchWindow > 0 {
+		opts.Since = time.Now().Add(-s.cfg.Duplicate.SearchWindow)
+	}
+
+	return s.repos.FindSimilar(ctx, domain.NewFingerprint(post.Text), opts)
+}
+
+// Getting the post author of a request.
+//
+// The authenticated subject takes precedence over the requested author id,
+// which is only trusted when authentication is not required.
+func (s *PostService) author(ctx context.Context, authorId ksuid.KSUID) (ksuid.KSUID, error) {
+	// Check is request authenticated.
+	if subject, ok := auth.SubjectFromContext(ctx); ok {
+		return subject, nil
+	}
+
+	if s.cfg.RequireAuth {
+		return ksuid.Nil, &domain.Error{
+			Code:    domain.CodeUnauthenticated,
+			Message: "Authentication required",
+			Reason:  domain.ReasonAuthenticationRequired,
+		}
+	}
+
+	// Check is author id set.
+	if authorId.IsNil() {
+		return ksuid.Nil, &domain.Error{
+			Code:       domain.CodeInvalidArgument,
+			Message:    "Author is required",
+			Reason:     domain.ReasonInvalidArgument,
+			Violations: []domain.FieldViolation{{Field: "author_id", Description: "Field is required"}},
+		}
+	}
+
+	return authorId, nil
 }
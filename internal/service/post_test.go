@@ -19,17 +19,35 @@ package service_test
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/durudex/durudex-post-service/internal/auth"
+	"github.com/durudex/durudex-post-service/internal/config"
 	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/metrics"
 	mock_postgres "github.com/durudex/durudex-post-service/internal/repository/postgres/mock"
 	"github.com/durudex/durudex-post-service/internal/service"
 
 	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/segmentio/ksuid"
 )
 
+// Testing post service config.
+var testConfig = config.PostConfig{
+	Duplicate: config.DuplicateConfig{Window: time.Hour, SearchWindow: 24 * time.Hour, Distance: 6, Reject: true, Limit: 50},
+	Stream: config.StreamConfig{
+		Buffer:     16,
+		Batch:      100,
+		MaxAuthors: 10,
+		Retention:  time.Hour,
+		Reconnect:  time.Second,
+	},
+}
+
 // Testing creating a new post.
 func TestPostService_Create(t *testing.T) {
 	// Creating a new mock controller.
@@ -61,7 +79,40 @@ func TestPostService_Create(t *testing.T) {
 				Text:     "This is a test post.",
 			}},
 			mockBehavior: func(r *mock_postgres.MockPost, args args) {
-				r.EXPECT().Create(context.Background(), args.post).Return(nil)
+				post := args.post
+				post.Fingerprint = domain.NewFingerprint(post.Text)
+				post.CreatedAt = post.Id.Time()
+
+				r.EXPECT().FindSimilar(context.Background(), post.Fingerprint, gomock.Any()).Return(nil, nil)
+				r.EXPECT().Create(context.Background(), post).Return(nil)
+			},
+		},
+		{
+			name: "Duplicate",
+			args: args{domain.Post{
+				AuthorId: ksuid.New(),
+				Text:     "This is a test post.",
+			}},
+			wantErr: true,
+			mockBehavior: func(r *mock_postgres.MockPost, args args) {
+				r.EXPECT().FindSimilar(context.Background(), domain.NewFingerprint(args.post.Text), gomock.Any()).
+					Return([]domain.Post{{Id: ksuid.New()}}, nil)
+			},
+		},
+		{
+			name: "Punctuation only",
+			args: args{domain.Post{
+				Id:       ksuid.New(),
+				AuthorId: ksuid.New(),
+				Text:     "?!...",
+			}},
+			mockBehavior: func(r *mock_postgres.MockPost, args args) {
+				post := args.post
+				post.Fingerprint = domain.NewFingerprint(post.Text)
+				post.CreatedAt = post.Id.Time()
+
+				// Duplicates are not looked up.
+				r.EXPECT().Create(context.Background(), post).Return(nil)
 			},
 		},
 	}
@@ -73,16 +124,16 @@ func TestPostService_Create(t *testing.T) {
 			tt.mockBehavior(psql, tt.args)
 
 			// Creating a new post service.
-			service := service.NewPostService(psql)
+			service := service.NewPostService(psql, testConfig)
 
 			// Creating a new post.
 			id, err := service.Create(context.Background(), tt.args.post)
-			if err != nil {
-				t.Errorf("error creating post: %s", err.Error())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error creating post: %v", err)
 			}
 
 			// Check id is nil.
-			if id.IsNil() {
+			if id.IsNil() != tt.wantErr {
 				t.Error("post id is nil")
 			}
 		})
@@ -120,7 +171,7 @@ func TestPostService_Get(t *testing.T) {
 				Text:     "This is a test post.",
 			},
 			mockBehavior: func(r *mock_postgres.MockPost, args args, want domain.Post) {
-				r.EXPECT().Get(context.Background(), args.id).Return(want, nil)
+				r.EXPECT().Get(context.Background(), args.id, nil).Return(want, nil)
 			},
 		},
 	}
@@ -132,10 +183,10 @@ func TestPostService_Get(t *testing.T) {
 			tt.mockBehavior(psql, tt.args, tt.want)
 
 			// Creating a new post service.
-			service := service.NewPostService(psql)
+			service := service.NewPostService(psql, testConfig)
 
 			// Getting a post by id.
-			got, err := service.Get(context.Background(), tt.args.id)
+			got, err := service.Get(context.Background(), tt.args.id, nil)
 			if err != nil {
 				t.Errorf("error getting post by id: %s", err.Error())
 			}
@@ -148,6 +199,69 @@ func TestPostService_Get(t *testing.T) {
 	}
 }
 
+// Testing getting posts by ids.
+func TestPostService_GetByIds(t *testing.T) {
+	// Creating a new mock controller.
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// Creating a new mock repository.
+	psql := mock_postgres.NewMockPost(c)
+
+	// Testing args.
+	type args struct{ ids []ksuid.KSUID }
+
+	// Test behavior.
+	type mockBehavior func(r *mock_postgres.MockPost, args args, want []domain.Post)
+
+	id := ksuid.New()
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		args         args
+		want         []domain.Post
+		mockBehavior mockBehavior
+	}{
+		{
+			name: "OK",
+			args: args{ids: []ksuid.KSUID{id}},
+			want: []domain.Post{{Id: id, AuthorId: ksuid.New(), Text: "This is a test post."}},
+			mockBehavior: func(r *mock_postgres.MockPost, args args, want []domain.Post) {
+				r.EXPECT().GetByIds(context.Background(), args.ids).Return(want, nil)
+			},
+		},
+		{
+			name:         "Empty",
+			args:         args{ids: nil},
+			want:         []domain.Post{},
+			mockBehavior: func(r *mock_postgres.MockPost, args args, want []domain.Post) {},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setting a mock behavior.
+			tt.mockBehavior(psql, tt.args, tt.want)
+
+			// Creating a new post service.
+			service := service.NewPostService(psql, testConfig)
+
+			// Getting posts by ids.
+			got, err := service.GetByIds(context.Background(), tt.args.ids)
+			if err != nil {
+				t.Errorf("error getting posts by ids: %s", err.Error())
+			}
+
+			// Check for similarity of posts.
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Error("error posts are not similar")
+			}
+		})
+	}
+}
+
 // Testing getting author posts.
 func TestPostService_GetPosts(t *testing.T) {
 	// Creating a new mock controller.
@@ -161,6 +275,7 @@ func TestPostService_GetPosts(t *testing.T) {
 	type args struct {
 		authorId ksuid.KSUID
 		sort     domain.SortOptions
+		filter   domain.PostFilter
 	}
 
 	// Test behavior.
@@ -192,9 +307,19 @@ func TestPostService_GetPosts(t *testing.T) {
 				},
 			},
 			mockBehavior: func(r *mock_postgres.MockPost, args args, want []domain.Post) {
-				r.EXPECT().GetPosts(context.Background(), args.authorId, args.sort).Return(want, nil)
+				r.EXPECT().GetPosts(context.Background(), args.authorId, args.sort, args.filter, nil).Return(want, nil)
 			},
 		},
+		{
+			name: "Invalid time range",
+			args: args{
+				authorId: ksuid.New(),
+				sort:     domain.SortOptions{First: &filer},
+				filter:   domain.PostFilter{CreatedAfter: time.Now(), CreatedBefore: time.Now().Add(-time.Hour)},
+			},
+			wantErr:      true,
+			mockBehavior: func(r *mock_postgres.MockPost, args args, want []domain.Post) {},
+		},
 	}
 
 	// Conducting tests in various structures.
@@ -204,12 +329,12 @@ func TestPostService_GetPosts(t *testing.T) {
 			tt.mockBehavior(psql, tt.args, tt.want)
 
 			// Creating a new post service.
-			service := service.NewPostService(psql)
+			service := service.NewPostService(psql, testConfig)
 
 			// Getting a post by id.
-			got, err := service.GetPosts(context.Background(), tt.args.authorId, tt.args.sort)
-			if err != nil {
-				t.Errorf("error getting posts: %s", err.Error())
+			got, err := service.GetPosts(context.Background(), tt.args.authorId, tt.args.sort, tt.args.filter, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error getting posts: %v", err)
 			}
 
 			// Check for similarity of post.
@@ -240,6 +365,7 @@ func TestPostService_Delete(t *testing.T) {
 		name         string
 		args         args
 		wantErr      bool
+		wantEvents   float64
 		mockBehavior mockBehavior
 	}{
 		{
@@ -248,11 +374,24 @@ func TestPostService_Delete(t *testing.T) {
 				id:       ksuid.New(),
 				authorId: ksuid.New(),
 			},
-			wantErr: false,
+			wantErr:    false,
+			wantEvents: 1,
 			mockBehavior: func(r *mock_postgres.MockPost, args args) {
 				r.EXPECT().Delete(context.Background(), args.id, args.authorId).Return(nil)
 			},
 		},
+		{
+			name: "Not found",
+			args: args{
+				id:       ksuid.New(),
+				authorId: ksuid.New(),
+			},
+			wantErr: true,
+			mockBehavior: func(r *mock_postgres.MockPost, args args) {
+				r.EXPECT().Delete(context.Background(), args.id, args.authorId).
+					Return(&domain.Error{Code: domain.CodeNotFound, Message: "Post not found", Reason: domain.ReasonPostNotFound})
+			},
+		},
 	}
 
 	// Conducting tests in various structures.
@@ -262,13 +401,123 @@ func TestPostService_Delete(t *testing.T) {
 			tt.mockBehavior(psql, tt.args)
 
 			// Creating a new post service.
-			service := service.NewPostService(psql)
+			service := service.NewPostService(psql, testConfig)
+
+			events := metrics.PostEventsTotal.WithLabelValues(string(domain.EventPostDeleted))
+			before := testutil.ToFloat64(events)
 
 			// Deleting a post.
-			if err := service.Delete(context.Background(), tt.args.id, tt.args.authorId); err != nil {
-				if !tt.wantErr {
-					t.Errorf("error deleting a post: %s", err.Error())
-				}
+			if err := service.Delete(context.Background(), tt.args.id, tt.args.authorId); (err != nil) != tt.wantErr {
+				t.Errorf("error deleting a post: %v", err)
+			}
+
+			// Check for number of counted events.
+			if got := testutil.ToFloat64(events) - before; got != tt.wantEvents {
+				t.Errorf("error counted post events: got %v, want %v", got, tt.wantEvents)
+			}
+		})
+	}
+}
+
+// Testing using the authenticated subject as post author.
+func TestPostService_Authentication(t *testing.T) {
+	// Creating a new mock controller.
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// Creating a new mock repository.
+	psql := mock_postgres.NewMockPost(c)
+
+	subject := ksuid.New()
+
+	// Testing args.
+	type args struct {
+		ctx         context.Context
+		requireAuth bool
+		id          ksuid.KSUID
+		authorId    ksuid.KSUID
+	}
+
+	// Test behavior.
+	type mockBehavior func(r *mock_postgres.MockPost, args args)
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		args         args
+		wantErr      bool
+		wantCode     domain.Code
+		mockBehavior mockBehavior
+	}{
+		{
+			name: "Subject",
+			args: args{
+				ctx:         auth.NewContext(context.Background(), subject),
+				requireAuth: true,
+				id:          ksuid.New(),
+				authorId:    ksuid.New(),
+			},
+			mockBehavior: func(r *mock_postgres.MockPost, args args) {
+				r.EXPECT().Delete(args.ctx, args.id, subject).Return(nil)
+			},
+		},
+		{
+			name: "Unauthenticated",
+			args: args{
+				ctx:         context.Background(),
+				requireAuth: true,
+				id:          ksuid.New(),
+				authorId:    ksuid.New(),
+			},
+			wantErr:      true,
+			wantCode:     domain.CodeUnauthenticated,
+			mockBehavior: func(r *mock_postgres.MockPost, args args) {},
+		},
+		{
+			name: "Nil author",
+			args: args{
+				ctx: context.Background(),
+				id:  ksuid.New(),
+			},
+			wantErr:      true,
+			wantCode:     domain.CodeInvalidArgument,
+			mockBehavior: func(r *mock_postgres.MockPost, args args) {},
+		},
+		{
+			name: "Not required",
+			args: args{
+				ctx:      context.Background(),
+				id:       ksuid.New(),
+				authorId: ksuid.New(),
+			},
+			mockBehavior: func(r *mock_postgres.MockPost, args args) {
+				r.EXPECT().Delete(args.ctx, args.id, args.authorId).Return(nil)
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setting a mock behavior.
+			tt.mockBehavior(psql, tt.args)
+
+			cfg := testConfig
+			cfg.RequireAuth = tt.args.requireAuth
+
+			// Creating a new post service.
+			service := service.NewPostService(psql, cfg)
+
+			// Deleting a post.
+			err := service.Delete(tt.args.ctx, tt.args.id, tt.args.authorId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error deleting a post: %v", err)
+			}
+
+			// Check error code.
+			var e *domain.Error
+			if tt.wantErr && (!errors.As(err, &e) || e.Code != tt.wantCode) {
+				t.Errorf("error code: %v", err)
 			}
 		})
 	}
@@ -294,6 +543,7 @@ func TestPostService_Update(t *testing.T) {
 		name         string
 		args         args
 		wantErr      bool
+		wantEvents   float64
 		mockBehavior mockBehavior
 	}{
 		{
@@ -303,9 +553,28 @@ func TestPostService_Update(t *testing.T) {
 				AuthorId: ksuid.New(),
 				Text:     "This is a test post.",
 			}},
-			wantErr: false,
+			wantErr:    false,
+			wantEvents: 1,
 			mockBehavior: func(r *mock_postgres.MockPost, args args) {
-				r.EXPECT().Update(context.Background(), args.post).Return(nil)
+				post := args.post
+				post.Fingerprint = domain.NewFingerprint(post.Text)
+
+				r.EXPECT().Update(context.Background(), post, nil).Return(nil)
+			},
+		},
+		{
+			name: "Not found",
+			args: args{domain.Post{
+				Id:       ksuid.New(),
+				AuthorId: ksuid.New(),
+				Text:     "This is a test post.",
+			}},
+			wantErr: true,
+			mockBehavior: func(r *mock_postgres.MockPost, args args) {
+				post := args.post
+				post.Fingerprint = domain.NewFingerprint(post.Text)
+
+				r.EXPECT().Update(context.Background(), post, nil).Return(&domain.Error{Code: domain.CodeNotFound, Message: "Post not found", Reason: domain.ReasonPostNotFound})
 			},
 		},
 	}
@@ -317,13 +586,19 @@ func TestPostService_Update(t *testing.T) {
 			tt.mockBehavior(psql, tt.args)
 
 			// Creating a new post service.
-			service := service.NewPostService(psql)
+			service := service.NewPostService(psql, testConfig)
+
+			events := metrics.PostEventsTotal.WithLabelValues(string(domain.EventPostUpdated))
+			before := testutil.ToFloat64(events)
 
 			// Updating a post.
-			if err := service.Update(context.Background(), tt.args.post); err != nil {
-				if !tt.wantErr {
-					t.Errorf("error updating a post: %s", err.Error())
-				}
+			if err := service.Update(context.Background(), tt.args.post, nil); (err != nil) != tt.wantErr {
+				t.Errorf("error updating a post: %v", err)
+			}
+
+			// Check for number of counted events.
+			if got := testutil.ToFloat64(events) - before; got != tt.wantEvents {
+				t.Errorf("error counted post events: got %v, want %v", got, tt.wantEvents)
 			}
 		})
 	}
@@ -339,7 +614,10 @@ func TestPostService_GetTotalCount(t *testing.T) {
 	psql := mock_postgres.NewMockPost(c)
 
 	// Testing args.
-	type args struct{ authorId ksuid.KSUID }
+	type args struct {
+		authorId ksuid.KSUID
+		filter   domain.PostFilter
+	}
 
 	// Test behavior.
 	type mockBehavior func(r *mock_postgres.MockPost, args args, want int32)
@@ -357,9 +635,18 @@ func TestPostService_GetTotalCount(t *testing.T) {
 			args: args{authorId: ksuid.New()},
 			want: 10,
 			mockBehavior: func(r *mock_postgres.MockPost, args args, want int32) {
-				r.EXPECT().GetTotalCount(context.Background(), args.authorId).Return(want, nil)
+				r.EXPECT().GetTotalCount(context.Background(), args.authorId, args.filter).Return(want, nil)
 			},
 		},
+		{
+			name: "Invalid time range",
+			args: args{
+				authorId: ksuid.New(),
+				filter:   domain.PostFilter{CreatedAfter: time.Unix(10, 0), CreatedBefore: time.Unix(10, 0)},
+			},
+			wantErr:      true,
+			mockBehavior: func(r *mock_postgres.MockPost, args args, want int32) {},
+		},
 	}
 
 	// Conducting tests in various structures.
@@ -369,10 +656,10 @@ func TestPostService_GetTotalCount(t *testing.T) {
 			tt.mockBehavior(psql, tt.args, tt.want)
 
 			// Creating a new post service.
-			service := service.NewPostService(psql)
+			service := service.NewPostService(psql, testConfig)
 
 			// Getting total author posts count.
-			got, err := service.GetTotalCount(context.Background(), tt.args.authorId)
+			got, err := service.GetTotalCount(context.Background(), tt.args.authorId, tt.args.filter)
 			if err != nil {
 				if !tt.wantErr {
 					t.Errorf("error getting total author posts count: %s", err.Error())
@@ -386,3 +673,121 @@ func TestPostService_GetTotalCount(t *testing.T) {
 		})
 	}
 }
+
+// Testing finding posts similar to the post.
+func TestPostService_FindSimilar(t *testing.T) {
+	// Creating a new mock controller.
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// Creating a new mock repository.
+	psql := mock_postgres.NewMockPost(c)
+
+	// Testing args.
+	type args struct {
+		id    ksuid.KSUID
+		limit int32
+	}
+
+	// Test behavior.
+	type mockBehavior func(r *mock_postgres.MockPost, args args, want []domain.Post)
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		args         args
+		cfg          config.PostConfig
+		want         []domain.Post
+		wantErr      bool
+		mockBehavior mockBehavior
+	}{
+		{
+			name: "OK",
+			args: args{id: ksuid.New(), limit: 100},
+			cfg:  testConfig,
+			want: []domain.Post{
+				{
+					Id:       ksuid.New(),
+					AuthorId: ksuid.New(),
+					Text:     "This is a test post!",
+				},
+			},
+			mockBehavior: func(r *mock_postgres.MockPost, args args, want []domain.Post) {
+				post := domain.Post{AuthorId: ksuid.New(), Text: "This is a test post."}
+
+				r.EXPECT().Get(context.Background(), args.id, domain.FieldMask{domain.PostFieldText}).Return(post, nil)
+				r.EXPECT().FindSimilar(context.Background(), domain.NewFingerprint(post.Text), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ domain.Fingerprint, opts domain.SimilarOptions) ([]domain.Post, error) {
+						// Check similar posts options.
+						if opts.Exclude != args.id || opts.Limit != testConfig.Duplicate.Limit || opts.Since.IsZero() {
+							t.Error("error similar options are not valid")
+						}
+
+						return want, nil
+					})
+			},
+		},
+		{
+			name: "Duplicate detection disabled",
+			args: args{id: ksuid.New(), limit: 10},
+			cfg:  config.PostConfig{Duplicate: config.DuplicateConfig{Distance: 6, Limit: 50}},
+			want: []domain.Post{{Id: ksuid.New(), AuthorId: ksuid.New(), Text: "This is a test post!"}},
+			mockBehavior: func(r *mock_postgres.MockPost, args args, want []domain.Post) {
+				post := domain.Post{AuthorId: ksuid.New(), Text: "This is a test post."}
+
+				r.EXPECT().Get(context.Background(), args.id, domain.FieldMask{domain.PostFieldText}).Return(post, nil)
+				r.EXPECT().FindSimilar(context.Background(), domain.NewFingerprint(post.Text), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ domain.Fingerprint, opts domain.SimilarOptions) ([]domain.Post, error) {
+						// Check similar posts are searched without a time bound.
+						if !opts.Since.IsZero() || opts.Limit != args.limit {
+							t.Error("error similar options are not valid")
+						}
+
+						return want, nil
+					})
+			},
+		},
+		{
+			name: "Limit not configured",
+			args: args{id: ksuid.New()},
+			cfg:  config.PostConfig{Duplicate: config.DuplicateConfig{Distance: 6}},
+			want: []domain.Post{{Id: ksuid.New(), AuthorId: ksuid.New(), Text: "This is a test post!"}},
+			mockBehavior: func(r *mock_postgres.MockPost, args args, want []domain.Post) {
+				post := domain.Post{AuthorId: ksuid.New(), Text: "This is a test post."}
+
+				r.EXPECT().Get(context.Background(), args.id, domain.FieldMask{domain.PostFieldText}).Return(post, nil)
+				r.EXPECT().FindSimilar(context.Background(), domain.NewFingerprint(post.Text), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ domain.Fingerprint, opts domain.SimilarOptions) ([]domain.Post, error) {
+						// Check similar posts are limited by the default limit.
+						if opts.Limit != 50 {
+							t.Errorf("error similar posts limit: got %d, want 50", opts.Limit)
+						}
+
+						return want, nil
+					})
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setting a mock behavior.
+			tt.mockBehavior(psql, tt.args, tt.want)
+
+			// Creating a new post service.
+			service := service.NewPostService(psql, tt.cfg)
+
+			// Finding similar posts.
+			got, err := service.FindSimilar(context.Background(), tt.args.id, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error finding similar posts: %v", err)
+			}
+
+			// Check for similarity of posts.
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Error("error posts are not similar")
+			}
+		})
+	}
+}
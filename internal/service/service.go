@@ -17,12 +17,36 @@
 
 package service
 
-import "github.com/durudex/durudex-post-service/internal/repository"
+import (
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/publisher"
+	"github.com/durudex/durudex-post-service/internal/repository"
+
+	"go.opentelemetry.io/otel"
+)
 
 // Service structure.
-type Service struct{ Post }
+type Service struct {
+	Post
+	Fingerprint
+	Event
+	Outbox
+	Stats
+	Webhook
+	Health
+}
 
 // Creating a new service.
-func NewService(repos *repository.Repository) *Service {
-	return &Service{Post: NewPostService(repos.Postgres)}
+func NewService(repos *repository.Repository, pub publisher.EventPublisher, cfg *config.Config) *Service {
+	webhook := NewWebhookTracing(NewWebhookService(repos.Postgres, cfg.Webhook), otel.GetTracerProvider())
+
+	return &Service{
+		Post:        NewPostTracing(NewPostService(repos.Postgres, cfg.Post), otel.GetTracerProvider()),
+		Fingerprint: NewFingerprintService(repos.Postgres),
+		Event:       NewEventTracing(NewEventService(repos.Postgres, cfg.Post.Stream), otel.GetTracerProvider()),
+		Outbox:      NewOutboxService(repos.Postgres, publisher.NewMultiPublisher(pub, webhook), cfg.Outbox),
+		Stats:       NewStatsService(repos.Postgres, cfg.Stats),
+		Webhook:     webhook,
+		Health:      NewHealthService(repos.Postgres, cfg.Health),
+	}
 }
@@ -0,0 +1,125 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/metrics"
+	"github.com/durudex/durudex-post-service/internal/repository/postgres"
+
+	"github.com/rs/zerolog/log"
+	"github.com/segmentio/ksuid"
+)
+
+// Stats interface.
+type Stats interface {
+	// Repairing drifted author posts counts.
+	Reconcile(ctx context.Context) (int, error)
+	// Running author stats reconciliation worker.
+	Run(ctx context.Context)
+}
+
+// Stats service structure.
+type StatsService struct {
+	repos postgres.Stats
+	cfg   config.StatsConfig
+}
+
+// Creating a new stats service.
+func NewStatsService(repos postgres.Stats, cfg config.StatsConfig) *StatsService {
+	return &StatsService{repos: repos, cfg: cfg}
+}
+
+// Repairing drifted author posts counts.
+//
+// All authors with a stats row are reconciled, then stats rows of post authors
+// without one are created. Authors are processed in batches, each in its own
+// transaction, so the reconciliation does not hold the stats lock for the
+// whole pass.
+func (s *StatsService) Reconcile(ctx context.Context) (int, error) {
+	ctx, span := startJob(ctx, "StatsService.Reconcile")
+	defer span.End()
+
+	// Repairing drifted author posts counts.
+	n, err := s.reconcile(ctx, s.repos.ReconcileAuthorStats)
+	if err == nil {
+		var created int
+
+		// Creating missing author posts counts.
+		created, err = s.reconcile(ctx, s.repos.CreateMissingAuthorStats)
+		n += created
+	}
+
+	// Committed batches are repaired even if a later batch failed.
+	if n != 0 {
+		log.Warn().Int("authors", n).Msg("Repaired drifted author posts counts")
+		metrics.AuthorStatsRepairedTotal.Add(float64(n))
+	}
+
+	if err != nil {
+		recordError(span, err)
+
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// Reconciling all authors in batches. Returns the number of repaired authors of
+// the committed batches.
+func (s *StatsService) reconcile(ctx context.Context, batch func(ctx context.Context, after ksuid.KSUID, limit int32) (ksuid.KSUID, int, error)) (int, error) {
+	var (
+		after ksuid.KSUID
+		n     int
+		err   error
+	)
+
+	for {
+		var repaired int
+
+		// Reconciling the next authors batch.
+		after, repaired, err = batch(ctx, after, s.cfg.Batch)
+		n += repaired
+
+		if err != nil || after.IsNil() {
+			return n, err
+		}
+	}
+}
+
+// Running author stats reconciliation worker.
+func (s *StatsService) Run(ctx context.Context) {
+	log.Debug().Msg("Running author stats reconciliation worker...")
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Reconcile(ctx); err != nil && ctx.Err() == nil {
+				log.Error().Err(err).Msg("error reconciling author stats")
+			}
+		}
+	}
+}
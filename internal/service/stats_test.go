@@ -0,0 +1,115 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+	mock_postgres "github.com/durudex/durudex-post-service/internal/repository/postgres/mock"
+	"github.com/durudex/durudex-post-service/internal/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/segmentio/ksuid"
+)
+
+// Testing repairing drifted author posts counts.
+func TestStatsService_Reconcile(t *testing.T) {
+	// Creating a new mock controller.
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// Creating a new mock repository.
+	psql := mock_postgres.NewMockStats(c)
+
+	// Stats config.
+	cfg := config.StatsConfig{Interval: time.Hour, Batch: 100}
+
+	// Test behavior.
+	type mockBehavior func(r *mock_postgres.MockStats, want int)
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		want         int
+		wantErr      bool
+		mockBehavior mockBehavior
+	}{
+		{
+			name: "OK",
+			want: 3,
+			mockBehavior: func(r *mock_postgres.MockStats, want int) {
+				last := ksuid.New()
+
+				// Reconciling authors in batches.
+				gomock.InOrder(
+					r.EXPECT().ReconcileAuthorStats(gomock.Any(), ksuid.Nil, cfg.Batch).Return(last, 2, nil),
+					r.EXPECT().ReconcileAuthorStats(gomock.Any(), last, cfg.Batch).Return(ksuid.Nil, want-2, nil),
+					r.EXPECT().CreateMissingAuthorStats(gomock.Any(), ksuid.Nil, cfg.Batch).Return(ksuid.Nil, 0, nil),
+				)
+			},
+		},
+		{
+			name: "Missing",
+			want: 2,
+			mockBehavior: func(r *mock_postgres.MockStats, want int) {
+				last := ksuid.New()
+
+				// Creating missing authors in batches.
+				gomock.InOrder(
+					r.EXPECT().ReconcileAuthorStats(gomock.Any(), ksuid.Nil, cfg.Batch).Return(ksuid.Nil, 0, nil),
+					r.EXPECT().CreateMissingAuthorStats(gomock.Any(), ksuid.Nil, cfg.Batch).Return(last, 1, nil),
+					r.EXPECT().CreateMissingAuthorStats(gomock.Any(), last, cfg.Batch).Return(ksuid.Nil, want-1, nil),
+				)
+			},
+		},
+		{
+			name:    "Error",
+			wantErr: true,
+			mockBehavior: func(r *mock_postgres.MockStats, want int) {
+				r.EXPECT().ReconcileAuthorStats(gomock.Any(), ksuid.Nil, cfg.Batch).
+					Return(ksuid.Nil, 0, errors.New("reconcile failed"))
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Call mock behavior.
+			tt.mockBehavior(psql, tt.want)
+
+			// Creating a new stats service.
+			service := service.NewStatsService(psql, cfg)
+
+			// Repairing drifted author posts counts.
+			got, err := service.Reconcile(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error reconciling author stats: %v", err)
+			}
+
+			// Check for repaired authors.
+			if got != tt.want {
+				t.Errorf("error repaired count: got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
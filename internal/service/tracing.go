@@ -0,0 +1,256 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"context"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+
+	"github.com/segmentio/ksuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Service tracer name.
+const tracerName = "github.com/durudex/durudex-post-service/internal/service"
+
+// Post service tracing structure.
+//
+// Records a span for every post service method.
+type PostTracing struct {
+	service Post
+	tracer  trace.Tracer
+}
+
+// Creating a new post service tracing.
+func NewPostTracing(service Post, provider trace.TracerProvider) *PostTracing {
+	return &PostTracing{service: service, tracer: provider.Tracer(tracerName)}
+}
+
+// Creating a new post.
+func (t *PostTracing) Create(ctx context.Context, post domain.Post) (ksuid.KSUID, error) {
+	ctx, span := t.start(ctx, "PostService.Create", attribute.String("post.author_id", post.AuthorId.String()))
+	defer span.End()
+
+	id, err := t.service.Create(ctx, post)
+	recordError(span, err)
+
+	return id, err
+}
+
+// Getting a post.
+func (t *PostTracing) Get(ctx context.Context, id ksuid.KSUID, mask domain.FieldMask) (domain.Post, error) {
+	ctx, span := t.start(ctx, "PostService.Get", attribute.String("post.id", id.String()))
+	defer span.End()
+
+	post, err := t.service.Get(ctx, id, mask)
+	recordError(span, err)
+
+	return post, err
+}
+
+// Getting posts by ids.
+func (t *PostTracing) GetByIds(ctx context.Context, ids []ksuid.KSUID) ([]domain.Post, error) {
+	ctx, span := t.start(ctx, "PostService.GetByIds", attribute.Int("post.ids", len(ids)))
+	defer span.End()
+
+	posts, err := t.service.GetByIds(ctx, ids)
+	recordError(span, err)
+
+	return posts, err
+}
+
+// Getting author posts.
+func (t *PostTracing) GetPosts(ctx context.Context, authorId ksuid.KSUID, sort domain.SortOptions, filter domain.PostFilter, mask domain.FieldMask) ([]domain.Post, error) {
+	ctx, span := t.start(ctx, "PostService.GetPosts", attribute.String("post.author_id", authorId.String()))
+	defer span.End()
+
+	posts, err := t.service.GetPosts(ctx, authorId, sort, filter, mask)
+	recordError(span, err)
+
+	return posts, err
+}
+
+// Deleting a post.
+func (t *PostTracing) Delete(ctx context.Context, id, authorId ksuid.KSUID) error {
+	ctx, span := t.start(ctx, "PostService.Delete", attribute.String("post.id", id.String()))
+	defer span.End()
+
+	err := t.service.Delete(ctx, id, authorId)
+	recordError(span, err)
+
+	return err
+}
+
+// Updating a post.
+func (t *PostTracing) Update(ctx context.Context, post domain.Post, mask domain.FieldMask) error {
+	ctx, span := t.start(ctx, "PostService.Update", attribute.String("post.id", post.Id.String()))
+	defer span.End()
+
+	err := t.service.Update(ctx, post, mask)
+	recordError(span, err)
+
+	return err
+}
+
+// Getting total author posts count.
+func (t *PostTracing) GetTotalCount(ctx context.Context, authorId ksuid.KSUID, filter domain.PostFilter) (int32, error) {
+	ctx, span := t.start(ctx, "PostService.GetTotalCount", attribute.String("post.author_id", authorId.String()))
+	defer span.End()
+
+	count, err := t.service.GetTotalCount(ctx, authorId, filter)
+	recordError(span, err)
+
+	return count, err
+}
+
+// Finding posts similar to the post.
+func (t *PostTracing) FindSimilar(ctx context.Context, id ksuid.KSUID, limit int32) ([]domain.Post, error) {
+	ctx, span := t.start(ctx, "PostService.FindSimilar", attribute.String("post.id", id.String()))
+	defer span.End()
+
+	posts, err := t.service.FindSimilar(ctx, id, limit)
+	recordError(span, err)
+
+	return posts, err
+}
+
+// Starting a new service span.
+func (t *PostTracing) start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// Event service tracing structure.
+//
+// Records a span for every watch stream.
+type EventTracing struct {
+	Event
+	tracer trace.Tracer
+}
+
+// Creating a new event service tracing.
+func NewEventTracing(service Event, provider trace.TracerProvider) *EventTracing {
+	return &EventTracing{Event: service, tracer: provider.Tracer(tracerName)}
+}
+
+// Watching author post events after the cursor.
+func (t *EventTracing) Watch(ctx context.Context, authorIds []ksuid.KSUID, cursor *int64, send func(domain.PostEvent) error) error {
+	ctx, span := t.tracer.Start(ctx, "EventService.Watch", trace.WithAttributes(attribute.Int("post.author_ids", len(authorIds))))
+	defer span.End()
+
+	err := t.Event.Watch(ctx, authorIds, cursor, send)
+	recordError(span, err)
+
+	return err
+}
+
+// Webhook service tracing structure.
+//
+// Records a span for every webhook admin method and recorded post event,
+// deliveries are recorded by the delivery worker.
+type WebhookTracing struct {
+	Webhook
+	tracer trace.Tracer
+}
+
+// Creating a new webhook service tracing.
+func NewWebhookTracing(service Webhook, provider trace.TracerProvider) *WebhookTracing {
+	return &WebhookTracing{Webhook: service, tracer: provider.Tracer(tracerName)}
+}
+
+// Recording webhook deliveries of a post event.
+func (t *WebhookTracing) Publish(ctx context.Context, event domain.PostEvent) error {
+	ctx, span := t.tracer.Start(ctx, "WebhookService.Publish", trace.WithAttributes(attribute.String("post.id", event.Post.Id.String())))
+	defer span.End()
+
+	err := t.Webhook.Publish(ctx, event)
+	recordError(span, err)
+
+	return err
+}
+
+// Creating a new webhook.
+func (t *WebhookTracing) CreateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	ctx, span := t.tracer.Start(ctx, "WebhookService.CreateWebhook")
+	defer span.End()
+
+	webhook, err := t.Webhook.CreateWebhook(ctx, webhook)
+	recordError(span, err)
+
+	return webhook, err
+}
+
+// Getting all webhooks.
+func (t *WebhookTracing) GetWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	ctx, span := t.tracer.Start(ctx, "WebhookService.GetWebhooks")
+	defer span.End()
+
+	webhooks, err := t.Webhook.GetWebhooks(ctx)
+	recordError(span, err)
+
+	return webhooks, err
+}
+
+// Deleting a webhook.
+func (t *WebhookTracing) DeleteWebhook(ctx context.Context, id ksuid.KSUID) error {
+	ctx, span := t.tracer.Start(ctx, "WebhookService.DeleteWebhook", trace.WithAttributes(attribute.String("webhook.id", id.String())))
+	defer span.End()
+
+	err := t.Webhook.DeleteWebhook(ctx, id)
+	recordError(span, err)
+
+	return err
+}
+
+// Getting webhook deliveries.
+func (t *WebhookTracing) GetDeliveries(ctx context.Context, webhookId ksuid.KSUID, limit int32) ([]domain.WebhookDelivery, error) {
+	ctx, span := t.tracer.Start(ctx, "WebhookService.GetDeliveries", trace.WithAttributes(attribute.String("webhook.id", webhookId.String())))
+	defer span.End()
+
+	deliveries, err := t.Webhook.GetDeliveries(ctx, webhookId, limit)
+	recordError(span, err)
+
+	return deliveries, err
+}
+
+// Replaying a webhook delivery.
+func (t *WebhookTracing) ReplayDelivery(ctx context.Context, id ksuid.KSUID) error {
+	ctx, span := t.tracer.Start(ctx, "WebhookService.ReplayDelivery", trace.WithAttributes(attribute.String("webhook.delivery_id", id.String())))
+	defer span.End()
+
+	err := t.Webhook.ReplayDelivery(ctx, id)
+	recordError(span, err)
+
+	return err
+}
+
+// Starting a new background job span, the root span of the job queries.
+func startJob(ctx context.Context, name string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name)
+}
+
+// Recording span error.
+func recordError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,180 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/publisher"
+	mock_postgres "github.com/durudex/durudex-post-service/internal/repository/postgres/mock"
+	"github.com/durudex/durudex-post-service/internal/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/segmentio/ksuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Testing recording post service spans.
+func TestPostTracing_Get(t *testing.T) {
+	// Creating a new mock controller.
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// Creating a new mock repository.
+	psql := mock_postgres.NewMockPost(c)
+
+	// Creating a new tracer provider with span recorder.
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		err          error
+		wantStatus   codes.Code
+		mockBehavior func(r *mock_postgres.MockPost, id ksuid.KSUID, err error)
+	}{
+		{
+			name:       "OK",
+			wantStatus: codes.Unset,
+			mockBehavior: func(r *mock_postgres.MockPost, id ksuid.KSUID, err error) {
+				r.EXPECT().Get(gomock.Any(), id, nil).Return(domain.Post{}, nil)
+			},
+		},
+		{
+			name:       "Not Found",
+			err:        &domain.Error{Code: domain.CodeNotFound, Message: "Post not found"},
+			wantStatus: codes.Error,
+			mockBehavior: func(r *mock_postgres.MockPost, id ksuid.KSUID, err error) {
+				r.EXPECT().Get(gomock.Any(), id, nil).Return(domain.Post{}, err)
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := ksuid.New()
+
+			// Setting a mock behavior.
+			tt.mockBehavior(psql, id, tt.err)
+
+			// Creating a new traced post service.
+			s := service.NewPostTracing(service.NewPostService(psql, testConfig), provider)
+
+			// Starting a parent span.
+			ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+
+			// Getting a post by id.
+			if _, err := s.Get(ctx, id, nil); err != tt.err {
+				t.Errorf("error getting post by id: %v", err)
+			}
+
+			parent.End()
+
+			spans := recorder.Ended()
+			span := spans[len(spans)-2]
+
+			// Check for service span structure.
+			if span.Name() != "PostService.Get" || span.Parent().SpanID() != parent.SpanContext().SpanID() {
+				t.Errorf("error service span: %s", span.Name())
+			}
+
+			// Check for span status.
+			if span.Status().Code != tt.wantStatus {
+				t.Errorf("error span status: got %v, want %v", span.Status().Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// Testing recording webhook service spans.
+func TestWebhookTracing_DeleteWebhook(t *testing.T) {
+	// Creating a new mock controller.
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// Creating a new mock repository.
+	psql := mock_postgres.NewMockWebhook(c)
+
+	// Creating a new tracer provider with span recorder.
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	id := ksuid.New()
+	psql.EXPECT().DeleteWebhook(gomock.Any(), id).Return(nil)
+
+	// Creating a new traced webhook service.
+	s := service.NewWebhookTracing(service.NewWebhookService(psql, webhookConfig), provider)
+
+	// Deleting a webhook.
+	if err := s.DeleteWebhook(context.Background(), id); err != nil {
+		t.Errorf("error deleting webhook: %s", err.Error())
+	}
+
+	// Check for service span.
+	if spans := recorder.Ended(); len(spans) != 1 || spans[0].Name() != "WebhookService.DeleteWebhook" {
+		t.Errorf("error service spans: %v", spans)
+	}
+}
+
+// Testing recording background job spans.
+func TestOutboxService_RelaySpan(t *testing.T) {
+	// Creating a new mock controller.
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// Creating a new mock repository.
+	psql := mock_postgres.NewMockOutbox(c)
+
+	// Creating a new global tracer provider with span recorder.
+	recorder := tracetest.NewSpanRecorder()
+	provider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(provider)
+
+	psql.EXPECT().RelayOutbox(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _, _ int32, _ time.Duration, _ func(context.Context, domain.PostEvent) error) (int, error) {
+			// Check for the job span of the queries.
+			if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+				t.Error("error relay context has no span")
+			}
+
+			return 0, errors.New("relay failed")
+		})
+
+	// Relaying pending outbox events.
+	if _, err := service.NewOutboxService(psql, publisher.NewMemoryPublisher(), config.OutboxConfig{Batch: 10}).Relay(context.Background()); err == nil {
+		t.Error("error relay error is not returned")
+	}
+
+	// Check for job span status.
+	if spans := recorder.Ended(); len(spans) != 1 || spans[0].Name() != "OutboxService.Relay" ||
+		spans[0].Status().Code != codes.Error {
+		t.Errorf("error job spans: %v", spans)
+	}
+}
@@ -0,0 +1,400 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/publisher"
+	"github.com/durudex/durudex-post-service/internal/repository/postgres"
+	"github.com/durudex/durudex-post-service/pkg/webhook"
+
+	"github.com/rs/zerolog/log"
+	"github.com/segmentio/ksuid"
+)
+
+// Webhook interface.
+type Webhook interface {
+	// Recording webhook deliveries of a post event.
+	publisher.EventPublisher
+	// Creating a new webhook.
+	CreateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error)
+	// Getting all webhooks.
+	GetWebhooks(ctx context.Context) ([]domain.Webhook, error)
+	// Deleting a webhook.
+	DeleteWebhook(ctx context.Context, id ksuid.KSUID) error
+	// Getting webhook deliveries.
+	GetDeliveries(ctx context.Context, webhookId ksuid.KSUID, limit int32) ([]domain.WebhookDelivery, error)
+	// Replaying a webhook delivery.
+	ReplayDelivery(ctx context.Context, id ksuid.KSUID) error
+	// Sending due webhook deliveries.
+	Deliver(ctx context.Context) (int, error)
+	// Running webhook delivery worker.
+	Run(ctx context.Context)
+}
+
+// Webhook service structure.
+type WebhookService struct {
+	repos  postgres.Webhook
+	client *http.Client
+	cfg    config.WebhookConfig
+}
+
+// Creating a new webhook service.
+func NewWebhookService(repos postgres.Webhook, cfg config.WebhookConfig) *WebhookService {
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+
+	return &WebhookService{repos: repos, client: &http.Client{Timeout: cfg.Timeout}, cfg: cfg}
+}
+
+// Webhook payload structure.
+type webhookPayload struct {
+	Id        int64            `json:"id"`
+	Type      domain.EventType `json:"type"`
+	Post      webhookPost      `json:"post"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// Webhook payload post structure.
+type webhookPost struct {
+	Id       string `json:"id"`
+	AuthorId string `json:"author_id"`
+	Text     string `json:"text,omitempty"`
+}
+
+// Creating a new webhook.
+func (s *WebhookService) CreateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	var err error
+
+	// Validate a webhook.
+	if err := webhook.Validate(); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	// Generating a new webhook id.
+	webhook.Id, err = ksuid.NewRandom()
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	// Generating a new webhook secret.
+	if webhook.Secret == "" {
+		secret := make([]byte, 32)
+
+		if _, err := rand.Read(secret); err != nil {
+			return domain.Webhook{}, err
+		}
+
+		webhook.Secret = hex.EncodeToString(secret)
+	}
+
+	// Creating a new webhook.
+	if err := s.repos.CreateWebhook(ctx, webhook); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	return webhook, nil
+}
+
+// Getting all webhooks.
+func (s *WebhookService) GetWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	return s.repos.GetWebhooks(ctx)
+}
+
+// Deleting a webhook.
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id ksuid.KSUID) error {
+	return s.repos.DeleteWebhook(ctx, id)
+}
+
+// Getting webhook deliveries.
+func (s *WebhookService) GetDeliveries(ctx context.Context, webhookId ksuid.KSUID, limit int32) ([]domain.WebhookDelivery, error) {
+	// Check deliveries limit.
+	if limit <= 0 || limit > s.cfg.Batch {
+		limit = s.cfg.Batch
+	}
+
+	return s.repos.GetDeliveries(ctx, webhookId, limit)
+}
+
+// Replaying a webhook delivery.
+func (s *WebhookService) ReplayDelivery(ctx context.Context, id ksuid.KSUID) error {
+	return s.repos.ReplayDelivery(ctx, id)
+}
+
+// Recording webhook deliveries of a post event.
+func (s *WebhookService) Publish(ctx context.Context, event domain.PostEvent) error {
+	// Getting all webhooks.
+	webhooks, err := s.repos.GetWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Encoding webhook payload.
+	payload, err := json.Marshal(webhookPayload{
+		Id:   event.Id,
+		Type: event.Type,
+		Post: webhookPost{
+			Id:       event.Post.Id.String(),
+			AuthorId: event.Post.AuthorId.String(),
+			Text:     event.Post.Text,
+		},
+		CreatedAt: event.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	var deliveries []domain.WebhookDelivery
+
+	for _, w := range webhooks {
+		// Check is the webhook subscribed to the event.
+		if !w.Accepts(event.Type) {
+			continue
+		}
+
+		// Generating a new delivery id.
+		id, err := ksuid.NewRandom()
+		if err != nil {
+			return err
+		}
+
+		deliveries = append(deliveries, domain.WebhookDelivery{
+			Id:        id,
+			WebhookId: w.Id,
+			EventId:   event.Id,
+			EventType: event.Type,
+			Payload:   payload,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return s.repos.CreateDeliveries(ctx, deliveries)
+}
+
+// Closing the webhook publisher.
+func (s *WebhookService) Close() error { return nil }
+
+// Sending due webhook deliveries.
+//
+// Claimed deliveries are sent concurrently and leased for as long as sending
+// the batch can take, so that other instances do not claim them again.
+// Failed deliveries are retried with an exponential backoff and marked as
+// failed after the maximum number of attempts. Deliveries of deleted webhooks
+// are marked as failed without sending, so they are not claimed again.
+// Returns the number of claimed deliveries.
+func (s *WebhookService) Deliver(ctx context.Context) (int, error) {
+	ctx, span := startJob(ctx, "WebhookService.Deliver")
+	defer span.End()
+
+	n, err := s.deliverBatch(ctx)
+	recordError(span, err)
+
+	return n, err
+}
+
+// Sending a batch of claimed due webhook deliveries.
+func (s *WebhookService) deliverBatch(ctx context.Context) (int, error) {
+	// Claiming due pending webhook deliveries.
+	deliveries, err := s.repos.ClaimDeliveries(ctx, s.cfg.Batch, s.lease())
+	if err != nil || len(deliveries) == 0 {
+		return 0, err
+	}
+
+	// Getting all webhooks.
+	webhooks, err := s.repos.GetWebhooks(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	targets := make(map[ksuid.KSUID]domain.Webhook, len(webhooks))
+
+	for _, w := range webhooks {
+		targets[w.Id] = w
+	}
+
+	var (
+		wg    sync.WaitGroup
+		once  sync.Once
+		first error
+	)
+
+	// Limiting concurrent deliveries.
+	sem := make(chan struct{}, s.cfg.Concurrency)
+
+	for _, d := range deliveries {
+		w, ok := targets[d.WebhookId]
+		if !ok {
+			// Abandoning a delivery of a deleted webhook.
+			if err := s.abandon(ctx, d); err != nil {
+				once.Do(func() { first = err })
+			}
+
+			continue
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+
+		go func(w domain.Webhook, d domain.WebhookDelivery) {
+			defer func() { <-sem; wg.Done() }()
+
+			if err := s.deliver(ctx, w, d); err != nil {
+				once.Do(func() { first = err })
+			}
+		}(w, d)
+	}
+
+	wg.Wait()
+
+	if first != nil {
+		return 0, first
+	}
+
+	return len(deliveries), nil
+}
+
+// Getting the lease of claimed deliveries. Deliveries are sent in rounds of
+// the concurrent deliveries, each taking up to the request timeout, with one
+// more timeout left for updating them.
+func (s *WebhookService) lease() time.Duration {
+	rounds := (s.cfg.Batch + s.cfg.Concurrency - 1) / s.cfg.Concurrency
+
+	return time.Duration(rounds+1) * s.cfg.Timeout
+}
+
+// Sending a webhook delivery and updating its status.
+func (s *WebhookService) deliver(ctx context.Context, w domain.Webhook, d domain.WebhookDelivery) error {
+	d.Attempts++
+
+	// Sending a webhook request.
+	code, err := s.send(ctx, w, d)
+	d.ResponseCode = int32(code)
+
+	switch {
+	case err == nil:
+		d.Status, d.Error = domain.DeliverySucceeded, ""
+	case d.Attempts >= s.cfg.MaxAttempts:
+		d.Status, d.Error = domain.DeliveryFailed, err.Error()
+	default:
+		d.Error = err.Error()
+		d.NextAttemptAt = time.Now().Add(s.backoff(d.Attempts))
+	}
+
+	// Updating a webhook delivery.
+	return s.repos.UpdateDelivery(ctx, d)
+}
+
+// Marking a delivery of a deleted webhook as failed.
+func (s *WebhookService) abandon(ctx context.Context, d domain.WebhookDelivery) error {
+	d.Status, d.Error = domain.DeliveryFailed, "webhook deleted"
+
+	// Updating a webhook delivery.
+	return s.repos.UpdateDelivery(ctx, d)
+}
+
+// Sending a signed webhook request.
+func (s *WebhookService) send(ctx context.Context, w domain.Webhook, d domain.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+
+	// Set webhook request headers.
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.EventHeader, string(d.EventType))
+	req.Header.Set(webhook.DeliveryHeader, d.Id.String())
+	req.Header.Set(webhook.TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(w.Secret, timestamp, d.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Check webhook response status.
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status: %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Getting a delay before the next delivery attempt.
+func (s *WebhookService) backoff(attempts int32) time.Duration {
+	delay := s.cfg.Backoff
+
+	for i := int32(1); i < attempts && delay < s.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > s.cfg.MaxBackoff {
+		delay = s.cfg.MaxBackoff
+	}
+
+	return delay
+}
+
+// Running webhook delivery worker.
+func (s *WebhookService) Run(ctx context.Context) {
+	log.Debug().Msg("Running webhook delivery worker...")
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Deliver while there are full batches of deliveries.
+			for {
+				n, err := s.Deliver(ctx)
+				if err != nil {
+					if ctx.Err() == nil {
+						log.Error().Err(err).Msg("error sending webhook deliveries")
+					}
+
+					break
+				}
+
+				if n < int(s.cfg.Batch) {
+					break
+				}
+			}
+		}
+	}
+}
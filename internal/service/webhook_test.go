@@ -0,0 +1,367 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/domain"
+	mock_postgres "github.com/durudex/durudex-post-service/internal/repository/postgres/mock"
+	"github.com/durudex/durudex-post-service/internal/service"
+	"github.com/durudex/durudex-post-service/pkg/webhook"
+
+	"github.com/golang/mock/gomock"
+	"github.com/segmentio/ksuid"
+)
+
+// Webhook service test config.
+var webhookConfig = config.WebhookConfig{
+	Interval:    time.Second,
+	Batch:       10,
+	Timeout:     time.Second,
+	Concurrency: 5,
+	MaxAttempts: 3,
+	Backoff:     time.Minute,
+	MaxBackoff:  time.Hour,
+}
+
+// Testing creating a new webhook.
+func TestWebhookService_CreateWebhook(t *testing.T) {
+	// Creating a new mock controller.
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// Creating a new mock repository.
+	psql := mock_postgres.NewMockWebhook(c)
+
+	// Testing args.
+	type args struct{ webhook domain.Webhook }
+
+	// Test behavior.
+	type mockBehavior func(r *mock_postgres.MockWebhook)
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		args         args
+		wantErr      bool
+		mockBehavior mockBehavior
+	}{
+		{
+			name: "OK",
+			args: args{webhook: domain.Webhook{URL: "https://example.com/hook"}},
+			mockBehavior: func(r *mock_postgres.MockWebhook) {
+				r.EXPECT().CreateWebhook(context.Background(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:         "Invalid URL",
+			args:         args{webhook: domain.Webhook{URL: "ftp://example.com"}},
+			wantErr:      true,
+			mockBehavior: func(r *mock_postgres.MockWebhook) {},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Call mock behavior.
+			tt.mockBehavior(psql)
+
+			// Creating a new webhook service.
+			service := service.NewWebhookService(psql, webhookConfig)
+
+			// Creating a new webhook.
+			got, err := service.CreateWebhook(context.Background(), tt.args.webhook)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error creating webhook: %v", err)
+			}
+
+			// Check is webhook id and secret generated.
+			if !tt.wantErr && (got.Id.IsNil() || got.Secret == "") {
+				t.Error("error webhook id or secret is empty")
+			}
+		})
+	}
+}
+
+// Testing recording webhook deliveries of a post event.
+func TestWebhookService_Publish(t *testing.T) {
+	// Creating a new mock controller.
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// Creating a new mock repository.
+	psql := mock_postgres.NewMockWebhook(c)
+
+	subscribed := domain.Webhook{Id: ksuid.New(), Events: []domain.EventType{domain.EventPostCreated}}
+	other := domain.Webhook{Id: ksuid.New(), Events: []domain.EventType{domain.EventPostDeleted}}
+
+	event := domain.PostEvent{
+		Id:   7,
+		Type: domain.EventPostCreated,
+		Post: domain.Post{Id: ksuid.New(), AuthorId: ksuid.New(), Text: "Hello world!"},
+	}
+
+	psql.EXPECT().GetWebhooks(gomock.Any()).Return([]domain.Webhook{subscribed, other}, nil)
+	psql.EXPECT().CreateDeliveries(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, deliveries []domain.WebhookDelivery) error {
+			// Check for recorded deliveries.
+			if len(deliveries) != 1 || deliveries[0].WebhookId != subscribed.Id || deliveries[0].EventId != event.Id {
+				t.Errorf("error webhook deliveries: %v", deliveries)
+			}
+
+			var payload struct {
+				Id   int64                  `json:"id"`
+				Type string                 `json:"type"`
+				Post map[string]interface{} `json:"post"`
+			}
+
+			// Decoding delivery payload.
+			if err := json.Unmarshal(deliveries[0].Payload, &payload); err != nil {
+				t.Fatalf("error decoding payload: %s", err.Error())
+			}
+
+			// Check for similarity of a payload.
+			if payload.Id != event.Id || payload.Type != string(event.Type) || payload.Post["id"] != event.Post.Id.String() {
+				t.Errorf("error payload is not similar: %s", deliveries[0].Payload)
+			}
+
+			return nil
+		})
+
+	// Creating a new webhook service.
+	service := service.NewWebhookService(psql, webhookConfig)
+
+	// Recording webhook deliveries of a post event.
+	if err := service.Publish(context.Background(), event); err != nil {
+		t.Errorf("error publishing post event: %s", err.Error())
+	}
+}
+
+// Testing sending due webhook deliveries.
+func TestWebhookService_Deliver(t *testing.T) {
+	// Creating a new mock controller.
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// Creating a new mock repository.
+	psql := mock_postgres.NewMockWebhook(c)
+
+	// Testing args.
+	type args struct {
+		status   int
+		attempts int32
+	}
+
+	// Tests structures.
+	tests := []struct {
+		name       string
+		args       args
+		wantStatus domain.DeliveryStatus
+		wantRetry  bool
+	}{
+		{
+			name:       "OK",
+			args:       args{status: http.StatusOK},
+			wantStatus: domain.DeliverySucceeded,
+		},
+		{
+			name:       "Retry",
+			args:       args{status: http.StatusInternalServerError},
+			wantStatus: domain.DeliveryPending,
+			wantRetry:  true,
+		},
+		{
+			name:       "Failed",
+			args:       args{status: http.StatusInternalServerError, attempts: 2},
+			wantStatus: domain.DeliveryFailed,
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := "secret"
+			payload := []byte(`{"id":1,"type":"post.created"}`)
+
+			// Creating a new webhook receiver.
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.TimestampHeader), 10, 64)
+
+				// Verifying a webhook request signature.
+				if !webhook.Verify(secret, r.Header.Get(webhook.SignatureHeader), timestamp, body) {
+					t.Error("error webhook signature is not valid")
+				}
+
+				// Check webhook event header.
+				if r.Header.Get(webhook.EventHeader) != string(domain.EventPostCreated) {
+					t.Error("error webhook event header is not similar")
+				}
+
+				w.WriteHeader(tt.args.status)
+			}))
+			defer srv.Close()
+
+			target := domain.Webhook{Id: ksuid.New(), URL: srv.URL, Secret: secret}
+			delivery := domain.WebhookDelivery{
+				Id:        ksuid.New(),
+				WebhookId: target.Id,
+				EventId:   1,
+				EventType: domain.EventPostCreated,
+				Payload:   payload,
+				Status:    domain.DeliveryPending,
+				Attempts:  tt.args.attempts,
+			}
+
+			psql.EXPECT().ClaimDeliveries(gomock.Any(), webhookConfig.Batch, 3*webhookConfig.Timeout).
+				Return([]domain.WebhookDelivery{delivery}, nil)
+			psql.EXPECT().GetWebhooks(gomock.Any()).Return([]domain.Webhook{target}, nil)
+			psql.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, got domain.WebhookDelivery) error {
+					// Check for updated delivery.
+					if got.Status != tt.wantStatus || got.Attempts != tt.args.attempts+1 ||
+						got.ResponseCode != int32(tt.args.status) {
+						t.Errorf("error delivery is not similar: %+v", got)
+					}
+
+					// Check is delivery postponed.
+					if tt.wantRetry && !got.NextAttemptAt.After(time.Now()) {
+						t.Error("error delivery retry is not postponed")
+					}
+
+					return nil
+				})
+
+			// Creating a new webhook service.
+			service := service.NewWebhookService(psql, webhookConfig)
+
+			// Sending due webhook deliveries.
+			n, err := service.Deliver(context.Background())
+			if err != nil || n != 1 {
+				t.Errorf("error sending webhook deliveries: %d, %v", n, err)
+			}
+		})
+	}
+}
+
+// Testing abandoning deliveries of a deleted webhook.
+func TestWebhookService_DeliverDeleted(t *testing.T) {
+	// Creating a new mock controller.
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// Creating a new mock repository.
+	psql := mock_postgres.NewMockWebhook(c)
+
+	delivery := domain.WebhookDelivery{
+		Id:        ksuid.New(),
+		WebhookId: ksuid.New(),
+		EventType: domain.EventPostCreated,
+		Status:    domain.DeliveryPending,
+	}
+
+	psql.EXPECT().ClaimDeliveries(gomock.Any(), webhookConfig.Batch, 3*webhookConfig.Timeout).
+		Return([]domain.WebhookDelivery{delivery}, nil)
+	psql.EXPECT().GetWebhooks(gomock.Any()).Return(nil, nil)
+	psql.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, got domain.WebhookDelivery) error {
+			// Check for abandoned delivery.
+			if got.Id != delivery.Id || got.Status != domain.DeliveryFailed || got.Attempts != 0 {
+				t.Errorf("error delivery is not abandoned: %+v", got)
+			}
+
+			return nil
+		})
+
+	// Creating a new webhook service.
+	service := service.NewWebhookService(psql, webhookConfig)
+
+	// Sending due webhook deliveries.
+	n, err := service.Deliver(context.Background())
+	if err != nil || n != 1 {
+		t.Errorf("error sending webhook deliveries: %d, %v", n, err)
+	}
+}
+
+// Testing sending webhook deliveries concurrently.
+func TestWebhookService_DeliverConcurrency(t *testing.T) {
+	// Creating a new mock controller.
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// Creating a new mock repository.
+	psql := mock_postgres.NewMockWebhook(c)
+
+	var (
+		mu              sync.Mutex
+		inFlight, maxIn int
+	)
+
+	// Creating a new webhook receiver counting concurrent requests.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxIn {
+			maxIn = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(50 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	target := domain.Webhook{Id: ksuid.New(), URL: srv.URL, Secret: "secret"}
+	deliveries := make([]domain.WebhookDelivery, webhookConfig.Batch)
+
+	for i := range deliveries {
+		deliveries[i] = domain.WebhookDelivery{Id: ksuid.New(), WebhookId: target.Id, EventType: domain.EventPostCreated}
+	}
+
+	psql.EXPECT().ClaimDeliveries(gomock.Any(), webhookConfig.Batch, 3*webhookConfig.Timeout).Return(deliveries, nil)
+	psql.EXPECT().GetWebhooks(gomock.Any()).Return([]domain.Webhook{target}, nil)
+	psql.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).Return(nil).Times(len(deliveries))
+
+	// Creating a new webhook service.
+	service := service.NewWebhookService(psql, webhookConfig)
+
+	// Sending due webhook deliveries.
+	n, err := service.Deliver(context.Background())
+	if err != nil || n != len(deliveries) {
+		t.Fatalf("error sending webhook deliveries: %d, %v", n, err)
+	}
+
+	// Check for concurrent deliveries within the limit.
+	if maxIn < 2 || maxIn > int(webhookConfig.Concurrency) {
+		t.Errorf("error concurrent deliveries: got %d, want from 2 to %d", maxIn, webhookConfig.Concurrency)
+	}
+}
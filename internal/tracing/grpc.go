@@ -0,0 +1,161 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// gRPC tracer name.
+const tracerName = "github.com/durudex/durudex-post-service/internal/tracing"
+
+// gRPC metadata text map carrier.
+type metadataCarrier metadata.MD
+
+// Getting metadata value by key.
+func (c metadataCarrier) Get(key string) string {
+	if v := metadata.MD(c).Get(key); len(v) != 0 {
+		return v[0]
+	}
+
+	return ""
+}
+
+// Setting metadata value by key.
+func (c metadataCarrier) Set(key, value string) { metadata.MD(c).Set(key, value) }
+
+// Getting metadata keys.
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// Unary gRPC server tracing interceptor.
+func UnaryServerInterceptor(provider trace.TracerProvider, propagator propagation.TextMapPropagator) grpc.UnaryServerInterceptor {
+	tracer := provider.Tracer(tracerName)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, span := startServerSpan(ctx, tracer, propagator, info.FullMethod)
+		defer span.End()
+
+		// Call the handler.
+		res, err := handler(ctx, req)
+		setStatus(span, err)
+
+		return res, err
+	}
+}
+
+// Stream gRPC server tracing interceptor.
+func StreamServerInterceptor(provider trace.TracerProvider, propagator propagation.TextMapPropagator) grpc.StreamServerInterceptor {
+	tracer := provider.Tracer(tracerName)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := startServerSpan(ss.Context(), tracer, propagator, info.FullMethod)
+		defer span.End()
+
+		// Call the handler.
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		setStatus(span, err)
+
+		return err
+	}
+}
+
+// Getting the global tracer provider and propagator interceptors.
+func ServerInterceptors() (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+
+	return UnaryServerInterceptor(provider, propagator), StreamServerInterceptor(provider, propagator)
+}
+
+// Reporting a recovered gRPC handler panic as an exception event of the request
+// span.
+func ReportPanic(ctx context.Context, method string, recovered interface{}, stack []byte) {
+	trace.SpanFromContext(ctx).AddEvent(semconv.ExceptionEventName, trace.WithAttributes(
+		semconv.ExceptionTypeKey.String(fmt.Sprintf("%T", recovered)),
+		semconv.ExceptionMessageKey.String(fmt.Sprint(recovered)),
+		semconv.ExceptionStacktraceKey.String(string(stack)),
+	))
+}
+
+// Traced server stream structure.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Getting traced stream context.
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+// Starting a new server span continuing the incoming trace context.
+func startServerSpan(ctx context.Context, tracer trace.Tracer, propagator propagation.TextMapPropagator, method string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	// Extracting trace context from incoming metadata.
+	ctx = propagator.Extract(ctx, metadataCarrier(md))
+
+	return tracer.Start(ctx, strings.TrimPrefix(method, "/"),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(append(methodAttributes(method), semconv.RPCSystemKey.String("grpc"))...),
+	)
+}
+
+// Getting rpc service and method attributes.
+func methodAttributes(method string) []attribute.KeyValue {
+	method = strings.TrimPrefix(method, "/")
+
+	i := strings.LastIndexByte(method, '/')
+	if i == -1 {
+		return []attribute.KeyValue{semconv.RPCMethodKey.String(method)}
+	}
+
+	return []attribute.KeyValue{
+		semconv.RPCServiceKey.String(method[:i]),
+		semconv.RPCMethodKey.String(method[i+1:]),
+	}
+}
+
+// Setting span status by gRPC status code.
+func setStatus(span trace.Span, err error) {
+	st, _ := status.FromError(err)
+
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int64(int64(st.Code())))
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, st.Message())
+	}
+}
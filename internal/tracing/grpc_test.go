@@ -0,0 +1,153 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/internal/tracing"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Testing continuing incoming trace context in unary gRPC server spans.
+func TestUnaryServerInterceptor(t *testing.T) {
+	// Creating a new tracer provider with span recorder.
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	interceptor := tracing.UnaryServerInterceptor(provider, propagation.TraceContext{})
+
+	// Incoming W3C trace context.
+	traceId := "4bf92f3577b34da6a3ce929d0e0e4736"
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"traceparent", "00-"+traceId+"-00f067aa0ba902b7-01",
+	))
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/durudex.v1.PostService/GetPost"}
+
+	var child trace.SpanContext
+
+	// Call the interceptor with a handler starting a child span.
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		_, span := provider.Tracer("test").Start(ctx, "PostService.Get")
+		defer span.End()
+
+		child = span.SpanContext()
+
+		return nil, status.Error(grpccodes.NotFound, "Post not found")
+	})
+	if status.Code(err) != grpccodes.NotFound {
+		t.Fatalf("error calling interceptor: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("error span count: got %d, want 2", len(spans))
+	}
+
+	server := spans[1]
+
+	// Check for server span.
+	if server.Name() != "durudex.v1.PostService/GetPost" || server.SpanKind() != trace.SpanKindServer {
+		t.Errorf("error server span: %s, %s", server.Name(), server.SpanKind())
+	}
+
+	// Check for continued remote trace.
+	if server.SpanContext().TraceID().String() != traceId || !server.Parent().IsRemote() {
+		t.Errorf("error server span does not continue the incoming trace: %s", server.SpanContext().TraceID())
+	}
+
+	// Check for child span of the server span.
+	if spans[0].Parent().SpanID() != server.SpanContext().SpanID() || child.TraceID() != server.SpanContext().TraceID() {
+		t.Error("error handler span is not a child of the server span")
+	}
+
+	// Check for span status.
+	if server.Status().Code != codes.Error {
+		t.Errorf("error server span status: %v", server.Status())
+	}
+
+	// Check for rpc attributes.
+	want := map[string]string{
+		string(semconv.RPCSystemKey):  "grpc",
+		string(semconv.RPCServiceKey): "durudex.v1.PostService",
+		string(semconv.RPCMethodKey):  "GetPost",
+	}
+
+	for _, attr := range server.Attributes() {
+		if v, ok := want[string(attr.Key)]; ok {
+			if attr.Value.AsString() != v {
+				t.Errorf("error attribute %s: got %q, want %q", attr.Key, attr.Value.AsString(), v)
+			}
+
+			delete(want, string(attr.Key))
+		}
+	}
+
+	if len(want) != 0 {
+		t.Errorf("error missing attributes: %v", want)
+	}
+}
+
+// Testing reporting recovered panics as request span exception events.
+func TestReportPanic(t *testing.T) {
+	// Creating a new tracer provider with span recorder.
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "durudex.v1.PostService/GetPost")
+	tracing.ReportPanic(ctx, "/durudex.v1.PostService/GetPost", "nil pointer", []byte("goroutine 1"))
+	span.End()
+
+	events := recorder.Ended()[0].Events()
+	if len(events) != 1 || events[0].Name != semconv.ExceptionEventName {
+		t.Fatalf("error span events: %v", events)
+	}
+
+	// Check for exception attributes.
+	want := map[string]string{
+		string(semconv.ExceptionTypeKey):       "string",
+		string(semconv.ExceptionMessageKey):    "nil pointer",
+		string(semconv.ExceptionStacktraceKey): "goroutine 1",
+	}
+
+	for _, attr := range events[0].Attributes {
+		if v, ok := want[string(attr.Key)]; ok {
+			if attr.Value.AsString() != v {
+				t.Errorf("error attribute %s: got %q, want %q", attr.Key, attr.Value.AsString(), v)
+			}
+
+			delete(want, string(attr.Key))
+		}
+	}
+
+	if len(want) != 0 {
+		t.Errorf("error missing attributes: %v", want)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package connect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/transport/grpc"
+	"github.com/durudex/durudex-post-service/pkg/requestid"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+)
+
+// Connect access log interceptor structure.
+type accessLogger struct{ access *grpc.AccessLogger }
+
+// Wrapping unary handler calls.
+func (l *accessLogger) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		start := time.Now()
+
+		ctx = withRequestId(ctx, req.Header())
+
+		// Call the handler.
+		res, err := next(ctx, req)
+
+		// Sending request id in the response header.
+		var connectErr *connect.Error
+
+		if errors.As(err, &connectErr) {
+			connectErr.Meta().Set(requestid.Key, requestIdOf(ctx))
+		} else if err == nil {
+			res.Header().Set(requestid.Key, requestIdOf(ctx))
+		}
+
+		l.log(ctx, req.Spec().Procedure, req.Peer().Addr, start, err)
+
+		return res, err
+	}
+}
+
+// Client streams are not used by the server.
+func (l *accessLogger) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// Wrapping streaming handler calls.
+func (l *accessLogger) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		start := time.Now()
+
+		ctx = withRequestId(ctx, conn.RequestHeader())
+
+		// Sending request id in the response header.
+		conn.ResponseHeader().Set(requestid.Key, requestIdOf(ctx))
+
+		// Call the handler.
+		err := next(ctx, conn)
+
+		l.log(ctx, conn.Spec().Procedure, conn.Peer().Addr, start, err)
+
+		return err
+	}
+}
+
+// Writing an access log entry and observing the request.
+func (l *accessLogger) log(ctx context.Context, method, addr string, start time.Time, err error) {
+	code := codes.OK
+
+	// Connect and gRPC status codes have the same values.
+	if err != nil {
+		code = codes.Code(connect.CodeOf(err))
+	}
+
+	l.access.LogRequest(ctx, method, addr, start, code, err)
+}
+
+// Adding incoming or a new request id and request logger to the context.
+func withRequestId(ctx context.Context, header http.Header) context.Context {
+	id := requestid.FromIncoming(header.Get(requestid.Key))
+
+	logger := log.With().Str("request_id", id).Logger()
+
+	return logger.WithContext(requestid.NewContext(ctx, id))
+}
+
+// Getting request id of the context.
+func requestIdOf(ctx context.Context) string {
+	id, _ := requestid.FromContext(ctx)
+	return id
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package connect
+
+import (
+	"net/http"
+	"strings"
+)
+
+var (
+	// Request headers used by the Connect and gRPC-Web protocols.
+	allowedHeaders = strings.Join([]string{
+		"Content-Type",
+		"Connect-Protocol-Version",
+		"Connect-Timeout-Ms",
+		"Connect-Accept-Encoding",
+		"Connect-Content-Encoding",
+		"Grpc-Timeout",
+		"X-Grpc-Web",
+		"X-User-Agent",
+		"Authorization",
+	}, ", ")
+
+	// Response headers and trailers readable by browser clients.
+	exposedHeaders = strings.Join([]string{
+		"Content-Encoding",
+		"Connect-Content-Encoding",
+		"Grpc-Status",
+		"Grpc-Message",
+		"Grpc-Status-Details-Bin",
+	}, ", ")
+)
+
+// CORS middleware allowing browser clients from the given origins.
+func cors(origins []string, next http.Handler) http.Handler {
+	allowed := make(map[string]struct{}, len(origins))
+
+	for _, origin := range origins {
+		allowed[origin] = struct{}{}
+	}
+
+	// Check is any origin allowed.
+	_, wildcard := allowed["*"]
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+
+		// Check is origin allowed.
+		_, ok := allowed[origin]
+
+		if origin != "" && (ok || wildcard) {
+			w.Header().Add("Vary", "Origin")
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Expose-Headers", exposedHeaders)
+
+			// Handle preflight request.
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
+				w.Header().Set("Access-Control-Max-Age", "7200")
+				w.WriteHeader(http.StatusNoContent)
+
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
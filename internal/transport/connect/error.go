@@ -0,0 +1,115 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package connect
+
+import (
+	"context"
+	"errors"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/transport/grpc"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/status"
+)
+
+// Connect server error handler.
+func errorHandler(err error) error {
+	var e *domain.Error
+
+	// Check if error is a domain.Error, gRPC and Connect status codes have the
+	// same values.
+	if errors.As(err, &e) {
+		code := connect.Code(grpc.ErrorCode(e.Code))
+
+		// Internal error details are hidden.
+		if code == connect.CodeInternal {
+			return connect.NewError(connect.CodeInternal, errors.New("Internal Server Error"))
+		}
+
+		return newError(code, e)
+	}
+
+	// Check if error is a gRPC status returned by the shared handlers.
+	if st, ok := status.FromError(err); ok {
+		err := connect.NewError(connect.Code(st.Code()), errors.New(st.Message()))
+
+		// Added status details.
+		for _, detail := range st.Proto().GetDetails() {
+			if d, e := connect.NewErrorDetail(detail); e == nil {
+				err.AddDetail(d)
+			}
+		}
+
+		return err
+	}
+
+	return err
+}
+
+// Creating a new Connect error with the domain error details.
+func newError(code connect.Code, e *domain.Error) error {
+	err := connect.NewError(code, errors.New(e.Message))
+
+	// Added error details.
+	for _, detail := range grpc.ErrorDetails(e) {
+		d, e := connect.NewErrorDetail(detail)
+		if e != nil {
+			log.Error().Err(e).Msg("error adding error details")
+			continue
+		}
+
+		err.AddDetail(d)
+	}
+
+	return err
+}
+
+// Connect error interceptor structure.
+type errorInterceptor struct{}
+
+// Wrapping unary handler errors.
+func (errorInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		// Call the handler.
+		res, err := next(ctx, req)
+		if err != nil {
+			return res, errorHandler(err)
+		}
+
+		return res, nil
+	}
+}
+
+// Client streams are not used by the server.
+func (errorInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// Wrapping streaming handler errors.
+func (errorInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		// Call the handler.
+		if err := next(ctx, conn); err != nil {
+			return errorHandler(err)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package connect
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Testing mapping errors to Connect errors.
+func TestErrorHandler(t *testing.T) {
+	// Tests structures.
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    connect.Code
+		wantMessage string
+		wantReason  string
+	}{
+		{
+			name:        "Internal",
+			err:         &domain.Error{Code: domain.CodeInternal, Message: "database is down"},
+			wantCode:    connect.CodeInternal,
+			wantMessage: "Internal Server Error",
+		},
+		{
+			name:        "Not Found",
+			err:         &domain.Error{Code: domain.CodeNotFound, Message: "Post not found", Reason: domain.ReasonPostNotFound},
+			wantCode:    connect.CodeNotFound,
+			wantMessage: "Post not found",
+			wantReason:  domain.ReasonPostNotFound,
+		},
+		{
+			name:        "Already Exists",
+			err:         &domain.Error{Code: domain.CodeAlreadyExists, Message: "Post is a duplicate", Reason: domain.ReasonPostDuplicate},
+			wantCode:    connect.CodeAlreadyExists,
+			wantMessage: "Post is a duplicate",
+			wantReason:  domain.ReasonPostDuplicate,
+		},
+		{
+			name:        "Invalid Argument",
+			err:         &domain.Error{Code: domain.CodeInvalidArgument, Message: "Invalid id"},
+			wantCode:    connect.CodeInvalidArgument,
+			wantMessage: "Invalid id",
+		},
+		{
+			name: "Resource Exhausted",
+			err: &domain.Error{Code: domain.CodeResourceExhausted, Message: "Consumer is too slow",
+				Reason: domain.ReasonConsumerTooSlow, RetryDelay: time.Second},
+			wantCode:    connect.CodeResourceExhausted,
+			wantMessage: "Consumer is too slow",
+			wantReason:  domain.ReasonConsumerTooSlow,
+		},
+		{
+			name:        "Unauthenticated",
+			err:         &domain.Error{Code: domain.CodeUnauthenticated, Message: "Authentication required"},
+			wantCode:    connect.CodeUnauthenticated,
+			wantMessage: "Authentication required",
+		},
+		{
+			name:        "Out Of Range",
+			err:         &domain.Error{Code: domain.CodeOutOfRange, Message: "Cursor expired", Reason: domain.ReasonCursorExpired},
+			wantCode:    connect.CodeOutOfRange,
+			wantMessage: "Cursor expired",
+			wantReason:  domain.ReasonCursorExpired,
+		},
+		{
+			name:        "Unavailable",
+			err:         &domain.Error{Code: domain.CodeUnavailable, Message: "Shutting down"},
+			wantCode:    connect.CodeUnavailable,
+			wantMessage: "Shutting down",
+		},
+		{
+			name:        "Status",
+			err:         status.Error(codes.PermissionDenied, "Permission denied"),
+			wantCode:    connect.CodePermissionDenied,
+			wantMessage: "Permission denied",
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *connect.Error
+
+			// Check for Connect error code and message.
+			if !errors.As(errorHandler(tt.err), &got) || got.Code() != tt.wantCode || got.Message() != tt.wantMessage {
+				t.Fatalf("error mapping error: got %v", got)
+			}
+
+			var reason string
+
+			// Getting error reason of the error details.
+			for _, detail := range got.Details() {
+				value, err := detail.Value()
+				if err != nil {
+					t.Fatalf("error decoding error detail: %s", err.Error())
+				}
+
+				if info, ok := value.(*errdetails.ErrorInfo); ok {
+					reason = info.Reason
+				}
+			}
+
+			if reason != tt.wantReason {
+				t.Errorf("error reason: got %q, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package connect
+
+import (
+	"net/http"
+
+	"github.com/durudex/durudex-post-service/internal/auth"
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/service"
+	v1 "github.com/durudex/durudex-post-service/internal/transport/connect/v1"
+	"github.com/durudex/durudex-post-service/internal/transport/grpc"
+
+	"github.com/bufbuild/connect-go"
+)
+
+// Connect server handler structure.
+type Handler struct {
+	service       *service.Service
+	authenticator *auth.Authenticator
+	reporter      grpc.PanicReporter
+}
+
+// Creating a new Connect handler. Access tokens are not authenticated when the
+// authenticator is nil, and recovered panics are reported with the gRPC panic
+// reporter.
+func NewHandler(service *service.Service, authenticator *auth.Authenticator, reporter grpc.PanicReporter) *Handler {
+	return &Handler{service: service, authenticator: authenticator, reporter: reporter}
+}
+
+// Registering Connect version handlers.
+//
+// Handler panics are recovered outside of the interceptors, and requests are
+// logged after their errors are mapped.
+func (h *Handler) RegisterHandlers(mux *http.ServeMux, accessLog config.AccessLogConfig) {
+	opts := []connect.HandlerOption{
+		connect.WithRecover((&recovery{reporter: h.reporter}).recoverHandler),
+		connect.WithInterceptors(&accessLogger{access: grpc.NewAccessLogger("Connect", accessLog)}, errorInterceptor{}),
+	}
+
+	v1.NewHandler(h.service).RegisterHandlers(mux, opts...)
+}
+
+// Wrapping the handler with request access token authentication.
+func (h *Handler) authenticate(next http.Handler) http.Handler {
+	if h.authenticator == nil {
+		return next
+	}
+
+	return auth.Middleware(h.authenticator)(next)
+}
@@ -0,0 +1,38 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package connect
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/durudex/durudex-post-service/internal/transport/grpc"
+
+	"github.com/bufbuild/connect-go"
+)
+
+// Connect panic recovery structure.
+type recovery struct{ reporter grpc.PanicReporter }
+
+// Handling a recovered Connect handler panic.
+func (r *recovery) recoverHandler(ctx context.Context, spec connect.Spec, _ http.Header, p any) error {
+	grpc.RecoverPanic(ctx, r.reporter, spec.Procedure, p)
+
+	return connect.NewError(connect.CodeInternal, fmt.Errorf("Internal Server Error"))
+}
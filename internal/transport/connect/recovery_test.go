@@ -0,0 +1,92 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package connect
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/metrics"
+	"github.com/durudex/durudex-post-service/internal/service"
+	v1 "github.com/durudex/durudex-post-service/pkg/pb/durudex/v1"
+	"github.com/durudex/durudex-post-service/pkg/pb/durudex/v1/durudexv1connect"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/segmentio/ksuid"
+)
+
+// Post service panicking on getting a post.
+type panickingPost struct{ service.Post }
+
+// Getting a post.
+func (panickingPost) Get(context.Context, ksuid.KSUID, domain.FieldMask) (domain.Post, error) {
+	panic("connect panic")
+}
+
+// Panic reporter recorder structure.
+type panicRecorder struct {
+	mu      sync.Mutex
+	methods []string
+}
+
+// Recording a recovered handler panic.
+func (r *panicRecorder) ReportPanic(_ context.Context, method string, recovered interface{}, _ []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.methods = append(r.methods, method)
+}
+
+// Testing recovering and reporting Connect handler panics.
+func TestRecovery(t *testing.T) {
+	const method = "/durudex.v1.PostService/GetPost"
+
+	reporter := &panicRecorder{}
+
+	mux := http.NewServeMux()
+	NewHandler(&service.Service{Post: panickingPost{}}, nil, reporter).RegisterHandlers(mux, config.AccessLogConfig{})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	panics := metrics.PanicsTotal.WithLabelValues(method)
+	before := testutil.ToFloat64(panics)
+
+	// Getting a post.
+	client := durudexv1connect.NewPostServiceClient(srv.Client(), srv.URL)
+
+	_, err := client.GetPost(context.Background(), connect.NewRequest(&v1.GetPostRequest{Id: ksuid.New().Bytes()}))
+	if connect.CodeOf(err) != connect.CodeInternal {
+		t.Errorf("error status code: got %v, want Internal", err)
+	}
+
+	// Check for counted and reported panic.
+	if got := testutil.ToFloat64(panics) - before; got != 1 {
+		t.Errorf("error counted panics: got %v, want 1", got)
+	}
+
+	if len(reporter.methods) != 1 || reporter.methods[0] != method {
+		t.Errorf("error reported panics: %v", reporter.methods)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package connect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// Connect server structure.
+//
+// Serves the Connect, gRPC-Web and gRPC protocols on a single HTTP/1.1 and
+// cleartext HTTP/2 listener, so browser clients need no proxy.
+type Server struct {
+	server  *http.Server
+	config  config.ConnectConfig
+	handler *Handler
+}
+
+// Creating a new Connect server.
+func NewServer(cfg config.ConnectConfig, handler *Handler) *Server {
+	mux := http.NewServeMux()
+
+	// Registering Connect handlers.
+	handler.RegisterHandlers(mux, cfg.AccessLog)
+
+	return &Server{
+		server: &http.Server{
+			Addr:              cfg.Host + ":" + cfg.Port,
+			Handler:           h2c.NewHandler(cors(cfg.AllowedOrigins, handler.authenticate(mux)), &http2.Server{}),
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+		config:  cfg,
+		handler: handler,
+	}
+}
+
+// Running Connect server.
+func (s *Server) Run() {
+	log.Info().Msg("Running Connect server...")
+
+	// Running Connect server.
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal().Err(err).Msg("error running Connect server")
+	}
+}
+
+// Stopping Connect server.
+//
+// In-flight requests are drained until the context is done, after which open
+// connections are closed.
+func (s *Server) Stop(ctx context.Context) error {
+	log.Info().Msg("Stopping Connect server...")
+
+	if err := s.server.Shutdown(ctx); err != nil {
+		s.server.Close()
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/durudex/durudex-post-service/pkg/pb/durudex/v2/durudexv2connect"
+
+	"github.com/bufbuild/connect-go"
+)
+
+// Successor version link header value.
+const successorLink = "</" + durudexv2connect.PostServiceName + ">; rel=\"successor-version\""
+
+// Connect deprecation interceptor structure.
+//
+// Sends deprecation and successor version headers on responses and errors of
+// the deprecated service.
+type deprecation struct{}
+
+// Wrapping unary handler responses.
+func (deprecation) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		// Call the handler.
+		res, err := next(ctx, req)
+		if err != nil {
+			var connectErr *connect.Error
+
+			if errors.As(err, &connectErr) {
+				setDeprecation(connectErr.Meta())
+			}
+
+			return res, err
+		}
+
+		setDeprecation(res.Header())
+
+		return res, nil
+	}
+}
+
+// Client streams are not used by the server.
+func (deprecation) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// Wrapping streaming handler responses.
+func (deprecation) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		setDeprecation(conn.ResponseHeader())
+
+		return next(ctx, conn)
+	}
+}
+
+// Setting deprecation headers.
+func setDeprecation(header http.Header) {
+	header.Set("Deprecation", "true")
+	header.Set("Link", successorLink)
+}
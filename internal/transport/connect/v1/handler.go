@@ -0,0 +1,46 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package v1
+
+import (
+	"net/http"
+
+	"github.com/durudex/durudex-post-service/internal/service"
+	grpcv1 "github.com/durudex/durudex-post-service/internal/transport/grpc/v1"
+	"github.com/durudex/durudex-post-service/pkg/pb/durudex/v1/durudexv1connect"
+
+	"github.com/bufbuild/connect-go"
+)
+
+// Connect handler structure.
+type Handler struct{ service *service.Service }
+
+// Creating a new Connect handler.
+func NewHandler(service *service.Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Registering Connect handlers.
+func (h *Handler) RegisterHandlers(mux *http.ServeMux, opts ...connect.HandlerOption) {
+	// Post service is deprecated in favor of the v2 gRPC post service.
+	postOpts := append([]connect.HandlerOption{connect.WithInterceptors(deprecation{})}, opts...)
+
+	mux.Handle(durudexv1connect.NewPostServiceHandler(
+		NewPostHandler(grpcv1.NewPostHandler(h.service.Post, h.service.Event)), postOpts...,
+	))
+}
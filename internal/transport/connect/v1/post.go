@@ -0,0 +1,128 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package v1
+
+import (
+	"context"
+
+	grpcv1 "github.com/durudex/durudex-post-service/internal/transport/grpc/v1"
+	v1 "github.com/durudex/durudex-post-service/pkg/pb/durudex/v1"
+	"github.com/durudex/durudex-post-service/pkg/pb/durudex/v1/durudexv1connect"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/grpc"
+)
+
+// Post Connect handler.
+//
+// Requests are served by the gRPC post handler, so the Connect, gRPC-Web and
+// gRPC protocols share the same behavior. Finding similar posts is a moderator
+// RPC served only by the gRPC server, so it is unimplemented on the browser
+// listener.
+type PostHandler struct {
+	handler *grpcv1.PostHandler
+	durudexv1connect.UnimplementedPostServiceHandler
+}
+
+// Creating a new post Connect handler.
+func NewPostHandler(handler *grpcv1.PostHandler) *PostHandler {
+	return &PostHandler{handler: handler}
+}
+
+// Creating a new post handler.
+func (h *PostHandler) CreatePost(ctx context.Context, req *connect.Request[v1.CreatePostRequest]) (*connect.Response[v1.CreatePostResponse], error) {
+	res, err := h.handler.CreatePost(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(res), nil
+}
+
+// Getting a post handler.
+func (h *PostHandler) GetPost(ctx context.Context, req *connect.Request[v1.GetPostRequest]) (*connect.Response[v1.GetPostResponse], error) {
+	res, err := h.handler.GetPost(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(res), nil
+}
+
+// Getting posts handler.
+func (h *PostHandler) GetPosts(ctx context.Context, req *connect.Request[v1.GetPostsRequest]) (*connect.Response[v1.GetPostsResponse], error) {
+	res, err := h.handler.GetPosts(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(res), nil
+}
+
+// Deleting a post handler.
+func (h *PostHandler) DeletePost(ctx context.Context, req *connect.Request[v1.DeletePostRequest]) (*connect.Response[v1.DeletePostResponse], error) {
+	res, err := h.handler.DeletePost(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(res), nil
+}
+
+// Updating a post handler.
+func (h *PostHandler) UpdatePost(ctx context.Context, req *connect.Request[v1.UpdatePostRequest]) (*connect.Response[v1.UpdatePostResponse], error) {
+	res, err := h.handler.UpdatePost(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(res), nil
+}
+
+// Getting total posts count.
+func (h *PostHandler) GetTotalPostsCount(ctx context.Context, req *connect.Request[v1.GetTotalPostsCountRequest]) (*connect.Response[v1.GetTotalPostsCountResponse], error) {
+	res, err := h.handler.GetTotalPostsCount(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(res), nil
+}
+
+// Watching author post events handler.
+func (h *PostHandler) WatchPosts(ctx context.Context, req *connect.Request[v1.WatchPostsRequest], stream *connect.ServerStream[v1.WatchPostsResponse]) error {
+	return h.handler.WatchPosts(req.Msg, &watchPostsStream{ctx: ctx, stream: stream})
+}
+
+// Watch posts stream structure.
+//
+// Adapts a Connect server stream to the gRPC stream used by the post handler,
+// only Send and Context are used by the handler.
+type watchPostsStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	stream *connect.ServerStream[v1.WatchPostsResponse]
+}
+
+// Sending a post event to the stream.
+func (s *watchPostsStream) Send(res *v1.WatchPostsResponse) error {
+	return s.stream.Send(res)
+}
+
+// Getting stream context.
+func (s *watchPostsStream) Context() context.Context { return s.ctx }
@@ -0,0 +1,140 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package v1_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/service"
+	connectv1 "github.com/durudex/durudex-post-service/internal/transport/connect/v1"
+	v1 "github.com/durudex/durudex-post-service/pkg/pb/durudex/v1"
+	"github.com/durudex/durudex-post-service/pkg/pb/durudex/v1/durudexv1connect"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/segmentio/ksuid"
+)
+
+// Post service getting a post.
+type getPost struct{ service.Post }
+
+// Getting a post.
+func (getPost) Get(context.Context, ksuid.KSUID, domain.FieldMask) (domain.Post, error) {
+	return domain.Post{Text: "text"}, nil
+}
+
+// Event service sending post events.
+type sendEvent struct {
+	service.Event
+	events []domain.PostEvent
+}
+
+// Watching author post events.
+func (e *sendEvent) Watch(_ context.Context, _ []ksuid.KSUID, _ *int64, send func(domain.PostEvent) error) error {
+	for _, event := range e.events {
+		if err := send(event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Creating a new post service Connect client.
+func newClient(t *testing.T, svc *service.Service) durudexv1connect.PostServiceClient {
+	mux := http.NewServeMux()
+	connectv1.NewHandler(svc).RegisterHandlers(mux)
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return durudexv1connect.NewPostServiceClient(srv.Client(), srv.URL)
+}
+
+// Check for deprecation headers of the deprecated post service.
+func checkDeprecation(t *testing.T, header http.Header) {
+	if header.Get("Deprecation") != "true" || header.Get("Link") != "</durudex.v2.PostService>; rel=\"successor-version\"" {
+		t.Errorf("error deprecation headers: %v", header)
+	}
+}
+
+// Testing getting a post over Connect.
+func TestPostHandler_GetPost(t *testing.T) {
+	client := newClient(t, &service.Service{Post: getPost{}})
+
+	res, err := client.GetPost(context.Background(), connect.NewRequest(&v1.GetPostRequest{Id: ksuid.New().Bytes()}))
+	if err != nil {
+		t.Fatalf("error getting post: %s", err.Error())
+	}
+
+	// Check for post and deprecation headers.
+	if res.Msg.Text != "text" {
+		t.Errorf("error post text: %s", res.Msg.Text)
+	}
+
+	checkDeprecation(t, res.Header())
+}
+
+// Testing finding similar posts is not served over Connect.
+func TestPostHandler_FindSimilarPosts(t *testing.T) {
+	client := newClient(t, &service.Service{})
+
+	_, err := client.FindSimilarPosts(context.Background(),
+		connect.NewRequest(&v1.FindSimilarPostsRequest{Id: ksuid.New().Bytes()}))
+	if connect.CodeOf(err) != connect.CodeUnimplemented {
+		t.Errorf("error status code: got %v, want Unimplemented", err)
+	}
+}
+
+// Testing watching author post events over a Connect server stream.
+func TestPostHandler_WatchPosts(t *testing.T) {
+	authorId := ksuid.New()
+
+	client := newClient(t, &service.Service{Event: &sendEvent{events: []domain.PostEvent{
+		{Id: 1, Type: domain.EventPostCreated, Post: domain.Post{Id: ksuid.New(), AuthorId: authorId, Text: "text"}},
+		{Id: 2, Type: domain.EventPostDeleted, Post: domain.Post{Id: ksuid.New(), AuthorId: authorId}},
+	}}})
+
+	stream, err := client.WatchPosts(context.Background(),
+		connect.NewRequest(&v1.WatchPostsRequest{AuthorIds: [][]byte{authorId.Bytes()}}))
+	if err != nil {
+		t.Fatalf("error watching posts: %s", err.Error())
+	}
+	defer stream.Close()
+
+	var cursors []int64
+
+	for stream.Receive() {
+		cursors = append(cursors, stream.Msg().Cursor)
+	}
+
+	if err := stream.Err(); err != nil {
+		t.Fatalf("error receiving post events: %s", err.Error())
+	}
+
+	// Check for received event cursors and deprecation headers.
+	if !reflect.DeepEqual(cursors, []int64{1, 2}) {
+		t.Errorf("error event cursors: %v", cursors)
+	}
+
+	checkDeprecation(t, stream.ResponseHeader())
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package graphql
+
+import (
+	"errors"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/segmentio/ksuid"
+)
+
+// GraphQL resolver error structure.
+type resolverError struct {
+	message string
+	code    string
+	// Domain error with reason and field violations.
+	err *domain.Error
+}
+
+// Getting error message.
+func (e *resolverError) Error() string { return e.message }
+
+// Getting error extensions.
+func (e *resolverError) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": e.code}
+
+	if e.err == nil {
+		return extensions
+	}
+
+	// Added error reason.
+	if e.err.Reason != "" {
+		extensions["reason"] = e.err.Reason
+	}
+
+	if len(e.err.Metadata) != 0 {
+		extensions["metadata"] = e.err.Metadata
+	}
+
+	// Added field violations.
+	if len(e.err.Violations) != 0 {
+		violations := make([]map[string]string, len(e.err.Violations))
+
+		for i, v := range e.err.Violations {
+			violations[i] = map[string]string{"field": v.Field, "description": v.Description}
+		}
+
+		extensions["violations"] = violations
+	}
+
+	return extensions
+}
+
+// GraphQL resolver error handler.
+func errorHandler(err error) error {
+	var e *domain.Error
+
+	// Check if error is a domain.Error.
+	if errors.As(err, &e) {
+		switch e.Code {
+		case domain.CodeNotFound:
+			return &resolverError{message: e.Message, code: "NOT_FOUND", err: e}
+		case domain.CodeAlreadyExists:
+			return &resolverError{message: e.Message, code: "ALREADY_EXISTS", err: e}
+		case domain.CodeInvalidArgument:
+			return &resolverError{message: e.Message, code: "BAD_USER_INPUT", err: e}
+		case domain.CodeResourceExhausted:
+			return &resolverError{message: e.Message, code: "RESOURCE_EXHAUSTED", err: e}
+		case domain.CodeUnauthenticated:
+			return &resolverError{message: e.Message, code: "UNAUTHENTICATED", err: e}
+		case domain.CodeOutOfRange:
+			return &resolverError{message: e.Message, code: "OUT_OF_RANGE", err: e}
+		case domain.CodeUnavailable:
+			return &resolverError{message: e.Message, code: "UNAVAILABLE", err: e}
+		}
+	}
+
+	return &resolverError{message: "Internal Server Error", code: "INTERNAL_SERVER_ERROR"}
+}
+
+// Parsing a KSUID argument.
+func parseId(id string) (ksuid.KSUID, error) {
+	v, err := ksuid.Parse(id)
+	if err != nil {
+		return ksuid.Nil, &resolverError{message: "Invalid id", code: "BAD_USER_INPUT"}
+	}
+
+	return v, nil
+}
+
+// Parsing an optional id, a missing id is parsed as nil ksuid.
+func parseOptionalId(id *graphql.ID) (ksuid.KSUID, error) {
+	if id == nil {
+		return ksuid.Nil, nil
+	}
+
+	return parseId(string(*id))
+}
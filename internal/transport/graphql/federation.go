@@ -0,0 +1,123 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/durudex/durudex-post-service/pkg/dataloader"
+
+	"github.com/segmentio/ksuid"
+)
+
+// Federation entity representation scalar.
+type representation map[string]interface{}
+
+// Implementing GraphQL _Any scalar type.
+func (representation) ImplementsGraphQLType(name string) bool { return name == "_Any" }
+
+// Unmarshaling entity representation.
+func (r *representation) UnmarshalGraphQL(input interface{}) error {
+	v, ok := input.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("invalid entity representation type: %T", input)
+	}
+
+	*r = v
+
+	return nil
+}
+
+// Getting entity representation type name and id.
+func (r representation) key() (string, ksuid.KSUID, error) {
+	typename, _ := r["__typename"].(string)
+	id, _ := r["id"].(string)
+
+	v, err := parseId(id)
+
+	return typename, v, err
+}
+
+// Federation entity resolver structure.
+type entityResolver struct {
+	post *postResolver
+	user *userResolver
+}
+
+// Resolving entity as post.
+func (e *entityResolver) ToPost() (*postResolver, bool) { return e.post, e.post != nil }
+
+// Resolving entity as user.
+func (e *entityResolver) ToUser() (*userResolver, bool) { return e.user, e.user != nil }
+
+// Resolving federation entities by representations.
+//
+// Posts are loaded in a single batch, missing posts are resolved as null.
+func (r *Resolver) Entities(ctx context.Context, args struct{ Representations []representation }) ([]*entityResolver, error) {
+	entities := make([]*entityResolver, len(args.Representations))
+
+	var (
+		ids   []ksuid.KSUID
+		index []int
+	)
+
+	for i, rep := range args.Representations {
+		typename, id, err := rep.key()
+		if err != nil {
+			return nil, err
+		}
+
+		switch typename {
+		case "Post":
+			ids = append(ids, id)
+			index = append(index, i)
+		case "User":
+			entities[i] = &entityResolver{user: &userResolver{id: id, root: r}}
+		default:
+			return nil, &resolverError{message: "Unknown entity type: " + typename, code: "BAD_USER_INPUT"}
+		}
+	}
+
+	// Loading posts.
+	posts, errs := loadersFor(ctx).post.LoadMany(ctx, ids)
+
+	for i, post := range posts {
+		if errs[i] != nil {
+			if errors.Is(errs[i], dataloader.ErrNotFound) {
+				continue
+			}
+
+			return nil, errorHandler(errs[i])
+		}
+
+		entities[index[i]] = &entityResolver{post: &postResolver{post: post, root: r}}
+	}
+
+	return entities, nil
+}
+
+// Federation service resolver structure.
+type serviceResolver struct{}
+
+// Getting the published subgraph schema.
+func (serviceResolver) Sdl() *string { return &schema }
+
+// Getting federation service.
+func (r *Resolver) Service() *serviceResolver { return &serviceResolver{} }
@@ -0,0 +1,74 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/service"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+var (
+	// Published GraphQL schema, returned to the federation gateway.
+	//
+	//go:embed schema.graphql
+	schema string
+
+	// Apollo Federation subgraph definitions.
+	//
+	//go:embed federation.graphql
+	federation string
+)
+
+// GraphQL handler structure.
+type Handler struct {
+	service *service.Service
+	config  config.GraphQLConfig
+	handler *relay.Handler
+}
+
+// Creating a new GraphQL handler.
+func NewHandler(service *service.Service, cfg config.GraphQLConfig) *Handler {
+	opts := []graphql.SchemaOpt{graphql.UseStringDescriptions()}
+
+	// Added max query depth option.
+	if cfg.MaxDepth > 0 {
+		opts = append(opts, graphql.MaxDepth(cfg.MaxDepth))
+	}
+
+	return &Handler{
+		service: service,
+		config:  cfg,
+		handler: &relay.Handler{
+			Schema: graphql.MustParseSchema(schema+federation, NewResolver(service.Post), opts...),
+		},
+	}
+}
+
+// Serving GraphQL request with request scoped data loaders.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := context.WithValue(r.Context(), loadersKey{}, newLoaders(h.service.Post, h.config))
+
+	h.handler.ServeHTTP(w, r.WithContext(ctx))
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/service"
+
+	"github.com/segmentio/ksuid"
+)
+
+// Post service with stored posts.
+type storedPost struct {
+	service.Post
+	posts map[ksuid.KSUID]domain.Post
+	sort  *domain.SortOptions
+}
+
+// Getting posts by ids, missing posts are skipped.
+func (p *storedPost) GetByIds(_ context.Context, ids []ksuid.KSUID) ([]domain.Post, error) {
+	var posts []domain.Post
+
+	for _, id := range ids {
+		if post, ok := p.posts[id]; ok {
+			posts = append(posts, post)
+		}
+	}
+
+	return posts, nil
+}
+
+// Getting author posts.
+func (p *storedPost) GetPosts(_ context.Context, _ ksuid.KSUID, sort domain.SortOptions, _ domain.PostFilter, _ domain.FieldMask) ([]domain.Post, error) {
+	*p.sort = sort
+	return nil, nil
+}
+
+// GraphQL response structure.
+type response struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// Sending a GraphQL query.
+func query(t *testing.T, post service.Post, query string, variables map[string]interface{}) response {
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		t.Fatalf("error encoding query: %s", err.Error())
+	}
+
+	h := NewHandler(&service.Service{Post: post}, config.GraphQLConfig{BatchWait: time.Millisecond, MaxBatch: 100})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+
+	h.ServeHTTP(w, r)
+
+	var res response
+
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatalf("error decoding response: %s", err.Error())
+	}
+
+	return res
+}
+
+// Testing getting user posts with page size bounds.
+func TestUserResolver_Posts(t *testing.T) {
+	first, last := int32(101), int32(1)
+
+	// Tests structures.
+	tests := []struct {
+		name     string
+		args     string
+		want     domain.SortOptions
+		wantCode string
+	}{
+		{name: "Max First", args: "first: 100", want: domain.SortOptions{First: &first}},
+		{name: "Zero Last", args: "last: 0", want: domain.SortOptions{Last: &last}},
+		{name: "Too Large First", args: "first: 101", wantCode: "BAD_USER_INPUT"},
+		{name: "Negative Last", args: "last: -1", wantCode: "BAD_USER_INPUT"},
+		{name: "First And Last", args: "first: 10, last: 10", wantCode: "BAD_USER_INPUT"},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got domain.SortOptions
+
+			res := query(t, &storedPost{sort: &got}, `query($r: [_Any!]!) {
+				_entities(representations: $r) { ... on User { posts(`+tt.args+`) { nodes { id } } } }
+			}`, map[string]interface{}{"r": []map[string]string{{"__typename": "User", "id": ksuid.New().String()}}})
+
+			if tt.wantCode != "" {
+				// Check for error code.
+				if len(res.Errors) != 1 || res.Errors[0].Extensions["code"] != tt.wantCode {
+					t.Errorf("error response errors: got %+v, want %s", res.Errors, tt.wantCode)
+				}
+
+				return
+			}
+
+			if len(res.Errors) != 0 {
+				t.Fatalf("error response errors: %+v", res.Errors)
+			}
+
+			// Check for similarity of sort options.
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("error sort options: got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Testing loading missing posts as null.
+func TestResolver_MissingPost(t *testing.T) {
+	stored, missing := ksuid.New(), ksuid.New()
+	post := &storedPost{posts: map[ksuid.KSUID]domain.Post{stored: {Id: stored, Text: "text"}}}
+
+	// Tests structures.
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		field     string
+		want      string
+	}{
+		{
+			name:      "Post",
+			query:     `query($id: ID!) { post(id: $id) { id } }`,
+			variables: map[string]interface{}{"id": missing.String()},
+			field:     "post",
+			want:      `null`,
+		},
+		{
+			name:  "Entities",
+			query: `query($r: [_Any!]!) { _entities(representations: $r) { ... on Post { id } } }`,
+			variables: map[string]interface{}{"r": []map[string]string{
+				{"__typename": "Post", "id": missing.String()},
+				{"__typename": "Post", "id": stored.String()},
+			}},
+			field: "_entities",
+			want:  `[null,{"id":"` + stored.String() + `"}]`,
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := query(t, post, tt.query, tt.variables)
+
+			if len(res.Errors) != 0 {
+				t.Fatalf("error response errors: %+v", res.Errors)
+			}
+
+			// Check for similarity of resolved field.
+			if got := string(res.Data[tt.field]); got != tt.want {
+				t.Errorf("error resolved %s: got %s, want %s", tt.field, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package graphql
+
+import (
+	"context"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/service"
+	"github.com/durudex/durudex-post-service/pkg/dataloader"
+
+	"github.com/segmentio/ksuid"
+)
+
+// Data loaders context key.
+type loadersKey struct{}
+
+// Request data loaders structure.
+type loaders struct {
+	post *dataloader.Loader[ksuid.KSUID, domain.Post]
+}
+
+// Creating a new request data loaders.
+func newLoaders(post service.Post, cfg config.GraphQLConfig) *loaders {
+	return &loaders{
+		post: dataloader.NewLoader(func(ctx context.Context, ids []ksuid.KSUID) (map[ksuid.KSUID]domain.Post, error) {
+			// Getting posts by ids.
+			posts, err := post.GetByIds(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			res := make(map[ksuid.KSUID]domain.Post, len(posts))
+
+			for _, p := range posts {
+				res[p.Id] = p
+			}
+
+			return res, nil
+		}, cfg.BatchWait, cfg.MaxBatch),
+	}
+}
+
+// Getting request data loaders.
+func loadersFor(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
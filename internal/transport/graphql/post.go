@@ -0,0 +1,277 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package graphql
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/segmentio/ksuid"
+)
+
+// Maximum number of posts of a page.
+const maxPageSize = 100
+
+// Post resolver structure.
+type postResolver struct {
+	post domain.Post
+	root *Resolver
+}
+
+// Getting post id.
+func (p *postResolver) ID() graphql.ID { return graphql.ID(p.post.Id.String()) }
+
+// Getting post author.
+func (p *postResolver) Author() *userResolver {
+	return &userResolver{id: p.post.AuthorId, root: p.root}
+}
+
+// Getting post text.
+func (p *postResolver) Text() string { return p.post.Text }
+
+// Getting is post flagged.
+func (p *postResolver) Flagged() bool { return p.post.Flagged }
+
+// Getting post creation time.
+func (p *postResolver) CreatedAt() graphql.Time { return graphql.Time{Time: p.post.CreatedAt} }
+
+// Getting post update time.
+func (p *postResolver) UpdatedAt() *graphql.Time {
+	if p.post.UpdatedAt == nil {
+		return nil
+	}
+
+	return &graphql.Time{Time: *p.post.UpdatedAt}
+}
+
+// User resolver structure.
+type userResolver struct {
+	id   ksuid.KSUID
+	root *Resolver
+}
+
+// Getting user id.
+func (u *userResolver) ID() graphql.ID { return graphql.ID(u.id.String()) }
+
+// Post connection arguments structure.
+type connectionArgs struct {
+	First  *int32
+	Last   *int32
+	Before *string
+	After  *string
+	filterArgs
+}
+
+// Posts creation time filter arguments structure.
+type filterArgs struct {
+	CreatedAfter  *graphql.Time
+	CreatedBefore *graphql.Time
+}
+
+// Getting author posts filter of the arguments.
+func (a filterArgs) filter() domain.PostFilter {
+	var filter domain.PostFilter
+
+	if a.CreatedAfter != nil {
+		filter.CreatedAfter = a.CreatedAfter.Time
+	}
+
+	if a.CreatedBefore != nil {
+		filter.CreatedBefore = a.CreatedBefore.Time
+	}
+
+	return filter
+}
+
+// Getting user posts connection.
+//
+// One extra post is requested to know whether there is another page in the
+// direction of pagination.
+func (u *userResolver) Posts(ctx context.Context, args connectionArgs) (*postConnectionResolver, error) {
+	var (
+		sort domain.SortOptions
+		err  error
+	)
+
+	// Parsing before cursor.
+	if args.Before != nil {
+		if sort.Before, err = parseId(*args.Before); err != nil {
+			return nil, err
+		}
+	}
+	// Parsing after cursor.
+	if args.After != nil {
+		if sort.After, err = parseId(*args.After); err != nil {
+			return nil, err
+		}
+	}
+
+	// Check first and last are mutually exclusive.
+	if args.First != nil && args.Last != nil {
+		return nil, &resolverError{message: "Must be only one of `first` or `last`", code: "BAD_USER_INPUT"}
+	}
+
+	// Check first and last range.
+	if !validPageSize(args.First) || !validPageSize(args.Last) {
+		return nil, &resolverError{
+			message: "Must be `first` or `last` from 0 to " + strconv.Itoa(maxPageSize),
+			code:    "BAD_USER_INPUT",
+		}
+	}
+
+	// Added first or last sort option.
+	var n int32
+
+	if args.First != nil {
+		n = *args.First
+		sort.First = overfetch(n)
+	} else if args.Last != nil {
+		n = *args.Last
+		sort.Last = overfetch(n)
+	}
+
+	// Getting author posts.
+	posts, err := u.root.post.GetPosts(ctx, u.id, sort, args.filter(), nil)
+	if err != nil {
+		return nil, errorHandler(err)
+	}
+
+	conn := &postConnectionResolver{root: u.root}
+
+	// Check is there another page.
+	more := len(posts) > int(n)
+	if more {
+		posts = posts[:n]
+	}
+
+	if args.First != nil {
+		conn.hasNext, conn.hasPrevious = more, args.After != nil
+	} else {
+		conn.hasNext, conn.hasPrevious = args.Before != nil, more
+
+		// Last posts are returned newest first.
+		for i, j := 0, len(posts)-1; i < j; i, j = i+1, j-1 {
+			posts[i], posts[j] = posts[j], posts[i]
+		}
+	}
+
+	conn.posts = make([]domain.Post, len(posts))
+
+	for i, post := range posts {
+		post.AuthorId = u.id
+		conn.posts[i] = post
+	}
+
+	return conn, nil
+}
+
+// Getting total user posts count.
+func (u *userResolver) TotalPostsCount(ctx context.Context, args filterArgs) (int32, error) {
+	count, err := u.root.post.GetTotalCount(ctx, u.id, args.filter())
+	if err != nil {
+		return 0, errorHandler(err)
+	}
+
+	return count, nil
+}
+
+// Check is an optional page size in the range from 0 to the maximum page size.
+func validPageSize(n *int32) bool {
+	return n == nil || (*n >= 0 && *n <= maxPageSize)
+}
+
+// Getting page size with one extra item.
+func overfetch(n int32) *int32 {
+	n++
+
+	return &n
+}
+
+// Post connection resolver structure.
+type postConnectionResolver struct {
+	posts       []domain.Post
+	hasNext     bool
+	hasPrevious bool
+	root        *Resolver
+}
+
+// Getting post edges.
+func (c *postConnectionResolver) Edges() []*postEdgeResolver {
+	edges := make([]*postEdgeResolver, len(c.posts))
+
+	for i, post := range c.posts {
+		edges[i] = &postEdgeResolver{post: &postResolver{post: post, root: c.root}}
+	}
+
+	return edges
+}
+
+// Getting posts of the edges.
+func (c *postConnectionResolver) Nodes() []*postResolver {
+	nodes := make([]*postResolver, len(c.posts))
+
+	for i, post := range c.posts {
+		nodes[i] = &postResolver{post: post, root: c.root}
+	}
+
+	return nodes
+}
+
+// Getting page information.
+func (c *postConnectionResolver) PageInfo() *pageInfoResolver {
+	info := &pageInfoResolver{hasNext: c.hasNext, hasPrevious: c.hasPrevious}
+
+	if len(c.posts) != 0 {
+		start, end := c.posts[0].Id.String(), c.posts[len(c.posts)-1].Id.String()
+		info.start, info.end = &start, &end
+	}
+
+	return info
+}
+
+// Post edge resolver structure.
+type postEdgeResolver struct{ post *postResolver }
+
+// Getting post cursor.
+func (e *postEdgeResolver) Cursor() string { return e.post.post.Id.String() }
+
+// Getting post node.
+func (e *postEdgeResolver) Node() *postResolver { return e.post }
+
+// Page information resolver structure.
+type pageInfoResolver struct {
+	hasNext     bool
+	hasPrevious bool
+	start       *string
+	end         *string
+}
+
+// Are there more items after the end cursor.
+func (p *pageInfoResolver) HasNextPage() bool { return p.hasNext }
+
+// Are there more items before the start cursor.
+func (p *pageInfoResolver) HasPreviousPage() bool { return p.hasPrevious }
+
+// Getting cursor of the first edge.
+func (p *pageInfoResolver) StartCursor() *string { return p.start }
+
+// Getting cursor of the last edge.
+func (p *pageInfoResolver) EndCursor() *string { return p.end }
@@ -0,0 +1,151 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/service"
+	"github.com/durudex/durudex-post-service/pkg/dataloader"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/segmentio/ksuid"
+)
+
+// GraphQL root resolver structure.
+type Resolver struct{ post service.Post }
+
+// Creating a new GraphQL root resolver.
+func NewResolver(post service.Post) *Resolver {
+	return &Resolver{post: post}
+}
+
+// Getting a post by id.
+func (r *Resolver) Post(ctx context.Context, args struct{ ID graphql.ID }) (*postResolver, error) {
+	id, err := parseId(string(args.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	return r.loadPost(ctx, id)
+}
+
+// Create post input structure.
+type createPostInput struct {
+	AuthorId *graphql.ID
+	Text     string
+}
+
+// Creating a new post.
+func (r *Resolver) CreatePost(ctx context.Context, args struct{ Input createPostInput }) (*postPayloadResolver, error) {
+	authorId, err := parseOptionalId(args.Input.AuthorId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new post.
+	id, err := r.post.Create(ctx, domain.Post{AuthorId: authorId, Text: args.Input.Text})
+	if err != nil {
+		return nil, errorHandler(err)
+	}
+
+	return &postPayloadResolver{id: id, root: r}, nil
+}
+
+// Update post input structure.
+type updatePostInput struct {
+	ID       graphql.ID
+	AuthorId *graphql.ID
+	Text     string
+}
+
+// Updating a post.
+func (r *Resolver) UpdatePost(ctx context.Context, args struct{ Input updatePostInput }) (*postPayloadResolver, error) {
+	id, err := parseId(string(args.Input.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	authorId, err := parseOptionalId(args.Input.AuthorId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Updating post.
+	if err := r.post.Update(ctx, domain.Post{Id: id, AuthorId: authorId, Text: args.Input.Text}, nil); err != nil {
+		return nil, errorHandler(err)
+	}
+
+	return &postPayloadResolver{id: id, root: r}, nil
+}
+
+// Delete post input structure.
+type deletePostInput struct {
+	ID       graphql.ID
+	AuthorId *graphql.ID
+}
+
+// Deleting a post.
+func (r *Resolver) DeletePost(ctx context.Context, args struct{ Input deletePostInput }) (*postPayloadResolver, error) {
+	id, err := parseId(string(args.Input.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	authorId, err := parseOptionalId(args.Input.AuthorId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deleting post.
+	if err := r.post.Delete(ctx, id, authorId); err != nil {
+		return nil, errorHandler(err)
+	}
+
+	return &postPayloadResolver{id: id, root: r}, nil
+}
+
+// Loading a post by id, a missing post is resolved as null.
+func (r *Resolver) loadPost(ctx context.Context, id ksuid.KSUID) (*postResolver, error) {
+	post, err := loadersFor(ctx).post.Load(ctx, id)
+	if err != nil {
+		if errors.Is(err, dataloader.ErrNotFound) {
+			return nil, nil
+		}
+
+		return nil, errorHandler(err)
+	}
+
+	return &postResolver{post: post, root: r}, nil
+}
+
+// Post mutation payload resolver structure.
+type postPayloadResolver struct {
+	id   ksuid.KSUID
+	root *Resolver
+}
+
+// Getting post id.
+func (p *postPayloadResolver) ID() graphql.ID { return graphql.ID(p.id.String()) }
+
+// Getting the post, loaded only when selected.
+func (p *postPayloadResolver) Post(ctx context.Context) (*postResolver, error) {
+	return p.root.loadPost(ctx, p.id)
+}
@@ -0,0 +1,173 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/metrics"
+	"github.com/durudex/durudex-post-service/pkg/requestid"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Request access logger structure, shared by the gRPC, HTTP and Connect
+// listeners.
+type AccessLogger struct {
+	protocol string
+	// Logger of successful requests, sampled by config.
+	success zerolog.Logger
+}
+
+// Creating a new request access logger of the protocol.
+func NewAccessLogger(protocol string, cfg config.AccessLogConfig) *AccessLogger {
+	success := log.Logger
+
+	// Logging only one of every sample successful requests.
+	if cfg.Sample > 1 {
+		success = success.Sample(&zerolog.BasicSampler{N: cfg.Sample})
+	}
+
+	return &AccessLogger{protocol: protocol, success: success}
+}
+
+// Unary gRPC server access log interceptor.
+func (l *AccessLogger) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+
+	// Call the handler.
+	res, err := handler(ctx, req)
+
+	l.LogRequest(ctx, info.FullMethod, peerAddr(ctx), start, status.Code(err), err)
+
+	return res, err
+}
+
+// Stream gRPC server access log interceptor.
+func (l *AccessLogger) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+
+	// Call the handler.
+	err := handler(srv, ss)
+
+	l.LogRequest(ss.Context(), info.FullMethod, peerAddr(ss.Context()), start, status.Code(err), err)
+
+	return err
+}
+
+// Writing an access log entry and observing request count and latency by
+// method and status code. Server errors are logged at error level and client
+// errors at warn level, both are never sampled.
+func (l *AccessLogger) LogRequest(ctx context.Context, method, addr string, start time.Time, code codes.Code, err error) {
+	var event *zerolog.Event
+
+	switch code {
+	case codes.OK:
+		event = l.success.Info()
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.DeadlineExceeded, codes.Unimplemented:
+		event = log.Error().Err(err)
+	default:
+		event = log.Warn().Err(err)
+	}
+
+	// Added request id.
+	if id, ok := requestid.FromContext(ctx); ok {
+		event.Str("request_id", id)
+	}
+
+	if addr != "" {
+		event.Str("peer", addr)
+	}
+
+	// Added peer certificate subject.
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(info.State.PeerCertificates) != 0 {
+			event.Str("subject", info.State.PeerCertificates[0].Subject.String())
+		}
+	}
+
+	event.Str("method", method).
+		Str("code", code.String()).
+		Dur("latency", time.Since(start)).
+		Msg(l.protocol + " request")
+
+	metrics.RequestsTotal.WithLabelValues(method, code.String()).Inc()
+	metrics.RequestDuration.WithLabelValues(method, code.String()).Observe(time.Since(start).Seconds())
+}
+
+// Getting the gRPC peer address.
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+
+	return ""
+}
+
+// Unary gRPC server request id interceptor.
+func requestIdUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(withRequestId(ctx), req)
+}
+
+// Stream gRPC server request id interceptor.
+func requestIdStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &serverStream{ServerStream: ss, ctx: withRequestId(ss.Context())})
+}
+
+// Adding incoming or a new request id and request logger to the context.
+//
+// The request id is sent back in the response header.
+func withRequestId(ctx context.Context) context.Context {
+	var incoming string
+
+	// Getting request id from incoming metadata.
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(requestid.Key); len(v) != 0 {
+			incoming = v[0]
+		}
+	}
+
+	id := requestid.FromIncoming(incoming)
+
+	// Sending request id in the response header.
+	if err := grpc.SetHeader(ctx, metadata.Pairs(requestid.Key, id)); err != nil {
+		log.Debug().Err(err).Msg("error setting request id header")
+	}
+
+	logger := log.With().Str("request_id", id).Logger()
+
+	return logger.WithContext(requestid.NewContext(ctx, id))
+}
+
+// Server stream with request context structure.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Getting stream context.
+func (s *serverStream) Context() context.Context { return s.ctx }
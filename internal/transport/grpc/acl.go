@@ -0,0 +1,82 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package grpc
+
+import (
+	"context"
+
+	"github.com/durudex/durudex-post-service/internal/acl"
+	"github.com/durudex/durudex-post-service/pkg/requestid"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// gRPC authorization structure.
+type authorization struct{ acl *acl.ACL }
+
+// Unary gRPC server authorization interceptor.
+func (a *authorization) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := a.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// Stream gRPC server authorization interceptor.
+func (a *authorization) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+
+	return handler(srv, ss)
+}
+
+// Authorizing the peer certificate identity to call the method. Denied calls
+// are written to the audit log.
+func (a *authorization) authorize(ctx context.Context, method string) error {
+	var identities []string
+
+	// Getting peer certificate identities.
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(info.State.PeerCertificates) != 0 {
+			identities = acl.Identities(info.State.PeerCertificates[0])
+		}
+	}
+
+	// Check is identity allowed to call the method.
+	if len(identities) != 0 && a.acl.Allowed(identities, method) {
+		return nil
+	}
+
+	event := log.Warn().Str("log", "audit")
+
+	// Added request id.
+	if id, ok := requestid.FromContext(ctx); ok {
+		event.Str("request_id", id)
+	}
+
+	event.Strs("identities", identities).Str("method", method).Msg("gRPC call denied")
+
+	return status.Error(codes.PermissionDenied, "Permission denied")
+}
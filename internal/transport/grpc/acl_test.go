@@ -0,0 +1,158 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/internal/acl"
+	"github.com/durudex/durudex-post-service/internal/config"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Creating a new context with the peer certificate common name.
+func peerContext(commonName string) context.Context {
+	p := &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50051}}
+
+	if commonName != "" {
+		p.AuthInfo = credentials.TLSInfo{State: tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: commonName}}},
+		}}
+	}
+
+	return peer.NewContext(context.Background(), p)
+}
+
+// Testing authorizing calls of peer certificate identities.
+func TestAuthorization(t *testing.T) {
+	a := &authorization{acl: acl.New(config.ACLConfig{
+		Enable: true,
+		Rules: []config.ACLRule{
+			{Identity: "*", Methods: []string{"/grpc.health.v1.Health/*"}},
+			{
+				Identity: "gateway.service.durudex.local",
+				Methods:  []string{"/durudex.v1.PostService/GetPost", "/durudex.v1.PostService/WatchPosts"},
+			},
+			{Identity: "moderation.service.durudex.local", Methods: []string{"/durudex.v1.PostService/*"}},
+		},
+	})}
+
+	// Tests structures.
+	tests := []struct {
+		name       string
+		identity   string
+		method     string
+		stream     bool
+		wantDenied bool
+	}{
+		{name: "OK", identity: "gateway.service.durudex.local", method: "/durudex.v1.PostService/GetPost"},
+		{name: "Stream", identity: "gateway.service.durudex.local", method: "/durudex.v1.PostService/WatchPosts", stream: true},
+		{name: "Service Wildcard", identity: "moderation.service.durudex.local", method: "/durudex.v1.PostService/FindSimilarPosts"},
+		{name: "Any Identity", identity: "unknown.service.durudex.local", method: "/grpc.health.v1.Health/Check"},
+		{
+			name:       "Denied",
+			identity:   "gateway.service.durudex.local",
+			method:     "/durudex.v1.PostService/FindSimilarPosts",
+			wantDenied: true,
+		},
+		{
+			name:       "Denied Stream",
+			identity:   "unknown.service.durudex.local",
+			method:     "/durudex.v1.PostService/WatchPosts",
+			stream:     true,
+			wantDenied: true,
+		},
+		{name: "Without Certificate", method: "/durudex.v1.PostService/GetPost", wantDenied: true},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			// Capturing audit log.
+			logger := log.Logger
+			log.Logger = zerolog.New(&buf)
+			defer func() { log.Logger = logger }()
+
+			var called bool
+
+			ctx := peerContext(tt.identity)
+
+			var err error
+
+			// Calling the authorization interceptor.
+			if tt.stream {
+				err = a.streamInterceptor(nil, &contextStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: tt.method},
+					func(srv interface{}, ss grpc.ServerStream) error {
+						called = true
+						return nil
+					})
+			} else {
+				_, err = a.unaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method},
+					func(ctx context.Context, req interface{}) (interface{}, error) {
+						called = true
+						return nil, nil
+					})
+			}
+
+			if !tt.wantDenied {
+				if err != nil || !called || buf.Len() != 0 {
+					t.Errorf("error authorizing call: %v, called %t, log %s", err, called, buf.String())
+				}
+
+				return
+			}
+
+			// Check for permission denied status code.
+			if status.Code(err) != codes.PermissionDenied || called {
+				t.Errorf("error denying call: %v, called %t", err, called)
+			}
+
+			var entry struct {
+				Log        string   `json:"log"`
+				Method     string   `json:"method"`
+				Identities []string `json:"identities"`
+			}
+
+			// Check for audit log entry.
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("error decoding audit log: %s", err.Error())
+			}
+
+			if entry.Log != "audit" || entry.Method != tt.method ||
+				(tt.identity != "" && (len(entry.Identities) != 1 || entry.Identities[0] != tt.identity)) {
+				t.Errorf("error audit log entry: %s", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package grpc
+
+import (
+	"context"
+
+	"github.com/durudex/durudex-post-service/internal/auth"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Authorization metadata key.
+const authorizationKey = "authorization"
+
+// gRPC authentication structure.
+type authentication struct{ authenticator *auth.Authenticator }
+
+// Unary gRPC server authentication interceptor.
+func (a *authentication) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// Stream gRPC server authentication interceptor.
+func (a *authentication) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// Authenticating the request access token and adding its subject to the
+// context. Requests without an access token are passed on unauthenticated.
+func (a *authentication) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	values := md.Get(authorizationKey)
+	if len(values) == 0 {
+		return ctx, nil
+	}
+
+	token, ok := auth.BearerToken(values[0])
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Invalid access token")
+	}
+
+	// Authenticating access token.
+	subject, err := a.authenticator.Authenticate(token)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("error authenticating request")
+		return nil, status.Error(codes.Unauthenticated, "Invalid access token")
+	}
+
+	return auth.NewContext(ctx, subject), nil
+}
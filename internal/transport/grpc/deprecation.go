@@ -0,0 +1,82 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	v1 "github.com/durudex/durudex-post-service/pkg/pb/durudex/v1"
+	v2 "github.com/durudex/durudex-post-service/pkg/pb/durudex/v2"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Deprecation response header keys.
+const (
+	deprecationKey = "deprecation"
+	linkKey        = "link"
+)
+
+// Deprecated services by successor service names.
+var deprecatedServices = map[string]string{
+	v1.PostService_ServiceDesc.ServiceName: v2.PostService_ServiceDesc.ServiceName,
+}
+
+// Unary gRPC server deprecation interceptor.
+func deprecationUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	deprecate(ctx, info.FullMethod)
+
+	return handler(ctx, req)
+}
+
+// Stream gRPC server deprecation interceptor.
+func deprecationStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	deprecate(ss.Context(), info.FullMethod)
+
+	return handler(srv, ss)
+}
+
+// Sending deprecation and successor version headers of the deprecated service
+// methods.
+func deprecate(ctx context.Context, method string) {
+	successor, ok := deprecatedServices[serviceName(method)]
+	if !ok {
+		return
+	}
+
+	md := metadata.Pairs(deprecationKey, "true", linkKey, "</"+successor+">; rel=\"successor-version\"")
+
+	// Sending deprecation response header.
+	if err := grpc.SetHeader(ctx, md); err != nil {
+		log.Debug().Err(err).Msg("error setting deprecation header")
+	}
+}
+
+// Getting service name of the full method name.
+func serviceName(fullMethod string) string {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i]
+	}
+
+	return ""
+}
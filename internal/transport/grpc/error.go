@@ -18,34 +18,125 @@
 package grpc
 
 import (
+	"context"
 	"errors"
 
 	"github.com/durudex/durudex-post-service/internal/domain"
 
+	"github.com/rs/zerolog/log"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
+// Domain of error reasons.
+const errorDomain = "post.service.durudex.com"
+
+// gRPC status codes of domain error codes, shared by the Connect and HTTP
+// transports.
+var errorCodes = map[domain.Code]codes.Code{
+	domain.CodeInternal:          codes.Internal,
+	domain.CodeNotFound:          codes.NotFound,
+	domain.CodeAlreadyExists:     codes.AlreadyExists,
+	domain.CodeInvalidArgument:   codes.InvalidArgument,
+	domain.CodeResourceExhausted: codes.ResourceExhausted,
+	domain.CodeUnauthenticated:   codes.Unauthenticated,
+	domain.CodeOutOfRange:        codes.OutOfRange,
+	domain.CodeUnavailable:       codes.Unavailable,
+}
+
+// Getting the gRPC status code of the domain error code, unknown codes are
+// internal errors.
+func ErrorCode(code domain.Code) codes.Code {
+	if c, ok := errorCodes[code]; ok {
+		return c
+	}
+
+	return codes.Internal
+}
+
 // gRPC server error handler.
 func errorHandler(err error) error {
 	var e *domain.Error
 
 	// Check if error is a domain.Error.
 	if errors.As(err, &e) {
-		switch e.Code {
-		case domain.CodeNotFound:
-			// Return gRPC error with status code not found.
-			return status.Error(codes.NotFound, e.Message)
-		case domain.CodeAlreadyExists:
-			// Return gRPC error with status code already exists.
-			return status.Error(codes.AlreadyExists, e.Message)
-		case domain.CodeInvalidArgument:
-			// Return gRPC error with status code invalid argument.
-			return status.Error(codes.InvalidArgument, e.Message)
-		case domain.CodeInternal:
+		code := ErrorCode(e.Code)
+
+		// Internal error details are hidden.
+		if code == codes.Internal {
 			return status.Error(codes.Internal, "Internal Server Error")
 		}
+
+		return newStatus(code, e)
+	}
+
+	// Check if error is a gRPC status, such as validation errors.
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	// Check if error is a context error of a cancelled or expired call.
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	// Unexpected error details are hidden.
+	log.Error().Err(err).Msg("unexpected gRPC handler error")
+
+	return status.Error(codes.Internal, "Internal Server Error")
+}
+
+// Creating a new gRPC status error with the domain error details.
+func newStatus(code codes.Code, e *domain.Error) error {
+	st := status.New(code, e.Message).Proto()
+
+	// Added status details.
+	for _, detail := range ErrorDetails(e) {
+		any, err := anypb.New(detail)
+		if err != nil {
+			log.Error().Err(err).Msg("error adding status details")
+			continue
+		}
+
+		st.Details = append(st.Details, any)
+	}
+
+	return status.FromProto(st).Err()
+}
+
+// Getting status details of the domain error. These are ErrorInfo with the
+// reason and metadata, BadRequest with field violations and RetryInfo.
+func ErrorDetails(e *domain.Error) []proto.Message {
+	var details []proto.Message
+
+	// Added error reason.
+	if e.Reason != "" {
+		details = append(details, &errdetails.ErrorInfo{
+			Reason:   e.Reason,
+			Domain:   errorDomain,
+			Metadata: e.Metadata,
+		})
+	}
+
+	// Added field violations.
+	if len(e.Violations) != 0 {
+		violations := make([]*errdetails.BadRequest_FieldViolation, len(e.Violations))
+
+		for i, v := range e.Violations {
+			violations[i] = &errdetails.BadRequest_FieldViolation{Field: v.Field, Description: v.Description}
+		}
+
+		details = append(details, &errdetails.BadRequest{FieldViolations: violations})
+	}
+
+	// Added retry delay.
+	if e.RetryDelay > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(e.RetryDelay)})
 	}
 
-	return err
+	return details
 }
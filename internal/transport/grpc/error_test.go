@@ -0,0 +1,84 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Testing mapping errors to gRPC status errors.
+func TestErrorHandler(t *testing.T) {
+	// Tests structures.
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    codes.Code
+		wantMessage string
+	}{
+		{
+			name:        "Internal",
+			err:         &domain.Error{Code: domain.CodeInternal, Message: "database is down"},
+			wantCode:    codes.Internal,
+			wantMessage: "Internal Server Error",
+		},
+		{
+			name:        "Not Found",
+			err:         &domain.Error{Code: domain.CodeNotFound, Message: "Post not found", Reason: domain.ReasonPostNotFound},
+			wantCode:    codes.NotFound,
+			wantMessage: "Post not found",
+		},
+		{
+			name:        "Status",
+			err:         status.Error(codes.PermissionDenied, "Permission denied"),
+			wantCode:    codes.PermissionDenied,
+			wantMessage: "Permission denied",
+		},
+		{
+			name:        "Canceled",
+			err:         fmt.Errorf("error watching posts: %w", context.Canceled),
+			wantCode:    codes.Canceled,
+			wantMessage: "error watching posts: context canceled",
+		},
+		{
+			name:        "Unexpected",
+			err:         errors.New("connection reset by peer"),
+			wantCode:    codes.Internal,
+			wantMessage: "Internal Server Error",
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, ok := status.FromError(errorHandler(tt.err))
+
+			// Check for status code and message.
+			if !ok || st.Code() != tt.wantCode || st.Message() != tt.wantMessage {
+				t.Errorf("error mapping error: got %v", st)
+			}
+		})
+	}
+}
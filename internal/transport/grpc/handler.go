@@ -20,6 +20,7 @@ package grpc
 import (
 	"github.com/durudex/durudex-post-service/internal/service"
 	v1 "github.com/durudex/durudex-post-service/internal/transport/grpc/v1"
+	v2 "github.com/durudex/durudex-post-service/internal/transport/grpc/v2"
 
 	"google.golang.org/grpc"
 )
@@ -35,4 +36,5 @@ func NewHandler(service *service.Service) *Handler {
 // Registering gRPC version handlers.
 func (h *Handler) RegisterHandlers(srv *grpc.Server) {
 	v1.NewHandler(h.service).RegisterHandlers(srv)
+	v2.NewHandler(h.service).RegisterHandlers(srv)
 }
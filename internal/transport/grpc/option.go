@@ -20,7 +20,10 @@ package grpc
 import (
 	"context"
 
+	"github.com/durudex/durudex-post-service/internal/acl"
+	"github.com/durudex/durudex-post-service/internal/auth"
 	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/tracing"
 	"github.com/durudex/durudex-post-service/pkg/tls"
 
 	"github.com/rs/zerolog/log"
@@ -28,26 +31,67 @@ import (
 	"google.golang.org/grpc/credentials"
 )
 
+// gRPC server extension options structure.
+type options struct {
+	reporter      PanicReporter
+	authenticator *auth.Authenticator
+	certs         *tls.Reloader
+}
+
+// gRPC server extension option.
+type Option func(o *options)
+
+// Setting a reporter of recovered handler panics.
+func WithPanicReporter(reporter PanicReporter) Option {
+	return func(o *options) { o.reporter = reporter }
+}
+
+// Setting an authenticator of request access tokens.
+func WithAuthenticator(authenticator *auth.Authenticator) Option {
+	return func(o *options) { o.authenticator = authenticator }
+}
+
+// Setting a reloader of server TLS certificates.
+func WithCertificates(certs *tls.Reloader) Option {
+	return func(o *options) { o.certs = certs }
+}
+
 // Getting gRPC server options.
-func getOptions(cfg config.TLSConfig) []grpc.ServerOption {
+func getOptions(cfg config.GRPCConfig, o options) []grpc.ServerOption {
 	log.Debug().Msg("Getting gRPC server options...")
 
-	var opts []grpc.ServerOption
+	// Peer certificate identities are required for authorization.
+	if cfg.ACL.Enable && !cfg.TLS.Enable {
+		log.Fatal().Msg("gRPC access control list requires TLS")
+	}
+
+	unary, stream := getInterceptors(cfg, o)
 
 	// Added basic server options.
-	opts = append(opts,
-		// Unary interceptor.
-		grpc.UnaryInterceptor(unaryInterceptor),
-		// Stream interceptor.
-		grpc.StreamInterceptor(streamInterceptor),
-	)
-
-	if cfg.Enable {
-		creds, err := tls.LoadTLSConfig(cfg.CACert, cfg.Cert, cfg.Key)
-		if err != nil {
-			log.Fatal().Err(err).Msg("failed to load TLS credentials")
+	opts := []grpc.ServerOption{
+		// Unary interceptors.
+		grpc.ChainUnaryInterceptor(unary...),
+		// Stream interceptors.
+		grpc.ChainStreamInterceptor(stream...),
+	}
+
+	if cfg.TLS.Enable {
+		certs := o.certs
+
+		// Loading certificates without reloading.
+		if certs == nil {
+			var err error
+
+			certs, err = tls.NewReloader(cfg.TLS.CACert, cfg.TLS.Cert, cfg.TLS.Key, cfg.TLS.ExpiryWarning)
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to load TLS credentials")
+			}
 		}
 
+		creds := certs.Config()
+		// Negotiating HTTP/2 in handshakes with the reloaded certificates.
+		creds.NextProtos = []string{"h2"}
+
 		// Append server credential options.
 		opts = append(opts, grpc.Creds(credentials.NewTLS(creds)))
 	}
@@ -55,22 +99,65 @@ func getOptions(cfg config.TLSConfig) []grpc.ServerOption {
 	return opts
 }
 
+// Getting gRPC server interceptor chains.
+func getInterceptors(cfg config.GRPCConfig, o options) ([]grpc.UnaryServerInterceptor, []grpc.StreamServerInterceptor) {
+	// Tracing interceptors.
+	unaryTracing, streamTracing := tracing.ServerInterceptors()
+	// Access log interceptors.
+	access := NewAccessLogger("gRPC", cfg.AccessLog)
+	// Panic recovery interceptors of the interceptor chain.
+	chainRecovery := &recovery{reporter: o.reporter, access: access}
+	// Panic recovery interceptors of the handler.
+	handlerRecovery := &recovery{reporter: o.reporter}
+
+	// The request id is assigned first, so every log entry and panic report of
+	// the call has it. Recovery follows, so panics of other interceptors are
+	// recovered too. The access log precedes authorization and authentication,
+	// so rejected calls are logged and counted.
+	unary := []grpc.UnaryServerInterceptor{requestIdUnaryInterceptor, chainRecovery.unaryInterceptor, unaryTracing,
+		access.unaryInterceptor, deprecationUnaryInterceptor, unaryInterceptor}
+	stream := []grpc.StreamServerInterceptor{requestIdStreamInterceptor, chainRecovery.streamInterceptor, streamTracing,
+		access.streamInterceptor, deprecationStreamInterceptor, streamInterceptor}
+
+	// Authorization interceptors.
+	if cfg.ACL.Enable {
+		authorization := &authorization{acl: acl.New(cfg.ACL)}
+
+		unary = append(unary, authorization.unaryInterceptor)
+		stream = append(stream, authorization.streamInterceptor)
+	}
+
+	// Authentication interceptors.
+	if o.authenticator != nil {
+		authentication := &authentication{authenticator: o.authenticator}
+
+		unary = append(unary, authentication.unaryInterceptor)
+		stream = append(stream, authentication.streamInterceptor)
+	}
+
+	// Handler panics are recovered innermost, so the tracing, access log and
+	// metrics interceptors observe them as internal errors.
+	return append(unary, handlerRecovery.unaryInterceptor), append(stream, handlerRecovery.streamInterceptor)
+}
+
 // Unary gRPC server interceptor.
 func unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-	log.Info().Str("method", info.FullMethod).Msg("Unary interceptor")
-
 	// Call the handler.
 	h, err := handler(ctx, req)
 	if err != nil {
-		return h, errorHandler(err)
+		err = errorHandler(err)
 	}
 
-	return h, nil
+	return h, err
 }
 
 // Stream gRPC server interceptor.
 func streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-	log.Info().Str("method", info.FullMethod).Msg("Stream interceptor")
+	// Call the handler.
+	err := handler(srv, ss)
+	if err != nil {
+		err = errorHandler(err)
+	}
 
-	return handler(srv, ss)
+	return err
 }
@@ -0,0 +1,125 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/internal/auth"
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/metrics"
+	"github.com/durudex/durudex-post-service/internal/service"
+	v1 "github.com/durudex/durudex-post-service/pkg/pb/durudex/v1"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/segmentio/ksuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// Testing counting calls rejected by authorization and authentication.
+func TestInterceptors_Rejected(t *testing.T) {
+	authenticator, err := auth.NewAuthenticator(config.AuthConfig{Enable: true, Audience: "durudex-post-service", Secret: "secret"})
+	if err != nil {
+		t.Fatalf("error creating authenticator: %s", err.Error())
+	}
+
+	// Tests structures.
+	tests := []struct {
+		name     string
+		cfg      config.GRPCConfig
+		method   string
+		call     func(ctx context.Context, client v1.PostServiceClient) error
+		wantCode codes.Code
+	}{
+		{
+			name: "Permission Denied",
+			cfg: config.GRPCConfig{ACL: config.ACLConfig{
+				Enable: true,
+				Rules:  []config.ACLRule{{Identity: "*", Methods: []string{"/grpc.health.v1.Health/*"}}},
+			}},
+			method: "/durudex.v1.PostService/GetPost",
+			call: func(ctx context.Context, client v1.PostServiceClient) error {
+				_, err := client.GetPost(ctx, &v1.GetPostRequest{Id: ksuid.New().Bytes()})
+				return err
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:   "Unauthenticated Stream",
+			method: "/durudex.v1.PostService/WatchPosts",
+			call: func(ctx context.Context, client v1.PostServiceClient) error {
+				stream, err := client.WatchPosts(ctx, &v1.WatchPostsRequest{AuthorIds: [][]byte{ksuid.New().Bytes()}})
+				if err != nil {
+					return err
+				}
+
+				_, err = stream.Recv()
+
+				return err
+			},
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unary, stream := getInterceptors(tt.cfg, options{authenticator: authenticator})
+
+			// Creating a new gRPC server with the interceptor chains.
+			srv := grpc.NewServer(grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...))
+			NewHandler(&service.Service{}).RegisterHandlers(srv)
+
+			lis := bufconn.Listen(1 << 20)
+			go srv.Serve(lis)
+			defer srv.Stop()
+
+			// Connecting to the gRPC server.
+			conn, err := grpc.Dial("bufnet",
+				grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+				grpc.WithTransportCredentials(insecure.NewCredentials()),
+			)
+			if err != nil {
+				t.Fatalf("error connecting to gRPC server: %s", err.Error())
+			}
+			defer conn.Close()
+
+			requests := metrics.RequestsTotal.WithLabelValues(tt.method, tt.wantCode.String())
+			before := testutil.ToFloat64(requests)
+
+			ctx := metadata.AppendToOutgoingContext(context.Background(), authorizationKey, "Bearer invalid")
+
+			// Check for rejected status code.
+			if err := tt.call(ctx, v1.NewPostServiceClient(conn)); status.Code(err) != tt.wantCode {
+				t.Errorf("error status code: got %v, want %s", err, tt.wantCode)
+			}
+
+			// Check for number of counted requests.
+			if got := testutil.ToFloat64(requests) - before; got != 1 {
+				t.Errorf("error counted requests: got %v, want 1", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/metrics"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Handler panic reporter interface.
+type PanicReporter interface {
+	// Reporting a recovered handler panic.
+	ReportPanic(ctx context.Context, method string, recovered interface{}, stack []byte)
+}
+
+// Handler panic reporter function.
+type PanicReporterFunc func(ctx context.Context, method string, recovered interface{}, stack []byte)
+
+// Reporting a recovered handler panic.
+func (f PanicReporterFunc) ReportPanic(ctx context.Context, method string, recovered interface{}, stack []byte) {
+	f(ctx, method, recovered, stack)
+}
+
+// gRPC panic recovery structure.
+type recovery struct {
+	reporter PanicReporter
+	// Access logger of recovered calls. It is set only for the interceptors
+	// running outside the access log and metrics interceptors, which are
+	// skipped by the panic.
+	access *AccessLogger
+}
+
+// Unary gRPC server recovery interceptor.
+func (r *recovery) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res interface{}, err error) {
+	start := time.Now()
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = r.recover(ctx, info.FullMethod, start, p)
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// Stream gRPC server recovery interceptor.
+func (r *recovery) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	start := time.Now()
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = r.recover(ss.Context(), info.FullMethod, start, p)
+		}
+	}()
+
+	return handler(srv, ss)
+}
+
+// Handling a recovered panic.
+func (r *recovery) recover(ctx context.Context, method string, start time.Time, p interface{}) error {
+	RecoverPanic(ctx, r.reporter, method, p)
+
+	err := status.Error(codes.Internal, "Internal Server Error")
+
+	// Writing the access log entry and request metrics skipped by the panic.
+	if r.access != nil {
+		r.access.LogRequest(ctx, method, peerAddr(ctx), start, codes.Internal, err)
+	}
+
+	return err
+}
+
+// Handling a recovered handler panic of any listener: logging the stack with
+// the request logger, counting the panic and reporting it.
+func RecoverPanic(ctx context.Context, reporter PanicReporter, method string, p interface{}) {
+	stack := debug.Stack()
+
+	// Getting request logger or the global logger.
+	logger := log.Ctx(ctx)
+	if logger.GetLevel() == zerolog.Disabled {
+		logger = &log.Logger
+	}
+
+	logger.Error().
+		Str("method", method).
+		Str("panic", fmt.Sprint(p)).
+		Str("stack", string(stack)).
+		Msg("recovered handler panic")
+
+	metrics.PanicsTotal.WithLabelValues(method).Inc()
+
+	// Reporting the panic.
+	if reporter != nil {
+		reporter.ReportPanic(ctx, method, p, stack)
+	}
+}
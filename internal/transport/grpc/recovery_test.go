@@ -0,0 +1,311 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/metrics"
+	"github.com/durudex/durudex-post-service/pkg/requestid"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Recorded panic report structure.
+type panicReport struct {
+	method    string
+	requestId string
+	recovered interface{}
+}
+
+// Panic reporter recorder structure.
+type panicRecorder struct {
+	mu      sync.Mutex
+	reports []panicReport
+}
+
+// Recording a recovered handler panic.
+func (r *panicRecorder) ReportPanic(ctx context.Context, method string, recovered interface{}, stack []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id, _ := requestid.FromContext(ctx)
+	r.reports = append(r.reports, panicReport{method: method, requestId: id, recovered: recovered})
+}
+
+// Synchronized log buffer structure.
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Writing a log entry.
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+// Getting log entries with the message.
+func (b *logBuffer) entries(t *testing.T, message string) []map[string]interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var entries []map[string]interface{}
+
+	for _, line := range bytes.Split(bytes.TrimSpace(b.buf.Bytes()), []byte("\n")) {
+		var entry map[string]interface{}
+
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("error decoding log entry: %s", err.Error())
+		}
+
+		if entry["message"] == message {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// Capturing global logger output.
+func captureLog(t *testing.T) *logBuffer {
+	var buf logBuffer
+
+	logger := log.Logger
+	log.Logger = zerolog.New(&buf)
+	t.Cleanup(func() { log.Logger = logger })
+
+	return &buf
+}
+
+// Server stream with context structure.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Getting stream context.
+func (s *contextStream) Context() context.Context { return s.ctx }
+
+// Setting stream header.
+func (s *contextStream) SetHeader(metadata.MD) error { return nil }
+
+// Testing recovering interceptor chain panics.
+func TestRecovery(t *testing.T) {
+	// Tests structures.
+	tests := []struct {
+		name   string
+		method string
+		call   func(r *recovery, ctx context.Context, method string) error
+	}{
+		{
+			name:   "Unary",
+			method: "/durudex.v1.PostService/GetPost",
+			call: func(r *recovery, ctx context.Context, method string) error {
+				info := &grpc.UnaryServerInfo{FullMethod: method}
+
+				_, err := requestIdUnaryInterceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+					return r.unaryInterceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+						panic("unary panic")
+					})
+				})
+
+				return err
+			},
+		},
+		{
+			name:   "Stream",
+			method: "/durudex.v1.PostService/WatchPosts",
+			call: func(r *recovery, ctx context.Context, method string) error {
+				info := &grpc.StreamServerInfo{FullMethod: method}
+
+				return requestIdStreamInterceptor(nil, &contextStream{ctx: ctx}, info, func(srv interface{}, ss grpc.ServerStream) error {
+					return r.streamInterceptor(srv, ss, info, func(srv interface{}, ss grpc.ServerStream) error {
+						panic("stream panic")
+					})
+				})
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := captureLog(t)
+			reporter := &panicRecorder{}
+
+			r := &recovery{reporter: reporter, access: NewAccessLogger("gRPC", config.AccessLogConfig{})}
+
+			panics := metrics.PanicsTotal.WithLabelValues(tt.method)
+			requests := metrics.RequestsTotal.WithLabelValues(tt.method, codes.Internal.String())
+			panicsBefore, requestsBefore := testutil.ToFloat64(panics), testutil.ToFloat64(requests)
+
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestid.Key, "test-request"))
+
+			// Check for internal status code.
+			if err := tt.call(r, ctx, tt.method); status.Code(err) != codes.Internal {
+				t.Errorf("error status code: got %v, want Internal", err)
+			}
+
+			// Check for number of counted panics and requests.
+			if got := testutil.ToFloat64(panics) - panicsBefore; got != 1 {
+				t.Errorf("error counted panics: got %v, want 1", got)
+			}
+
+			if got := testutil.ToFloat64(requests) - requestsBefore; got != 1 {
+				t.Errorf("error counted requests: got %v, want 1", got)
+			}
+
+			// Check for reported panic with request id.
+			if len(reporter.reports) != 1 || reporter.reports[0].method != tt.method ||
+				reporter.reports[0].requestId != "test-request" || reporter.reports[0].recovered == nil {
+				t.Errorf("error reported panics: %v", reporter.reports)
+			}
+
+			// Check for panic log entry with request id.
+			if entries := buf.entries(t, "recovered handler panic"); len(entries) != 1 ||
+				entries[0]["request_id"] != "test-request" {
+				t.Errorf("error panic log entries: %v", entries)
+			}
+
+			// Check for access log entry with request id.
+			if entries := buf.entries(t, "gRPC request"); len(entries) != 1 ||
+				entries[0]["request_id"] != "test-request" || entries[0]["code"] != codes.Internal.String() {
+				t.Errorf("error access log entries: %v", entries)
+			}
+		})
+	}
+}
+
+// Testing recovering handler panics of the server interceptor chain.
+func TestRecovery_Server(t *testing.T) {
+	buf := captureLog(t)
+	reporter := &panicRecorder{}
+
+	// Creating a new gRPC server with panicking handlers.
+	srv := grpc.NewServer(getOptions(config.GRPCConfig{}, options{reporter: reporter})...)
+	srv.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "test.Panic",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Unary",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/test.Panic/Unary"}
+
+				return interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+					panic("unary panic")
+				})
+			},
+		}},
+		Streams: []grpc.StreamDesc{{
+			StreamName:    "Stream",
+			ServerStreams: true,
+			Handler:       func(srv interface{}, stream grpc.ServerStream) error { panic("stream panic") },
+		}},
+	}, struct{}{})
+
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	// Connecting to the gRPC server.
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("error connecting to gRPC server: %s", err.Error())
+	}
+	defer conn.Close()
+
+	requests := metrics.RequestsTotal.WithLabelValues("/test.Panic/Unary", codes.Internal.String())
+	before := testutil.ToFloat64(requests)
+
+	var header metadata.MD
+
+	// Calling a panicking unary method.
+	err = conn.Invoke(context.Background(), "/test.Panic/Unary", &emptypb.Empty{}, &emptypb.Empty{}, grpc.Header(&header))
+	if status.Code(err) != codes.Internal {
+		t.Errorf("error unary status code: got %v, want Internal", err)
+	}
+
+	// Check for response request id header.
+	if len(header.Get(requestid.Key)) != 1 {
+		t.Fatalf("error request id header: %v", header)
+	}
+
+	// Check for counted request.
+	if got := testutil.ToFloat64(requests) - before; got != 1 {
+		t.Errorf("error counted requests: got %v, want 1", got)
+	}
+
+	// Calling a panicking stream method.
+	stream, err := conn.NewStream(context.Background(), &grpc.StreamDesc{ServerStreams: true}, "/test.Panic/Stream")
+	if err != nil {
+		t.Fatalf("error creating a new stream: %s", err.Error())
+	}
+
+	if err := stream.SendMsg(&emptypb.Empty{}); err != nil {
+		t.Fatalf("error sending stream message: %s", err.Error())
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("error closing stream: %s", err.Error())
+	}
+
+	if err := stream.RecvMsg(&emptypb.Empty{}); status.Code(err) != codes.Internal {
+		t.Errorf("error stream status code: got %v, want Internal", err)
+	}
+
+	// Check for reported panics with request id.
+	if len(reporter.reports) != 2 || reporter.reports[0].method != "/test.Panic/Unary" ||
+		reporter.reports[1].method != "/test.Panic/Stream" || reporter.reports[0].requestId != header.Get(requestid.Key)[0] ||
+		reporter.reports[1].requestId == "" {
+		t.Errorf("error reported panics: %v", reporter.reports)
+	}
+
+	// Check for panic and access log entries with request id.
+	for _, message := range []string{"recovered handler panic", "gRPC request"} {
+		entries := buf.entries(t, message)
+		if len(entries) != 2 {
+			t.Fatalf("error %q log entries: %v", message, entries)
+		}
+
+		for _, entry := range entries {
+			if entry["request_id"] == nil || entry["request_id"] == "" {
+				t.Errorf("error %q log entry without request id: %v", message, entry)
+			}
+		}
+	}
+}
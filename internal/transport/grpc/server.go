@@ -18,30 +18,51 @@
 package grpc
 
 import (
+	"context"
 	"net"
 
 	"github.com/durudex/durudex-post-service/internal/config"
 
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 // gRPC server structure.
 type Server struct {
 	server  *grpc.Server
+	health  *health.Server
 	config  config.GRPCConfig
 	handler *Handler
 }
 
 // Creating a new gRPC server.
-func NewServer(cfg config.GRPCConfig, handler *Handler) *Server {
-	options := getOptions(cfg.TLS)
+func NewServer(cfg config.GRPCConfig, handler *Handler, opts ...Option) *Server {
+	var o options
 
-	return &Server{
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	options := getOptions(cfg, o)
+
+	srv := &Server{
 		server:  grpc.NewServer(options...),
+		health:  health.NewServer(),
 		config:  cfg,
 		handler: handler,
 	}
+
+	// Registering gRPC handlers.
+	srv.handler.RegisterHandlers(srv.server)
+	// Registering gRPC health service.
+	grpc_health_v1.RegisterHealthServer(srv.server, srv.health)
+
+	// Not serving until the first health check.
+	srv.SetServing(false)
+
+	return srv
 }
 
 // Running gRPC server.
@@ -56,18 +77,68 @@ func (s *Server) Run() {
 		log.Fatal().Err(err).Msg("error creating tcp listener")
 	}
 
-	// Registering gRPC handlers.
-	s.handler.RegisterHandlers(s.server)
-
 	// Running gRPC server.
 	if err := s.server.Serve(lis); err != nil {
 		log.Fatal().Err(err).Msg("error running gRPC server")
 	}
 }
 
+// Marking all services as not serving, so that clients stop sending new
+// requests before the servers are drained. Later health checks do not change
+// the status.
+func (s *Server) StopServing(context.Context) error {
+	log.Info().Msg("Setting gRPC health status to not serving")
+
+	s.health.Shutdown()
+
+	return nil
+}
+
 // Stopping gRPC server.
-func (s *Server) Stop() {
+//
+// Services are marked as not serving and in-flight requests are drained until
+// the context is done, after which the server is stopped immediately.
+func (s *Server) Stop(ctx context.Context) error {
 	log.Info().Msg("Stopping gRPC server...")
 
-	s.server.Stop()
+	// Set all services to not serving.
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		log.Warn().Msg("gRPC server drain deadline exceeded, stopping")
+
+		s.server.Stop()
+
+		return ctx.Err()
+	}
+}
+
+// Setting gRPC services health status.
+func (s *Server) SetServing(serving bool) {
+	status := grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	if serving {
+		status = grpc_health_v1.HealthCheckResponse_SERVING
+	}
+
+	log.Info().Str("status", status.String()).Msg("Setting gRPC health status")
+
+	// Set overall server status.
+	s.health.SetServingStatus("", status)
+
+	// Set status of every registered service.
+	for name := range s.server.GetServiceInfo() {
+		if name != grpc_health_v1.Health_ServiceDesc.ServiceName {
+			s.health.SetServingStatus(name, status)
+		}
+	}
 }
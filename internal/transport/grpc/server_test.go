@@ -0,0 +1,146 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/service"
+	"github.com/durudex/durudex-post-service/pkg/lifecycle"
+	v1 "github.com/durudex/durudex-post-service/pkg/pb/durudex/v1"
+
+	"github.com/segmentio/ksuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// Post service blocking getting a post until released.
+type blockingPost struct {
+	service.Post
+	entered chan struct{}
+	release chan struct{}
+}
+
+// Getting a post after release.
+func (p *blockingPost) Get(context.Context, ksuid.KSUID, domain.FieldMask) (domain.Post, error) {
+	close(p.entered)
+	<-p.release
+
+	return domain.Post{Text: "text"}, nil
+}
+
+// Event service signaling watching post events.
+type watchingEvent struct {
+	service.Event
+	watching chan struct{}
+}
+
+// Watching author post events.
+func (e *watchingEvent) Watch(ctx context.Context, authorIds []ksuid.KSUID, cursor *int64, send func(domain.PostEvent) error) error {
+	close(e.watching)
+
+	return e.Event.Watch(ctx, authorIds, cursor, send)
+}
+
+// Testing draining in-flight requests with an open watch stream on shutdown.
+func TestServer_ShutdownWatching(t *testing.T) {
+	post := &blockingPost{entered: make(chan struct{}), release: make(chan struct{})}
+	event := &watchingEvent{
+		Event:    service.NewEventService(nil, config.StreamConfig{Buffer: 1, MaxAuthors: 1}),
+		watching: make(chan struct{}),
+	}
+
+	// Creating a new gRPC server.
+	srv := NewServer(config.GRPCConfig{}, NewHandler(&service.Service{Post: post, Event: event}))
+
+	lis := bufconn.Listen(1 << 20)
+	go srv.server.Serve(lis)
+	defer srv.server.Stop()
+
+	// Connecting to the gRPC server.
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("error connecting to gRPC server: %s", err.Error())
+	}
+	defer conn.Close()
+
+	client := v1.NewPostServiceClient(conn)
+
+	// Opening a watch stream.
+	stream, err := client.WatchPosts(context.Background(), &v1.WatchPostsRequest{AuthorIds: [][]byte{ksuid.New().Bytes()}})
+	if err != nil {
+		t.Fatalf("error watching posts: %s", err.Error())
+	}
+
+	<-event.watching
+
+	// Sending an in-flight request.
+	errs := make(chan error, 1)
+
+	go func() {
+		_, err := client.GetPost(context.Background(), &v1.GetPostRequest{Id: ksuid.New().Bytes()})
+		errs <- err
+	}()
+
+	<-post.entered
+
+	// Creating a new lifecycle manager with the application stop order.
+	timeout := 5 * time.Second
+	lc := lifecycle.NewManager(timeout)
+
+	lc.OnStop("gRPC health", srv.StopServing)
+	lc.OnStop("post event streams", event.Stop)
+	lc.OnStopConcurrent(lifecycle.Hook{Name: "gRPC server", Stop: srv.Stop})
+
+	start := time.Now()
+	shutdown := make(chan error, 1)
+
+	// Shutting down.
+	go func() { shutdown <- lc.Shutdown() }()
+
+	// Check for the watch stream ended as unavailable.
+	if _, err := stream.Recv(); status.Code(err) != codes.Unavailable {
+		t.Errorf("error watch stream status: got %v, want Unavailable", err)
+	}
+
+	close(post.release)
+
+	// Check for the in-flight request drained.
+	if err := <-errs; err != nil {
+		t.Errorf("error getting post: %s", err.Error())
+	}
+
+	if err := <-shutdown; err != nil {
+		t.Errorf("error shutting down: %s", err.Error())
+	}
+
+	if elapsed := time.Since(start); elapsed >= timeout {
+		t.Errorf("error shutdown took %s", elapsed)
+	}
+}
@@ -34,5 +34,6 @@ func NewHandler(service *service.Service) *Handler {
 
 // Registering gRPC handlers.
 func (h *Handler) RegisterHandlers(srv *grpc.Server) {
-	v1.RegisterPostServiceServer(srv, NewPostHandler(h.service))
+	v1.RegisterPostServiceServer(srv, NewPostHandler(h.service.Post, h.service.Event))
+	v1.RegisterWebhookServiceServer(srv, NewWebhookHandler(h.service.Webhook))
 }
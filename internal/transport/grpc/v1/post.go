@@ -19,10 +19,13 @@ package v1
 
 import (
 	"context"
+	"time"
 
 	"github.com/durudex/dugopb/type/timestamp"
 	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/eventpb"
 	"github.com/durudex/durudex-post-service/internal/service"
+	"github.com/durudex/durudex-post-service/internal/transport/grpc/validate"
 	v1 "github.com/durudex/durudex-post-service/pkg/pb/durudex/v1"
 
 	"github.com/segmentio/ksuid"
@@ -31,21 +34,27 @@ import (
 // Sample gRPC server handler.
 type PostHandler struct {
 	service service.Post
+	event   service.Event
 	v1.UnimplementedPostServiceServer
 }
 
 // Creating a new post gRPC handler.
-func NewPostHandler(service service.Post) *PostHandler {
-	return &PostHandler{service: service}
+func NewPostHandler(service service.Post, event service.Event) *PostHandler {
+	return &PostHandler{service: service, event: event}
 }
 
 // Creating a new post handler.
 func (h *PostHandler) CreatePost(ctx context.Context, input *v1.CreatePostRequest) (*v1.CreatePostResponse, error) {
+	var v validator
+
+	// Validating request.
+	authorId := v.id("author_id", input.AuthorId, false)
+	if err := v.Err(); err != nil {
+		return &v1.CreatePostResponse{}, err
+	}
+
 	// Create a new post.
-	id, err := h.service.Create(ctx, domain.Post{
-		AuthorId: ksuid.FromBytesOrNil(input.AuthorId),
-		Text:     input.Text,
-	})
+	id, err := h.service.Create(ctx, domain.Post{AuthorId: authorId, Text: input.Text})
 	if err != nil {
 		return &v1.CreatePostResponse{}, err
 	}
@@ -55,29 +64,47 @@ func (h *PostHandler) CreatePost(ctx context.Context, input *v1.CreatePostReques
 
 // Getting a post handler.
 func (h *PostHandler) GetPost(ctx context.Context, input *v1.GetPostRequest) (*v1.GetPostResponse, error) {
+	var v validator
+
+	// Validating request.
+	id := v.id("id", input.Id, true)
+	mask := v.FieldMask("read_mask", input.ReadMask, domain.PostFieldAuthorId, domain.PostFieldText,
+		domain.PostFieldCreatedAt, domain.PostFieldUpdatedAt)
+
+	if err := v.Err(); err != nil {
+		return &v1.GetPostResponse{}, err
+	}
+
 	// Getting post by id.
-	post, err := h.service.Get(ctx, ksuid.FromBytesOrNil(input.Id))
+	post, err := h.service.Get(ctx, id, mask)
 	if err != nil {
 		return &v1.GetPostResponse{}, err
 	}
 
 	return &v1.GetPostResponse{
-		AuthorId:  post.AuthorId.Bytes(),
+		AuthorId:  idBytes(post.AuthorId),
 		Text:      post.Text,
 		UpdatedAt: timestamp.NewOptional(post.UpdatedAt),
+		CreatedAt: createdAt(post.CreatedAt),
 	}, nil
 }
 
 // Getting posts handler.
-func (h *Handler) GetPosts(ctx context.Context, input *v1.GetPostsRequest) (*v1.GetPostsResponse, error) {
+func (h *PostHandler) GetPosts(ctx context.Context, input *v1.GetPostsRequest) (*v1.GetPostsResponse, error) {
+	var v validator
+
+	// Validating request.
+	authorId := v.id("author_id", input.AuthorId, true)
+	sort := v.sortOptions("sort_options", input.SortOptions)
+	mask := v.FieldMask("read_mask", input.ReadMask, domain.PostFieldId, domain.PostFieldAuthorId,
+		domain.PostFieldText, domain.PostFieldFlagged, domain.PostFieldCreatedAt, domain.PostFieldUpdatedAt)
+
+	if err := v.Err(); err != nil {
+		return &v1.GetPostsResponse{}, err
+	}
+
 	// Getting posts.
-	posts, err := h.service.GetPosts(ctx, ksuid.FromBytesOrNil(input.AuthorId),
-		domain.SortOptions{
-			First:  input.SortOptions.First,
-			Last:   input.SortOptions.Last,
-			Before: ksuid.FromBytesOrNil(input.SortOptions.Before),
-			After:  ksuid.FromBytesOrNil(input.SortOptions.After),
-		})
+	posts, err := h.service.GetPosts(ctx, authorId, sort, validate.PostFilter(input.CreatedAfter, input.CreatedBefore), mask)
 	if err != nil {
 		return &v1.GetPostsResponse{}, err
 	}
@@ -86,9 +113,12 @@ func (h *Handler) GetPosts(ctx context.Context, input *v1.GetPostsRequest) (*v1.
 
 	for i, post := range posts {
 		responsePosts[i] = &v1.Post{
-			Id:        post.Id.Bytes(),
+			Id:        idBytes(post.Id),
+			AuthorId:  idBytes(post.AuthorId),
 			Text:      post.Text,
 			UpdatedAt: timestamp.NewOptional(post.UpdatedAt),
+			Flagged:   post.Flagged,
+			CreatedAt: createdAt(post.CreatedAt),
 		}
 	}
 
@@ -97,8 +127,18 @@ func (h *Handler) GetPosts(ctx context.Context, input *v1.GetPostsRequest) (*v1.
 
 // Deleting a post handler.
 func (h *PostHandler) DeletePost(ctx context.Context, input *v1.DeletePostRequest) (*v1.DeletePostResponse, error) {
+	var v validator
+
+	// Validating request.
+	id := v.id("id", input.Id, true)
+	authorId := v.id("author_id", input.AuthorId, false)
+
+	if err := v.Err(); err != nil {
+		return &v1.DeletePostResponse{}, err
+	}
+
 	// Deleting post.
-	if err := h.service.Delete(ctx, ksuid.FromBytesOrNil(input.Id), ksuid.FromBytesOrNil(input.AuthorId)); err != nil {
+	if err := h.service.Delete(ctx, id, authorId); err != nil {
 		return &v1.DeletePostResponse{}, err
 	}
 
@@ -107,12 +147,19 @@ func (h *PostHandler) DeletePost(ctx context.Context, input *v1.DeletePostReques
 
 // Updating a post handler.
 func (h *PostHandler) UpdatePost(ctx context.Context, input *v1.UpdatePostRequest) (*v1.UpdatePostResponse, error) {
+	var v validator
+
+	// Validating request.
+	id := v.id("id", input.Id, true)
+	authorId := v.id("author_id", input.AuthorId, false)
+	mask := v.FieldMask("update_mask", input.UpdateMask, domain.PostFieldText)
+
+	if err := v.Err(); err != nil {
+		return &v1.UpdatePostResponse{}, err
+	}
+
 	// Updating post.
-	if err := h.service.Update(ctx, domain.Post{
-		Id:       ksuid.FromBytesOrNil(input.Id),
-		AuthorId: ksuid.FromBytesOrNil(input.AuthorId),
-		Text:     input.Text,
-	}); err != nil {
+	if err := h.service.Update(ctx, domain.Post{Id: id, AuthorId: authorId, Text: input.Text}, mask); err != nil {
 		return &v1.UpdatePostResponse{}, err
 	}
 
@@ -121,10 +168,101 @@ func (h *PostHandler) UpdatePost(ctx context.Context, input *v1.UpdatePostReques
 
 // Getting total posts count.
 func (h *PostHandler) GetTotalPostsCount(ctx context.Context, input *v1.GetTotalPostsCountRequest) (*v1.GetTotalPostsCountResponse, error) {
-	count, err := h.service.GetTotalCount(ctx, ksuid.FromBytesOrNil(input.AuthorId))
+	var v validator
+
+	// Validating request.
+	authorId := v.id("author_id", input.AuthorId, true)
+	if err := v.Err(); err != nil {
+		return &v1.GetTotalPostsCountResponse{}, err
+	}
+
+	count, err := h.service.GetTotalCount(ctx, authorId, validate.PostFilter(input.CreatedAfter, input.CreatedBefore))
 	if err != nil {
 		return &v1.GetTotalPostsCountResponse{}, err
 	}
 
 	return &v1.GetTotalPostsCountResponse{Count: count}, nil
 }
+
+// Finding similar posts handler.
+func (h *PostHandler) FindSimilarPosts(ctx context.Context, input *v1.FindSimilarPostsRequest) (*v1.FindSimilarPostsResponse, error) {
+	var v validator
+
+	// Validating request.
+	id := v.id("id", input.Id, true)
+	v.Limit("limit", input.Limit)
+
+	if err := v.Err(); err != nil {
+		return &v1.FindSimilarPostsResponse{}, err
+	}
+
+	// Finding similar posts.
+	posts, err := h.service.FindSimilar(ctx, id, input.Limit)
+	if err != nil {
+		return &v1.FindSimilarPostsResponse{}, err
+	}
+
+	responsePosts := make([]*v1.Post, len(posts))
+
+	for i, post := range posts {
+		responsePosts[i] = &v1.Post{
+			Id:        post.Id.Bytes(),
+			AuthorId:  post.AuthorId.Bytes(),
+			Text:      post.Text,
+			UpdatedAt: timestamp.NewOptional(post.UpdatedAt),
+			Flagged:   post.Flagged,
+			CreatedAt: timestamp.New(post.CreatedAt),
+		}
+	}
+
+	return &v1.FindSimilarPostsResponse{Posts: responsePosts}, nil
+}
+
+// Watching author post events handler.
+func (h *PostHandler) WatchPosts(input *v1.WatchPostsRequest, stream v1.PostService_WatchPostsServer) error {
+	var v validator
+
+	// Validating request.
+	authorIds := v.ids("author_ids", input.AuthorIds)
+	if err := v.Err(); err != nil {
+		return err
+	}
+
+	// Watching post events.
+	return h.event.Watch(stream.Context(), authorIds, input.Cursor, func(event domain.PostEvent) error {
+		post := &v1.Post{
+			Id:       event.Post.Id.Bytes(),
+			AuthorId: event.Post.AuthorId.Bytes(),
+			Text:     event.Post.Text,
+		}
+
+		// Set post update time.
+		if event.Type == domain.EventPostUpdated {
+			post.UpdatedAt = timestamp.New(event.CreatedAt)
+		}
+
+		return stream.Send(&v1.WatchPostsResponse{
+			Cursor: event.Id,
+			Type:   eventpb.Type(event.Type),
+			Post:   post,
+		})
+	})
+}
+
+// Getting ksuid bytes, nil ksuid of an unread field is omitted.
+func idBytes(id ksuid.KSUID) []byte {
+	if id.IsNil() {
+		return nil
+	}
+
+	return id.Bytes()
+}
+
+// Getting creation timestamp, zero time of an unread field is omitted.
+func createdAt(t time.Time) *timestamp.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamp.New(t)
+}
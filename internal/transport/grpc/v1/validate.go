@@ -0,0 +1,111 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package v1
+
+import (
+	"strconv"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/eventpb"
+	"github.com/durudex/durudex-post-service/internal/transport/grpc/validate"
+	v1 "github.com/durudex/durudex-post-service/pkg/pb/durudex/v1"
+
+	"github.com/segmentio/ksuid"
+)
+
+// Maximum number of posts of a page.
+const maxSortLimit = 100
+
+// Request validator structure with the identifier and page parsing of the
+// API version.
+type validator struct{ validate.Validator }
+
+// Validating and parsing a ksuid field. Empty optional fields are parsed as
+// nil ksuid.
+func (v *validator) id(field string, b []byte, required bool) ksuid.KSUID {
+	if len(b) == 0 {
+		if required {
+			v.Violation(field, "Field is required")
+		}
+
+		return ksuid.Nil
+	}
+
+	id, err := ksuid.FromBytes(b)
+	if err != nil || id.IsNil() {
+		v.Violation(field, "Must be a valid ksuid")
+		return ksuid.Nil
+	}
+
+	return id
+}
+
+// Validating and parsing repeated ksuid field.
+func (v *validator) ids(field string, values [][]byte) []ksuid.KSUID {
+	ids := make([]ksuid.KSUID, len(values))
+
+	for i, b := range values {
+		ids[i] = v.id(field+"["+strconv.Itoa(i)+"]", b, true)
+	}
+
+	return ids
+}
+
+// Validating and parsing sort options. Exactly one of first or last must be
+// set in the range from 1 to the maximum page size.
+func (v *validator) sortOptions(field string, o *v1.SortOptions) domain.SortOptions {
+	if o == nil {
+		v.Violation(field+".first", "One of `first` or `last` is required")
+		return domain.SortOptions{}
+	}
+
+	switch {
+	case o.First != nil && o.Last != nil:
+		v.Violation(field+".last", "Mutually exclusive with `first`")
+	case o.First == nil && o.Last == nil:
+		v.Violation(field+".first", "One of `first` or `last` is required")
+	}
+
+	// Check first and last range.
+	v.pageSize(field+".first", o.First)
+	v.pageSize(field+".last", o.Last)
+
+	return domain.SortOptions{
+		First:  o.First,
+		Last:   o.Last,
+		Before: v.id(field+".before", o.Before, false),
+		After:  v.id(field+".after", o.After, false),
+	}
+}
+
+// Validating an optional page size field.
+func (v *validator) pageSize(field string, n *int32) {
+	if n != nil && (*n < 1 || *n > maxSortLimit) {
+		v.Violation(field, "Must be from 1 to "+strconv.Itoa(maxSortLimit))
+	}
+}
+
+// Validating an event type field.
+func (v *validator) eventType(field string, t v1.PostEventType) domain.EventType {
+	e, ok := eventpb.DomainType(t)
+	if !ok {
+		v.Violation(field, "Must be a known post event type")
+	}
+
+	return e
+}
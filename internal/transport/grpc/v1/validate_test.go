@@ -0,0 +1,144 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package v1
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	v1 "github.com/durudex/durudex-post-service/pkg/pb/durudex/v1"
+
+	"github.com/segmentio/ksuid"
+)
+
+// Testing validating sort options.
+func TestValidator_SortOptions(t *testing.T) {
+	first, last, zero, large := int32(10), int32(20), int32(0), int32(maxSortLimit+1)
+
+	// Tests structures.
+	tests := []struct {
+		name   string
+		input  *v1.SortOptions
+		want   domain.SortOptions
+		fields []string
+	}{
+		{
+			name:  "OK",
+			input: &v1.SortOptions{First: &first},
+			want:  domain.SortOptions{First: &first},
+		},
+		{
+			name:   "Nil",
+			input:  nil,
+			fields: []string{"sort_options.first"},
+		},
+		{
+			name:   "Without first and last",
+			input:  &v1.SortOptions{},
+			fields: []string{"sort_options.first"},
+		},
+		{
+			name:   "First and last",
+			input:  &v1.SortOptions{First: &first, Last: &last},
+			fields: []string{"sort_options.last"},
+		},
+		{
+			name:   "Out of range",
+			input:  &v1.SortOptions{First: &zero, Last: &large},
+			fields: []string{"sort_options.last", "sort_options.first", "sort_options.last"},
+		},
+		{
+			name:   "Invalid cursor",
+			input:  &v1.SortOptions{Last: &last, Before: []byte("invalid")},
+			fields: []string{"sort_options.before"},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v validator
+
+			// Validating sort options.
+			got := v.sortOptions("sort_options", tt.input)
+
+			var fields []string
+			for _, violation := range v.Violations {
+				fields = append(fields, violation.Field)
+			}
+
+			// Check for similarity of violated fields.
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("error violated fields: got %v, want %v", fields, tt.fields)
+			}
+
+			// Check for similarity of sort options.
+			if tt.fields == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("error sort options are not similar: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Testing validating ksuid fields.
+func TestValidator_Id(t *testing.T) {
+	id := ksuid.New()
+
+	// Tests structures.
+	tests := []struct {
+		name     string
+		input    []byte
+		required bool
+		want     ksuid.KSUID
+		wantErr  bool
+	}{
+		{name: "OK", input: id.Bytes(), required: true, want: id},
+		{name: "Empty optional", input: nil},
+		{name: "Empty required", input: nil, required: true, wantErr: true},
+		{name: "Malformed", input: []byte("invalid"), wantErr: true},
+		{name: "Nil", input: ksuid.Nil.Bytes(), wantErr: true},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v validator
+
+			// Validating ksuid field.
+			got := v.id("id", tt.input, tt.required)
+
+			err := v.Err()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error validating id: %v", err)
+			}
+
+			// Check error code.
+			var e *domain.Error
+			if tt.wantErr && (!errors.As(err, &e) || e.Code != domain.CodeInvalidArgument) {
+				t.Errorf("error code: %v", err)
+			}
+
+			// Check for similarity of id.
+			if got != tt.want {
+				t.Errorf("error ids are not similar: got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
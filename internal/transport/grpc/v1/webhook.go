@@ -0,0 +1,177 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package v1
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/durudex/dugopb/type/timestamp"
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/eventpb"
+	"github.com/durudex/durudex-post-service/internal/service"
+	v1 "github.com/durudex/durudex-post-service/pkg/pb/durudex/v1"
+)
+
+// Webhook gRPC server handler.
+type WebhookHandler struct {
+	service service.Webhook
+	v1.UnimplementedWebhookServiceServer
+}
+
+// Creating a new webhook gRPC handler.
+func NewWebhookHandler(service service.Webhook) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// Webhook delivery statuses.
+var deliveryStatuses = map[domain.DeliveryStatus]v1.WebhookDeliveryStatus{
+	domain.DeliveryPending:   v1.WebhookDeliveryStatus_WEBHOOK_DELIVERY_STATUS_PENDING,
+	domain.DeliverySucceeded: v1.WebhookDeliveryStatus_WEBHOOK_DELIVERY_STATUS_SUCCEEDED,
+	domain.DeliveryFailed:    v1.WebhookDeliveryStatus_WEBHOOK_DELIVERY_STATUS_FAILED,
+}
+
+// Creating a new webhook handler.
+func (h *WebhookHandler) CreateWebhook(ctx context.Context, input *v1.CreateWebhookRequest) (*v1.CreateWebhookResponse, error) {
+	var v validator
+
+	events := make([]domain.EventType, len(input.Events))
+
+	// Validating request.
+	for i, e := range input.Events {
+		events[i] = v.eventType("events["+strconv.Itoa(i)+"]", e)
+	}
+
+	if err := v.Err(); err != nil {
+		return &v1.CreateWebhookResponse{}, err
+	}
+
+	// Creating a new webhook.
+	webhook, err := h.service.CreateWebhook(ctx, domain.Webhook{
+		URL:    input.Url,
+		Events: events,
+		Secret: input.Secret,
+	})
+	if err != nil {
+		return &v1.CreateWebhookResponse{}, err
+	}
+
+	return &v1.CreateWebhookResponse{Id: webhook.Id.Bytes(), Secret: webhook.Secret}, nil
+}
+
+// Getting all webhooks handler.
+func (h *WebhookHandler) GetWebhooks(ctx context.Context, input *v1.GetWebhooksRequest) (*v1.GetWebhooksResponse, error) {
+	// Getting all webhooks.
+	webhooks, err := h.service.GetWebhooks(ctx)
+	if err != nil {
+		return &v1.GetWebhooksResponse{}, err
+	}
+
+	responseWebhooks := make([]*v1.Webhook, len(webhooks))
+
+	for i, webhook := range webhooks {
+		events := make([]v1.PostEventType, len(webhook.Events))
+
+		for j, e := range webhook.Events {
+			events[j] = eventpb.Type(e)
+		}
+
+		responseWebhooks[i] = &v1.Webhook{
+			Id:        webhook.Id.Bytes(),
+			Url:       webhook.URL,
+			Events:    events,
+			CreatedAt: timestamp.New(webhook.CreatedAt),
+		}
+	}
+
+	return &v1.GetWebhooksResponse{Webhooks: responseWebhooks}, nil
+}
+
+// Deleting a webhook handler.
+func (h *WebhookHandler) DeleteWebhook(ctx context.Context, input *v1.DeleteWebhookRequest) (*v1.DeleteWebhookResponse, error) {
+	var v validator
+
+	// Validating request.
+	id := v.id("id", input.Id, true)
+	if err := v.Err(); err != nil {
+		return &v1.DeleteWebhookResponse{}, err
+	}
+
+	// Deleting a webhook.
+	if err := h.service.DeleteWebhook(ctx, id); err != nil {
+		return &v1.DeleteWebhookResponse{}, err
+	}
+
+	return &v1.DeleteWebhookResponse{}, nil
+}
+
+// Getting webhook deliveries handler.
+func (h *WebhookHandler) GetWebhookDeliveries(ctx context.Context, input *v1.GetWebhookDeliveriesRequest) (*v1.GetWebhookDeliveriesResponse, error) {
+	var v validator
+
+	// Validating request.
+	webhookId := v.id("webhook_id", input.WebhookId, true)
+	v.Limit("limit", input.Limit)
+
+	if err := v.Err(); err != nil {
+		return &v1.GetWebhookDeliveriesResponse{}, err
+	}
+
+	// Getting webhook deliveries.
+	deliveries, err := h.service.GetDeliveries(ctx, webhookId, input.Limit)
+	if err != nil {
+		return &v1.GetWebhookDeliveriesResponse{}, err
+	}
+
+	responseDeliveries := make([]*v1.WebhookDelivery, len(deliveries))
+
+	for i, d := range deliveries {
+		responseDeliveries[i] = &v1.WebhookDelivery{
+			Id:           d.Id.Bytes(),
+			WebhookId:    d.WebhookId.Bytes(),
+			EventId:      d.EventId,
+			EventType:    eventpb.Type(d.EventType),
+			Status:       deliveryStatuses[d.Status],
+			Attempts:     d.Attempts,
+			ResponseCode: d.ResponseCode,
+			Error:        d.Error,
+			CreatedAt:    timestamp.New(d.CreatedAt),
+			UpdatedAt:    timestamp.NewOptional(d.UpdatedAt),
+		}
+	}
+
+	return &v1.GetWebhookDeliveriesResponse{Deliveries: responseDeliveries}, nil
+}
+
+// Replaying a webhook delivery handler.
+func (h *WebhookHandler) ReplayWebhookDelivery(ctx context.Context, input *v1.ReplayWebhookDeliveryRequest) (*v1.ReplayWebhookDeliveryResponse, error) {
+	var v validator
+
+	// Validating request.
+	id := v.id("id", input.Id, true)
+	if err := v.Err(); err != nil {
+		return &v1.ReplayWebhookDeliveryResponse{}, err
+	}
+
+	// Replaying a webhook delivery.
+	if err := h.service.ReplayDelivery(ctx, id); err != nil {
+		return &v1.ReplayWebhookDeliveryResponse{}, err
+	}
+
+	return &v1.ReplayWebhookDeliveryResponse{}, nil
+}
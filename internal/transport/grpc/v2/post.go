@@ -0,0 +1,252 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package v2
+
+import (
+	"context"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/service"
+	"github.com/durudex/durudex-post-service/internal/transport/grpc/validate"
+	v2 "github.com/durudex/durudex-post-service/pkg/pb/durudex/v2"
+
+	"github.com/durudex/dugopb/type/timestamp"
+)
+
+// Fields of a read post.
+var postFields = domain.FieldMask{domain.PostFieldAuthorId, domain.PostFieldText, domain.PostFieldFlagged,
+	domain.PostFieldCreatedAt, domain.PostFieldUpdatedAt}
+
+// Post gRPC server handler.
+type PostHandler struct {
+	service service.Post
+	v2.UnimplementedPostServiceServer
+}
+
+// Creating a new post gRPC handler.
+func NewPostHandler(service service.Post) *PostHandler {
+	return &PostHandler{service: service}
+}
+
+// Creating a new post handler.
+func (h *PostHandler) CreatePost(ctx context.Context, input *v2.CreatePostRequest) (*v2.CreatePostResponse, error) {
+	var v validator
+
+	// Validating request.
+	authorId := v.id("author_id", input.AuthorId, false)
+	if err := v.Err(); err != nil {
+		return &v2.CreatePostResponse{}, err
+	}
+
+	// Create a new post.
+	id, err := h.service.Create(ctx, domain.Post{AuthorId: authorId, Text: input.Text})
+	if err != nil {
+		return &v2.CreatePostResponse{}, err
+	}
+
+	return &v2.CreatePostResponse{Id: id.String()}, nil
+}
+
+// Getting a post handler.
+func (h *PostHandler) GetPost(ctx context.Context, input *v2.GetPostRequest) (*v2.GetPostResponse, error) {
+	var v validator
+
+	// Validating request.
+	id := v.id("id", input.Id, true)
+	mask := v.FieldMask("read_mask", input.ReadMask, domain.PostFieldText, domain.PostFieldFlagged,
+		domain.PostFieldCreatedAt, domain.PostFieldUpdatedAt)
+
+	if err := v.Err(); err != nil {
+		return &v2.GetPostResponse{}, err
+	}
+
+	// Post author and creation time are always read.
+	if mask == nil {
+		mask = postFields
+	} else {
+		mask = withFields(mask, domain.PostFieldAuthorId, domain.PostFieldCreatedAt)
+	}
+
+	// Getting post by id.
+	post, err := h.service.Get(ctx, id, mask)
+	if err != nil {
+		return &v2.GetPostResponse{}, err
+	}
+
+	post.Id = id
+
+	return &v2.GetPostResponse{Post: newPost(post)}, nil
+}
+
+// Listing author posts handler.
+func (h *PostHandler) ListPosts(ctx context.Context, input *v2.ListPostsRequest) (*v2.ListPostsResponse, error) {
+	var v validator
+
+	// Validating request.
+	authorId := v.id("author_id", input.AuthorId, true)
+	size := v.pageSize("page_size", input.PageSize)
+	before := v.pageToken("page_token", input.PageToken)
+	mask := v.FieldMask("read_mask", input.ReadMask, domain.PostFieldText, domain.PostFieldFlagged,
+		domain.PostFieldCreatedAt, domain.PostFieldUpdatedAt)
+
+	if err := v.Err(); err != nil {
+		return &v2.ListPostsResponse{}, err
+	}
+
+	// Post id is always read for the page token, and creation time with it.
+	if mask != nil {
+		mask = withFields(mask, domain.PostFieldId, domain.PostFieldCreatedAt)
+	}
+
+	// Getting one extra post to check is there a next page.
+	last := size + 1
+
+	// Getting author posts, newest first.
+	posts, err := h.service.GetPosts(ctx, authorId, domain.SortOptions{Last: &last, Before: before},
+		validate.PostFilter(input.CreatedAfter, input.CreatedBefore), mask)
+	if err != nil {
+		return &v2.ListPostsResponse{}, err
+	}
+
+	response := &v2.ListPostsResponse{}
+
+	// Check is there a next page.
+	if len(posts) > int(size) {
+		posts = posts[:size]
+		response.NextPageToken = pageToken(posts[size-1].Id)
+	}
+
+	response.Posts = make([]*v2.Post, len(posts))
+
+	for i, post := range posts {
+		post.AuthorId = authorId
+		response.Posts[i] = newPost(post)
+	}
+
+	return response, nil
+}
+
+// Updating a post handler.
+func (h *PostHandler) UpdatePost(ctx context.Context, input *v2.UpdatePostRequest) (*v2.UpdatePostResponse, error) {
+	var v validator
+
+	// Validating request.
+	id := v.id("id", input.Id, true)
+	authorId := v.id("author_id", input.AuthorId, false)
+	mask := v.FieldMask("update_mask", input.UpdateMask, domain.PostFieldText)
+
+	if err := v.Err(); err != nil {
+		return &v2.UpdatePostResponse{}, err
+	}
+
+	// Updating post.
+	if err := h.service.Update(ctx, domain.Post{Id: id, AuthorId: authorId, Text: input.Text}, mask); err != nil {
+		return &v2.UpdatePostResponse{}, err
+	}
+
+	return &v2.UpdatePostResponse{}, nil
+}
+
+// Deleting a post handler.
+func (h *PostHandler) DeletePost(ctx context.Context, input *v2.DeletePostRequest) (*v2.DeletePostResponse, error) {
+	var v validator
+
+	// Validating request.
+	id := v.id("id", input.Id, true)
+	authorId := v.id("author_id", input.AuthorId, false)
+
+	if err := v.Err(); err != nil {
+		return &v2.DeletePostResponse{}, err
+	}
+
+	// Deleting post.
+	if err := h.service.Delete(ctx, id, authorId); err != nil {
+		return &v2.DeletePostResponse{}, err
+	}
+
+	return &v2.DeletePostResponse{}, nil
+}
+
+// Getting total posts count handler.
+func (h *PostHandler) GetTotalPostsCount(ctx context.Context, input *v2.GetTotalPostsCountRequest) (*v2.GetTotalPostsCountResponse, error) {
+	var v validator
+
+	// Validating request.
+	authorId := v.id("author_id", input.AuthorId, true)
+	if err := v.Err(); err != nil {
+		return &v2.GetTotalPostsCountResponse{}, err
+	}
+
+	// Getting total posts count.
+	count, err := h.service.GetTotalCount(ctx, authorId, validate.PostFilter(input.CreatedAfter, input.CreatedBefore))
+	if err != nil {
+		return &v2.GetTotalPostsCountResponse{}, err
+	}
+
+	return &v2.GetTotalPostsCountResponse{Count: count}, nil
+}
+
+// Finding similar posts handler.
+func (h *PostHandler) FindSimilarPosts(ctx context.Context, input *v2.FindSimilarPostsRequest) (*v2.FindSimilarPostsResponse, error) {
+	var v validator
+
+	// Validating request.
+	id := v.id("id", input.Id, true)
+	v.Limit("limit", input.Limit)
+
+	if err := v.Err(); err != nil {
+		return &v2.FindSimilarPostsResponse{}, err
+	}
+
+	// Finding similar posts.
+	posts, err := h.service.FindSimilar(ctx, id, input.Limit)
+	if err != nil {
+		return &v2.FindSimilarPostsResponse{}, err
+	}
+
+	response := &v2.FindSimilarPostsResponse{Posts: make([]*v2.Post, len(posts))}
+
+	for i, post := range posts {
+		response.Posts[i] = newPost(post)
+	}
+
+	return response, nil
+}
+
+// Creating a new post message.
+func newPost(post domain.Post) *v2.Post {
+	return &v2.Post{
+		Id:        post.Id.String(),
+		AuthorId:  post.AuthorId.String(),
+		Text:      post.Text,
+		CreatedAt: timestamp.New(post.CreatedAt),
+		UpdatedAt: timestamp.NewOptional(post.UpdatedAt),
+		Flagged:   post.Flagged,
+	}
+}
+
+// Adding the fields missing in the mask.
+func withFields(mask domain.FieldMask, fields ...string) domain.FieldMask {
+	for _, field := range fields {
+		if !validate.Contains(mask, field) {
+			mask = append(mask, field)
+		}
+	}
+
+	return mask
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package v2_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/service"
+	v2 "github.com/durudex/durudex-post-service/internal/transport/grpc/v2"
+	pb "github.com/durudex/durudex-post-service/pkg/pb/durudex/v2"
+
+	"github.com/segmentio/ksuid"
+)
+
+// Post service with more author posts than any page.
+type pagePost struct{ service.Post }
+
+// Getting author posts.
+func (pagePost) GetPosts(_ context.Context, _ ksuid.KSUID, sort domain.SortOptions, _ domain.PostFilter, _ domain.FieldMask) ([]domain.Post, error) {
+	posts := make([]domain.Post, *sort.Last)
+
+	for i := range posts {
+		posts[i].Id = ksuid.New()
+	}
+
+	return posts, nil
+}
+
+// Testing listing author posts with page sizes.
+func TestPostHandler_ListPosts(t *testing.T) {
+	// Tests structures.
+	tests := []struct {
+		name     string
+		pageSize int32
+		want     int
+	}{
+		{name: "OK", pageSize: 10, want: 10},
+		{name: "Default", pageSize: 0, want: 20},
+		{name: "Too large", pageSize: 1000, want: 100},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Listing author posts.
+			got, err := v2.NewPostHandler(pagePost{}).ListPosts(context.Background(), &pb.ListPostsRequest{
+				AuthorId: ksuid.New().String(),
+				PageSize: tt.pageSize,
+			})
+			if err != nil {
+				t.Fatalf("error listing posts: %s", err.Error())
+			}
+
+			// Check for page size and next page token.
+			if len(got.Posts) != tt.want || got.NextPageToken == "" {
+				t.Errorf("error page: got %d posts, want %d", len(got.Posts), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package v2
+
+import (
+	"encoding/base64"
+
+	"github.com/durudex/durudex-post-service/internal/transport/grpc/validate"
+
+	"github.com/segmentio/ksuid"
+)
+
+const (
+	// Default number of posts of a page.
+	defaultPageSize = 20
+	// Maximum number of posts of a page.
+	maxPageSize = 100
+)
+
+// Request validator structure with the identifier and page parsing of the
+// API version.
+type validator struct{ validate.Validator }
+
+// Validating and parsing a ksuid string field. Empty optional fields are
+// parsed as nil ksuid.
+func (v *validator) id(field, s string, required bool) ksuid.KSUID {
+	if s == "" {
+		if required {
+			v.Violation(field, "Field is required")
+		}
+
+		return ksuid.Nil
+	}
+
+	id, err := ksuid.Parse(s)
+	if err != nil || id.IsNil() {
+		v.Violation(field, "Must be a valid ksuid")
+		return ksuid.Nil
+	}
+
+	return id
+}
+
+// Validating and parsing a page size field. Unset page size is parsed as the
+// default page size and larger page sizes are coerced to the maximum, as AIP-158
+// recommends.
+func (v *validator) pageSize(field string, n int32) int32 {
+	switch {
+	case n < 0:
+		v.Violation(field, "Must be non-negative")
+	case n == 0:
+		return defaultPageSize
+	case n > maxPageSize:
+		return maxPageSize
+	}
+
+	return n
+}
+
+// Validating and parsing a page token field. Empty page token is parsed as
+// nil ksuid.
+func (v *validator) pageToken(field, token string) ksuid.KSUID {
+	if token == "" {
+		return ksuid.Nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		v.Violation(field, "Must be a page token of the previous response")
+		return ksuid.Nil
+	}
+
+	id, err := ksuid.FromBytes(b)
+	if err != nil || id.IsNil() {
+		v.Violation(field, "Must be a page token of the previous response")
+		return ksuid.Nil
+	}
+
+	return id
+}
+
+// Getting page token of the post to continue after.
+func pageToken(id ksuid.KSUID) string {
+	return base64.RawURLEncoding.EncodeToString(id.Bytes())
+}
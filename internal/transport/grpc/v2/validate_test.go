@@ -0,0 +1,150 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package v2
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/segmentio/ksuid"
+)
+
+// Testing validating page sizes.
+func TestValidator_PageSize(t *testing.T) {
+	// Tests structures.
+	tests := []struct {
+		name   string
+		input  int32
+		want   int32
+		fields []string
+	}{
+		{name: "OK", input: 10, want: 10},
+		{name: "Default", input: 0, want: defaultPageSize},
+		{name: "Too large", input: maxPageSize + 1, want: maxPageSize},
+		{name: "Negative", input: -1, want: -1, fields: []string{"page_size"}},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v validator
+
+			// Validating page size.
+			got := v.pageSize("page_size", tt.input)
+
+			// Check for similarity of violated fields.
+			if !reflect.DeepEqual(fields(v), tt.fields) {
+				t.Errorf("error violated fields: got %v, want %v", fields(v), tt.fields)
+			}
+
+			// Check for similarity of page size.
+			if got != tt.want {
+				t.Errorf("error page sizes are not similar: got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// Testing validating page tokens.
+func TestValidator_PageToken(t *testing.T) {
+	id := ksuid.New()
+
+	// Tests structures.
+	tests := []struct {
+		name   string
+		input  string
+		want   ksuid.KSUID
+		fields []string
+	}{
+		{name: "OK", input: pageToken(id), want: id},
+		{name: "Empty", input: "", want: ksuid.Nil},
+		{name: "Not base64", input: "!", want: ksuid.Nil, fields: []string{"page_token"}},
+		{name: "Not ksuid", input: "AAAA", want: ksuid.Nil, fields: []string{"page_token"}},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v validator
+
+			// Validating page token.
+			got := v.pageToken("page_token", tt.input)
+
+			// Check for similarity of violated fields.
+			if !reflect.DeepEqual(fields(v), tt.fields) {
+				t.Errorf("error violated fields: got %v, want %v", fields(v), tt.fields)
+			}
+
+			// Check for similarity of page token id.
+			if got != tt.want {
+				t.Errorf("error ids are not similar: got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// Testing validating ksuid strings.
+func TestValidator_Id(t *testing.T) {
+	id := ksuid.New()
+
+	// Tests structures.
+	tests := []struct {
+		name     string
+		input    string
+		required bool
+		want     ksuid.KSUID
+		fields   []string
+	}{
+		{name: "OK", input: id.String(), required: true, want: id},
+		{name: "Optional", input: "", want: ksuid.Nil},
+		{name: "Required", input: "", required: true, want: ksuid.Nil, fields: []string{"id"}},
+		{name: "Invalid", input: "post", want: ksuid.Nil, fields: []string{"id"}},
+		{name: "Nil", input: ksuid.Nil.String(), want: ksuid.Nil, fields: []string{"id"}},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v validator
+
+			// Validating id.
+			got := v.id("id", tt.input, tt.required)
+
+			// Check for similarity of violated fields.
+			if !reflect.DeepEqual(fields(v), tt.fields) {
+				t.Errorf("error violated fields: got %v, want %v", fields(v), tt.fields)
+			}
+
+			// Check for similarity of id.
+			if got != tt.want {
+				t.Errorf("error ids are not similar: got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// Getting violated fields of the validator.
+func fields(v validator) []string {
+	var fields []string
+
+	for _, violation := range v.Violations {
+		fields = append(fields, violation.Field)
+	}
+
+	return fields
+}
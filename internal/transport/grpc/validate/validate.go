@@ -0,0 +1,111 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package validate provides the request validator shared by gRPC API versions.
+package validate
+
+import (
+	"strconv"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+
+	"github.com/durudex/dugopb/type/timestamp"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// Request validator structure.
+//
+// Collects field violations of a request, so that all of them are returned
+// at once before any service code runs. API versions embed the validator and
+// add their own identifier and page parsing.
+type Validator struct{ Violations []domain.FieldViolation }
+
+// Adding a field violation.
+func (v *Validator) Violation(field, description string) {
+	v.Violations = append(v.Violations, domain.FieldViolation{Field: field, Description: description})
+}
+
+// Validating a non-negative limit field.
+func (v *Validator) Limit(field string, n int32) {
+	if n < 0 {
+		v.Violation(field, "Must be non-negative")
+	}
+}
+
+// Validating and parsing a field mask of the allowed paths. Empty masks are
+// parsed as nil.
+func (v *Validator) FieldMask(field string, mask *fieldmaskpb.FieldMask, allowed ...string) domain.FieldMask {
+	if len(mask.GetPaths()) == 0 {
+		return nil
+	}
+
+	paths := make(domain.FieldMask, 0, len(mask.Paths))
+
+	for i, path := range mask.Paths {
+		if !Contains(allowed, path) {
+			v.Violation(field+".paths["+strconv.Itoa(i)+"]", "Unknown field path: "+path)
+			continue
+		}
+
+		// Skipping duplicate paths.
+		if !Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
+
+// Getting validation error of the collected field violations.
+func (v *Validator) Err() error {
+	if len(v.Violations) == 0 {
+		return nil
+	}
+
+	return &domain.Error{
+		Code:       domain.CodeInvalidArgument,
+		Message:    "Invalid request",
+		Reason:     domain.ReasonInvalidArgument,
+		Violations: v.Violations,
+	}
+}
+
+// Checking if the values contain the value.
+func Contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Creating a new author posts filter of the optional creation timestamps.
+func PostFilter(after, before *timestamp.Timestamp) domain.PostFilter {
+	var filter domain.PostFilter
+
+	if after != nil {
+		filter.CreatedAfter = after.AsTime()
+	}
+
+	if before != nil {
+		filter.CreatedBefore = before.AsTime()
+	}
+
+	return filter
+}
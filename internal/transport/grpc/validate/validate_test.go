@@ -0,0 +1,101 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// Testing validating field masks.
+func TestValidator_FieldMask(t *testing.T) {
+	// Tests structures.
+	tests := []struct {
+		name   string
+		paths  []string
+		want   domain.FieldMask
+		fields []string
+	}{
+		{name: "Empty"},
+		{
+			name:  "OK",
+			paths: []string{"text", "updated_at", "text"},
+			want:  domain.FieldMask{"text", "updated_at"},
+		},
+		{
+			name:   "Unknown path",
+			paths:  []string{"text", "hash"},
+			want:   domain.FieldMask{"text"},
+			fields: []string{"read_mask.paths[1]"},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Validator
+
+			// Validating field mask.
+			got := v.FieldMask("read_mask", &fieldmaskpb.FieldMask{Paths: tt.paths}, "text", "updated_at")
+
+			var fields []string
+			for _, violation := range v.Violations {
+				fields = append(fields, violation.Field)
+			}
+
+			// Check for similarity of violated fields.
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("error violated fields: got %v, want %v", fields, tt.fields)
+			}
+
+			// Check for similarity of field mask.
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("error field masks are not similar: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Testing getting validation error.
+func TestValidator_Err(t *testing.T) {
+	var v Validator
+
+	// Check for nil error without violations.
+	if err := v.Err(); err != nil {
+		t.Fatalf("error getting validation error: %s", err.Error())
+	}
+
+	v.Limit("limit", -1)
+
+	// Check for invalid argument error of the violations.
+	var e *domain.Error
+	if !errors.As(v.Err(), &e) || e.Code != domain.CodeInvalidArgument {
+		t.Fatalf("error validation error: got %v, want code %d", v.Err(), domain.CodeInvalidArgument)
+	}
+
+	// Check for similarity of violations.
+	want := []domain.FieldViolation{{Field: "limit", Description: "Must be non-negative"}}
+	if !reflect.DeepEqual(e.Violations, want) {
+		t.Errorf("error violations are not similar: got %v, want %v", e.Violations, want)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/transport/grpc"
+	v1 "github.com/durudex/durudex-post-service/internal/transport/http/v1"
+	"github.com/durudex/durudex-post-service/pkg/requestid"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog/log"
+)
+
+// HTTP access log middleware. Adds the incoming or a new request id and the
+// request logger to the request context.
+func logRequests(access *grpc.AccessLogger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			id := requestid.FromIncoming(r.Header.Get(requestid.Key))
+			logger := log.With().Str("request_id", id).Logger()
+			ctx := logger.WithContext(requestid.NewContext(r.Context(), id))
+
+			// Sending request id in the response header.
+			w.Header().Set(requestid.Key, id)
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			// Call the handler.
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			access.LogRequest(ctx, routeMethod(r), r.RemoteAddr, start, v1.StatusCode(status), nil)
+		})
+	}
+}
+
+// Getting the request method and route pattern, such as "GET /v1/posts/{id}",
+// so that metrics are not labeled by ids. Requests rejected before routing are
+// matched against the routes.
+func routeMethod(r *http.Request) string {
+	pattern := "unmatched"
+
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if rctx.RoutePattern() != "" {
+			pattern = rctx.RoutePattern()
+		} else if match := chi.NewRouteContext(); rctx.Routes != nil && rctx.Routes.Match(match, r.Method, r.URL.Path) {
+			pattern = match.RoutePattern()
+		}
+	}
+
+	return r.Method + " " + pattern
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package http
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/durudex/durudex-post-service/internal/auth"
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/service"
+	"github.com/durudex/durudex-post-service/internal/transport/graphql"
+	"github.com/durudex/durudex-post-service/internal/transport/grpc"
+	v1 "github.com/durudex/durudex-post-service/internal/transport/http/v1"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// OpenAPI document of the HTTP API.
+//
+//go:embed openapi.json
+var openapi []byte
+
+// HTTP server handler structure.
+type Handler struct {
+	service       *service.Service
+	graphql       config.GraphQLConfig
+	authenticator *auth.Authenticator
+	reporter      grpc.PanicReporter
+}
+
+// Creating a new HTTP handler. Access tokens are not authenticated when the
+// authenticator is nil, and recovered panics are reported with the gRPC panic
+// reporter.
+func NewHandler(service *service.Service, graphql config.GraphQLConfig, authenticator *auth.Authenticator,
+	reporter grpc.PanicReporter) *Handler {
+	return &Handler{service: service, graphql: graphql, authenticator: authenticator, reporter: reporter}
+}
+
+// Registering HTTP version handlers.
+//
+// Requests are logged and observed outside of panic recovery and
+// authentication, so recovered and rejected requests are counted.
+func (h *Handler) RegisterHandlers(r chi.Router, accessLog config.AccessLogConfig) {
+	r.Use(logRequests(grpc.NewAccessLogger("HTTP", accessLog)), (&recovery{reporter: h.reporter}).middleware)
+
+	// Authenticating request access tokens.
+	if h.authenticator != nil {
+		r.Use(auth.Middleware(h.authenticator))
+	}
+
+	r.Get("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if _, err := w.Write(openapi); err != nil {
+			log.Error().Err(err).Msg("error writing OpenAPI document")
+		}
+	})
+
+	r.Route("/v1", v1.NewHandler(h.service).RegisterHandlers)
+
+	// Registering GraphQL subgraph endpoint.
+	if h.graphql.Enable {
+		r.Handle("/graphql", graphql.NewHandler(h.service, h.graphql))
+	}
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/internal/auth"
+	"github.com/durudex/durudex-post-service/internal/config"
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/metrics"
+	"github.com/durudex/durudex-post-service/internal/service"
+	"github.com/durudex/durudex-post-service/pkg/requestid"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/segmentio/ksuid"
+	"google.golang.org/grpc/codes"
+)
+
+// Post service panicking on getting a post.
+type panickingPost struct{ service.Post }
+
+// Getting a post.
+func (panickingPost) Get(context.Context, ksuid.KSUID, domain.FieldMask) (domain.Post, error) {
+	panic("http panic")
+}
+
+// Panic reporter recorder structure.
+type panicRecorder struct {
+	mu      sync.Mutex
+	methods []string
+}
+
+// Recording a recovered handler panic.
+func (r *panicRecorder) ReportPanic(_ context.Context, method string, recovered interface{}, _ []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.methods = append(r.methods, method)
+}
+
+// Testing logging, observing and recovering HTTP requests.
+func TestHandler_Middleware(t *testing.T) {
+	const method = "GET /v1/posts/{id}"
+
+	authenticator, err := auth.NewAuthenticator(config.AuthConfig{Enable: true, Audience: "durudex-post-service", Secret: "secret"})
+	if err != nil {
+		t.Fatalf("error creating authenticator: %s", err.Error())
+	}
+
+	// Tests structures.
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantCode      codes.Code
+		wantPanic     bool
+	}{
+		{name: "Panic", wantStatus: http.StatusInternalServerError, wantCode: codes.Internal, wantPanic: true},
+		{
+			name:          "Unauthenticated",
+			authorization: "Bearer invalid",
+			wantStatus:    http.StatusUnauthorized,
+			wantCode:      codes.Unauthenticated,
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter := &panicRecorder{}
+
+			router := chi.NewRouter()
+			NewHandler(&service.Service{Post: panickingPost{}}, config.GraphQLConfig{}, authenticator, reporter).
+				RegisterHandlers(router, config.AccessLogConfig{})
+
+			panics := metrics.PanicsTotal.WithLabelValues(method)
+			requests := metrics.RequestsTotal.WithLabelValues(method, tt.wantCode.String())
+			panicsBefore, requestsBefore := testutil.ToFloat64(panics), testutil.ToFloat64(requests)
+
+			r := httptest.NewRequest(http.MethodGet, "/v1/posts/"+ksuid.New().String(), nil)
+			r.Header.Set(requestid.Key, "test-request")
+
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, r)
+
+			// Check for response status and request id.
+			if w.Code != tt.wantStatus || w.Header().Get(requestid.Key) != "test-request" {
+				t.Errorf("error response: status %d, request id %q", w.Code, w.Header().Get(requestid.Key))
+			}
+
+			// Check for number of counted requests.
+			if got := testutil.ToFloat64(requests) - requestsBefore; got != 1 {
+				t.Errorf("error counted requests: got %v, want 1", got)
+			}
+
+			if !tt.wantPanic {
+				return
+			}
+
+			// Check for counted and reported panic.
+			if got := testutil.ToFloat64(panics) - panicsBefore; got != 1 {
+				t.Errorf("error counted panics: got %v, want 1", got)
+			}
+
+			if len(reporter.methods) != 1 || reporter.methods[0] != method {
+				t.Errorf("error reported panics: %v", reporter.methods)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package http
+
+import (
+	"net/http"
+
+	"github.com/durudex/durudex-post-service/internal/transport/grpc"
+
+	"github.com/rs/zerolog/log"
+)
+
+// HTTP panic recovery structure.
+type recovery struct{ reporter grpc.PanicReporter }
+
+// HTTP panic recovery middleware. A recovered panic is answered with an
+// internal error.
+func (rc *recovery) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+
+			// Aborting the response without recovery.
+			if p == http.ErrAbortHandler {
+				panic(p)
+			}
+
+			grpc.RecoverPanic(r.Context(), rc.reporter, routeMethod(r), p)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+
+			if _, err := w.Write([]byte(`{"code":500,"message":"Internal Server Error"}`)); err != nil {
+				log.Error().Err(err).Msg("error writing response")
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/config"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// HTTP server structure.
+type Server struct {
+	server  *http.Server
+	config  config.HTTPConfig
+	handler *Handler
+}
+
+// Creating a new HTTP server.
+func NewServer(cfg config.HTTPConfig, handler *Handler) *Server {
+	router := chi.NewRouter()
+
+	// Registering HTTP handlers.
+	handler.RegisterHandlers(router, cfg.AccessLog)
+
+	return &Server{
+		server: &http.Server{
+			Addr:              cfg.Host + ":" + cfg.Port,
+			Handler:           router,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+		config:  cfg,
+		handler: handler,
+	}
+}
+
+// Running HTTP server.
+func (s *Server) Run() {
+	log.Info().Msg("Running HTTP server...")
+
+	// Running HTTP server.
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal().Err(err).Msg("error running HTTP server")
+	}
+}
+
+// Stopping HTTP server.
+//
+// In-flight requests are drained until the context is done, after which open
+// connections are closed.
+func (s *Server) Stop(ctx context.Context) error {
+	log.Info().Msg("Stopping HTTP server...")
+
+	if err := s.server.Shutdown(ctx); err != nil {
+		s.server.Close()
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package v1
+
+import (
+	"github.com/durudex/durudex-post-service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HTTP handler structure.
+type Handler struct{ service *service.Service }
+
+// Creating a new HTTP handler.
+func NewHandler(service *service.Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Registering HTTP handlers.
+func (h *Handler) RegisterHandlers(r chi.Router) {
+	NewPostHandler(h.service.Post, h.service.Event).Register(r)
+}
@@ -0,0 +1,336 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/segmentio/ksuid"
+)
+
+// Post HTTP handler.
+type PostHandler struct {
+	service service.Post
+	event   service.Event
+}
+
+// Creating a new post HTTP handler.
+func NewPostHandler(service service.Post, event service.Event) *PostHandler {
+	return &PostHandler{service: service, event: event}
+}
+
+// Registering post routes. Finding similar posts is a moderator RPC and is
+// not served over HTTP.
+func (h *PostHandler) Register(r chi.Router) {
+	r.Post("/posts", h.CreatePost)
+	r.Get("/posts/events", h.WatchPosts)
+	r.Get("/posts/{id}", h.GetPost)
+	r.Patch("/posts/{id}", h.UpdatePost)
+	r.Delete("/posts/{id}", h.DeletePost)
+	r.Get("/authors/{authorId}/posts", h.GetPosts)
+	r.Get("/authors/{authorId}/posts/count", h.GetTotalPostsCount)
+}
+
+// Create post request structure. The author id is optional with authentication.
+type createPostRequest struct {
+	AuthorId string `json:"author_id"`
+	Text     string `json:"text"`
+}
+
+// Creating a new post handler.
+func (h *PostHandler) CreatePost(w http.ResponseWriter, r *http.Request) {
+	var input createPostRequest
+
+	// Decoding request body.
+	if err := decode(r, &input); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	authorId, err := parseOptionalId(input.AuthorId, "author_id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Create a new post.
+	id, err := h.service.Create(r.Context(), domain.Post{AuthorId: authorId, Text: input.Text})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]string{"id": id.String()})
+}
+
+// Getting a post handler.
+func (h *PostHandler) GetPost(w http.ResponseWriter, r *http.Request) {
+	id, err := parseId(chi.URLParam(r, "id"), "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Getting post by id.
+	post, err := h.service.Get(r.Context(), id, nil)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	post.Id = id
+
+	writeJSON(w, http.StatusOK, newPost(post))
+}
+
+// Getting author posts handler.
+func (h *PostHandler) GetPosts(w http.ResponseWriter, r *http.Request) {
+	var (
+		sort domain.SortOptions
+		err  error
+	)
+
+	authorId, err := parseId(chi.URLParam(r, "authorId"), "author_id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Parsing sort options.
+	if sort.First, err = parsePageSize(r, "first"); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if sort.Last, err = parsePageSize(r, "last"); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if sort.First != nil && sort.Last != nil {
+		writeError(w, &domain.Error{
+			Code:       domain.CodeInvalidArgument,
+			Message:    "Invalid last",
+			Reason:     domain.ReasonInvalidArgument,
+			Violations: []domain.FieldViolation{{Field: "last", Description: "Mutually exclusive with `first`"}},
+		})
+		return
+	}
+
+	if before := r.URL.Query().Get("before"); before != "" {
+		if sort.Before, err = parseId(before, "before"); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	if after := r.URL.Query().Get("after"); after != "" {
+		if sort.After, err = parseId(after, "after"); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	// Parsing creation time filter.
+	filter, err := parsePostFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Getting author posts.
+	posts, err := h.service.GetPosts(r.Context(), authorId, sort, filter, nil)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string][]Post{"posts": newPosts(posts)})
+}
+
+// Deleting a post handler.
+func (h *PostHandler) DeletePost(w http.ResponseWriter, r *http.Request) {
+	id, err := parseId(chi.URLParam(r, "id"), "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	authorId, err := parseOptionalId(r.URL.Query().Get("author_id"), "author_id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Deleting post.
+	if err := h.service.Delete(r.Context(), id, authorId); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Update post request structure. The author id is optional with authentication.
+type updatePostRequest struct {
+	AuthorId string `json:"author_id"`
+	Text     string `json:"text"`
+}
+
+// Updating a post handler.
+func (h *PostHandler) UpdatePost(w http.ResponseWriter, r *http.Request) {
+	var input updatePostRequest
+
+	id, err := parseId(chi.URLParam(r, "id"), "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Decoding request body.
+	if err := decode(r, &input); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	authorId, err := parseOptionalId(input.AuthorId, "author_id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Updating post.
+	if err := h.service.Update(r.Context(), domain.Post{Id: id, AuthorId: authorId, Text: input.Text}, nil); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Getting total author posts count handler.
+func (h *PostHandler) GetTotalPostsCount(w http.ResponseWriter, r *http.Request) {
+	authorId, err := parseId(chi.URLParam(r, "authorId"), "author_id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Parsing creation time filter.
+	filter, err := parsePostFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	count, err := h.service.GetTotalCount(r.Context(), authorId, filter)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]int32{"count": count})
+}
+
+// Watching author post events handler.
+//
+// Events are streamed as server-sent events with the event cursor as the event
+// id, so a reconnecting client resumes after the Last-Event-ID header. The stream
+// is opened before watching, so errors of the watch are sent as error events.
+func (h *PostHandler) WatchPosts(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, fmt.Errorf("streaming is not supported"))
+		return
+	}
+
+	var authorIds []ksuid.KSUID
+
+	// Parsing author ids.
+	for _, value := range r.URL.Query()["author_id"] {
+		for _, s := range strings.Split(value, ",") {
+			id, err := parseId(s, "author_id")
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+
+			authorIds = append(authorIds, id)
+		}
+	}
+
+	var cursor *int64
+
+	// Parsing event cursor.
+	if value := r.Header.Get("Last-Event-ID"); value != "" || r.URL.Query().Get("cursor") != "" {
+		if value == "" {
+			value = r.URL.Query().Get("cursor")
+		}
+
+		c, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			writeError(w, invalidArgument("cursor"))
+			return
+		}
+
+		cursor = &c
+	}
+
+	if len(authorIds) == 0 {
+		writeError(w, invalidArgument("author_id"))
+		return
+	}
+
+	// Opening the event stream.
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// Watching post events.
+	err := h.event.Watch(r.Context(), authorIds, cursor, func(event domain.PostEvent) error {
+		data, err := json.Marshal(newPost(event.Post))
+		if err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Id, event.Type, data); err != nil {
+			return err
+		}
+
+		flusher.Flush()
+
+		return nil
+	})
+	if err != nil && r.Context().Err() == nil {
+		if err := writeErrorEvent(w, err); err != nil {
+			log.Error().Err(err).Msg("error writing error event")
+		}
+
+		flusher.Flush()
+	}
+}
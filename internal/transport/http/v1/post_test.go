@@ -0,0 +1,257 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package v1_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/service"
+	v1 "github.com/durudex/durudex-post-service/internal/transport/http/v1"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/segmentio/ksuid"
+)
+
+// Post service recording author posts sort options.
+type sortPost struct {
+	service.Post
+	sort *domain.SortOptions
+}
+
+// Getting author posts.
+func (p *sortPost) GetPosts(_ context.Context, _ ksuid.KSUID, sort domain.SortOptions, _ domain.PostFilter, _ domain.FieldMask) ([]domain.Post, error) {
+	*p.sort = sort
+	return nil, nil
+}
+
+// Event service with a watch function.
+type watchEvent struct {
+	service.Event
+	watch func(cursor *int64, send func(domain.PostEvent) error) error
+}
+
+// Watching author post events.
+func (e *watchEvent) Watch(_ context.Context, _ []ksuid.KSUID, cursor *int64, send func(domain.PostEvent) error) error {
+	return e.watch(cursor, send)
+}
+
+// Creating a new post HTTP router.
+func newRouter(post service.Post, event service.Event) http.Handler {
+	router := chi.NewRouter()
+	v1.NewPostHandler(post, event).Register(router)
+
+	return router
+}
+
+// Getting error response violated fields.
+func violatedFields(t *testing.T, body []byte) []string {
+	var response v1.Error
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		t.Fatalf("error decoding error response: %s", err.Error())
+	}
+
+	var fields []string
+	for _, v := range response.Violations {
+		fields = append(fields, v.Field)
+	}
+
+	return fields
+}
+
+// Testing getting author posts with page size bounds.
+func TestPostHandler_GetPosts(t *testing.T) {
+	first, last := int32(10), int32(100)
+
+	// Tests structures.
+	tests := []struct {
+		name       string
+		query      string
+		want       domain.SortOptions
+		wantStatus int
+		wantFields []string
+	}{
+		{name: "First", query: "first=10", want: domain.SortOptions{First: &first}, wantStatus: http.StatusOK},
+		{name: "Max Last", query: "last=100", want: domain.SortOptions{Last: &last}, wantStatus: http.StatusOK},
+		{name: "Zero First", query: "first=0", wantStatus: http.StatusBadRequest, wantFields: []string{"first"}},
+		{name: "Too Large Last", query: "last=101", wantStatus: http.StatusBadRequest, wantFields: []string{"last"}},
+		{name: "Malformed First", query: "first=ten", wantStatus: http.StatusBadRequest, wantFields: []string{"first"}},
+		{
+			name:       "First and Last",
+			query:      "first=10&last=10",
+			wantStatus: http.StatusBadRequest,
+			wantFields: []string{"last"},
+		},
+		{
+			name:       "Malformed Created After",
+			query:      "first=10&created_after=yesterday",
+			wantStatus: http.StatusBadRequest,
+			wantFields: []string{"created_after"},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got domain.SortOptions
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/authors/"+ksuid.New().String()+"/posts?"+tt.query, nil)
+
+			newRouter(&sortPost{sort: &got}, nil).ServeHTTP(w, r)
+
+			// Check for response status.
+			if w.Code != tt.wantStatus {
+				t.Fatalf("error response status: got %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+
+			if tt.wantStatus != http.StatusOK {
+				// Check for similarity of violated fields.
+				if fields := violatedFields(t, w.Body.Bytes()); !reflect.DeepEqual(fields, tt.wantFields) {
+					t.Errorf("error violated fields: got %v, want %v", fields, tt.wantFields)
+				}
+
+				return
+			}
+
+			// Check for similarity of sort options.
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("error sort options are not similar: got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Testing watching author post events as server-sent events.
+func TestPostHandler_WatchPosts(t *testing.T) {
+	event := domain.PostEvent{Id: 8, Type: domain.EventPostCreated, Post: domain.Post{Id: ksuid.New(), Text: "text"}}
+	data, _ := json.Marshal(v1.Post{Id: event.Post.Id.String(), Text: "text"})
+
+	// Tests structures.
+	tests := []struct {
+		name        string
+		query       string
+		lastEventId string
+		watchErr    error
+		wantCursor  *int64
+		wantStatus  int
+		wantBody    string
+	}{
+		{
+			name:       "Without Cursor",
+			wantStatus: http.StatusOK,
+			wantBody:   "id: 8\nevent: post.created\ndata: " + string(data) + "\n\n",
+		},
+		{
+			name:        "Last Event Id",
+			query:       "&cursor=3",
+			lastEventId: "7",
+			wantCursor:  int64Ptr(7),
+			wantStatus:  http.StatusOK,
+			wantBody:    "id: 8\nevent: post.created\ndata: " + string(data) + "\n\n",
+		},
+		{
+			name:       "Cursor",
+			query:      "&cursor=3",
+			wantCursor: int64Ptr(3),
+			wantStatus: http.StatusOK,
+			wantBody:   "id: 8\nevent: post.created\ndata: " + string(data) + "\n\n",
+		},
+		{name: "Malformed Cursor", query: "&cursor=three", wantStatus: http.StatusBadRequest},
+		{name: "Malformed Last Event Id", lastEventId: "seven", wantStatus: http.StatusBadRequest},
+		{
+			name: "Error Event",
+			watchErr: &domain.Error{
+				Code:       domain.CodeResourceExhausted,
+				Message:    "Consumer is too slow",
+				Reason:     domain.ReasonConsumerTooSlow,
+				RetryDelay: 1500 * time.Millisecond,
+			},
+			wantStatus: http.StatusOK,
+			wantBody: "id: 8\nevent: post.created\ndata: " + string(data) + "\n\n" +
+				"retry: 1500\nevent: error\ndata: {\"code\":429,\"message\":\"Consumer is too slow\",\"reason\":\"CONSUMER_TOO_SLOW\"}\n",
+		},
+		{
+			name:       "Internal Error Event",
+			watchErr:   &domain.Error{Code: domain.CodeInternal, Message: "database is down"},
+			wantStatus: http.StatusOK,
+			wantBody: "id: 8\nevent: post.created\ndata: " + string(data) + "\n\n" +
+				"event: error\ndata: {\"code\":500,\"message\":\"Internal Server Error\"}\n",
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cursor *int64
+
+			watch := &watchEvent{watch: func(c *int64, send func(domain.PostEvent) error) error {
+				cursor = c
+
+				if err := send(event); err != nil {
+					return err
+				}
+
+				return tt.watchErr
+			}}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/posts/events?author_id="+ksuid.New().String()+tt.query, nil)
+
+			if tt.lastEventId != "" {
+				r.Header.Set("Last-Event-ID", tt.lastEventId)
+			}
+
+			newRouter(nil, watch).ServeHTTP(w, r)
+
+			// Check for response status.
+			if w.Code != tt.wantStatus {
+				t.Fatalf("error response status: got %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			// Check for event stream headers and events.
+			if w.Header().Get("Content-Type") != "text/event-stream" {
+				t.Errorf("error content type: %s", w.Header().Get("Content-Type"))
+			}
+
+			if body := w.Body.String(); strings.TrimRight(body, "\n") != strings.TrimRight(tt.wantBody, "\n") {
+				t.Errorf("error event stream:\ngot  %q\nwant %q", body, tt.wantBody)
+			}
+
+			// Check for similarity of cursors.
+			if !reflect.DeepEqual(cursor, tt.wantCursor) {
+				t.Errorf("error cursor: got %v, want %v", cursor, tt.wantCursor)
+			}
+		})
+	}
+}
+
+// Getting an int64 pointer.
+func int64Ptr(i int64) *int64 { return &i }
@@ -0,0 +1,330 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+	"github.com/durudex/durudex-post-service/internal/transport/grpc"
+
+	"github.com/rs/zerolog/log"
+	"github.com/segmentio/ksuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Maximum number of posts of a page.
+const maxPageSize = 100
+
+// Post response structure.
+type Post struct {
+	Id        string     `json:"id"`
+	AuthorId  string     `json:"author_id,omitempty"`
+	Text      string     `json:"text"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	Flagged   bool       `json:"flagged"`
+}
+
+// Creating a new post response.
+func newPost(post domain.Post) Post {
+	p := Post{Text: post.Text, UpdatedAt: post.UpdatedAt, Flagged: post.Flagged}
+
+	if !post.Id.IsNil() {
+		p.Id = post.Id.String()
+	}
+
+	if !post.AuthorId.IsNil() {
+		p.AuthorId = post.AuthorId.String()
+	}
+
+	if !post.CreatedAt.IsZero() {
+		p.CreatedAt = &post.CreatedAt
+	}
+
+	return p
+}
+
+// Creating new post responses.
+func newPosts(posts []domain.Post) []Post {
+	response := make([]Post, len(posts))
+
+	for i, post := range posts {
+		response[i] = newPost(post)
+	}
+
+	return response
+}
+
+// Parsing a base62 ksuid.
+func parseId(s, name string) (ksuid.KSUID, error) {
+	id, err := ksuid.Parse(s)
+	if err != nil {
+		return ksuid.Nil, invalidArgument(name)
+	}
+
+	return id, nil
+}
+
+// Parsing an optional ksuid value, an empty value is parsed as nil ksuid.
+func parseOptionalId(s, name string) (ksuid.KSUID, error) {
+	if s == "" {
+		return ksuid.Nil, nil
+	}
+
+	return parseId(s, name)
+}
+
+// Parsing an optional int32 query parameter.
+func parseInt32(r *http.Request, name string) (*int32, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	n, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return nil, invalidArgument(name)
+	}
+
+	i := int32(n)
+
+	return &i, nil
+}
+
+// Parsing an optional page size query parameter in the range from 1 to the
+// maximum page size.
+func parsePageSize(r *http.Request, name string) (*int32, error) {
+	n, err := parseInt32(r, name)
+	if err != nil || n == nil {
+		return n, err
+	}
+
+	if *n < 1 || *n > maxPageSize {
+		return nil, &domain.Error{
+			Code:    domain.CodeInvalidArgument,
+			Message: "Invalid " + name,
+			Reason:  domain.ReasonInvalidArgument,
+			Violations: []domain.FieldViolation{
+				{Field: name, Description: "Must be from 1 to " + strconv.Itoa(maxPageSize)},
+			},
+		}
+	}
+
+	return n, nil
+}
+
+// Parsing an optional RFC 3339 time query parameter.
+func parseTime(r *http.Request, name string) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, invalidArgument(name)
+	}
+
+	return t, nil
+}
+
+// Parsing an author posts filter of the query parameters.
+func parsePostFilter(r *http.Request) (domain.PostFilter, error) {
+	var (
+		filter domain.PostFilter
+		err    error
+	)
+
+	if filter.CreatedAfter, err = parseTime(r, "created_after"); err != nil {
+		return filter, err
+	}
+
+	if filter.CreatedBefore, err = parseTime(r, "created_before"); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// Getting an invalid request parameter error.
+func invalidArgument(name string) error {
+	return &domain.Error{
+		Code:       domain.CodeInvalidArgument,
+		Message:    "Invalid " + name,
+		Reason:     domain.ReasonInvalidArgument,
+		Violations: []domain.FieldViolation{{Field: name, Description: "Invalid " + name}},
+	}
+}
+
+// Decoding a JSON request body.
+func decode(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return &domain.Error{
+			Code:    domain.CodeInvalidArgument,
+			Message: "Invalid request body",
+			Reason:  domain.ReasonInvalidArgument,
+		}
+	}
+
+	return nil
+}
+
+// Error response structure.
+type Error struct {
+	Code       int               `json:"code"`
+	Message    string            `json:"message"`
+	Reason     string            `json:"reason,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	Violations []FieldViolation  `json:"violations,omitempty"`
+}
+
+// Field violation response structure.
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// HTTP statuses of gRPC status codes.
+var httpStatuses = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           499,
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusPreconditionFailed,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusGone,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+}
+
+// Getting the HTTP status of the gRPC status code.
+func httpStatus(code codes.Code) int {
+	if s, ok := httpStatuses[code]; ok {
+		return s
+	}
+
+	return http.StatusInternalServerError
+}
+
+// Getting the gRPC status code of the HTTP status, so that HTTP requests are
+// observed with the same status codes as gRPC requests.
+func StatusCode(status int) codes.Code {
+	switch {
+	case status < http.StatusBadRequest:
+		return codes.OK
+	case status == http.StatusConflict:
+		return codes.AlreadyExists
+	case status >= http.StatusInternalServerError && status != http.StatusNotImplemented &&
+		status != http.StatusServiceUnavailable && status != http.StatusGatewayTimeout:
+		return codes.Internal
+	}
+
+	for code, s := range httpStatuses {
+		if s == status {
+			return code
+		}
+	}
+
+	return codes.Unknown
+}
+
+// Writing a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error().Err(err).Msg("error encoding response")
+	}
+}
+
+// Creating a new error response with the retry delay. Errors are mapped to the
+// gRPC status codes of the gRPC transport and internal errors are hidden.
+func newError(err error) (Error, time.Duration) {
+	var e *domain.Error
+
+	// Check if error is a domain.Error.
+	if errors.As(err, &e) {
+		if code := grpc.ErrorCode(e.Code); code != codes.Internal {
+			response := Error{Code: httpStatus(code), Message: e.Message, Reason: e.Reason, Metadata: e.Metadata}
+
+			for _, v := range e.Violations {
+				response.Violations = append(response.Violations, FieldViolation(v))
+			}
+
+			return response, e.RetryDelay
+		}
+	}
+
+	// Check if error is a gRPC status returned by the shared handlers.
+	if st, ok := status.FromError(err); ok && st.Code() != codes.Internal && st.Code() != codes.Unknown {
+		return Error{Code: httpStatus(st.Code()), Message: st.Message()}, 0
+	}
+
+	return Error{Code: http.StatusInternalServerError, Message: "Internal Server Error"}, 0
+}
+
+// Writing an error response.
+func writeError(w http.ResponseWriter, err error) {
+	response, retryDelay := newError(err)
+
+	// Added retry delay.
+	if retryDelay > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryDelay.Seconds()))))
+	}
+
+	writeJSON(w, response.Code, response)
+}
+
+// Writing an error server-sent event, the retry delay is sent as the reconnection
+// time of the event stream.
+func writeErrorEvent(w io.Writer, err error) error {
+	response, retryDelay := newError(err)
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	if retryDelay > 0 {
+		if _, err := fmt.Fprintf(w, "retry: %d\n", retryDelay.Milliseconds()); err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+
+	return err
+}
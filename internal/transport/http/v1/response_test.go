@@ -0,0 +1,119 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/durudex/durudex-post-service/internal/domain"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Testing creating error responses of domain and gRPC status errors.
+func TestNewError(t *testing.T) {
+	// Tests structures.
+	tests := []struct {
+		name           string
+		err            error
+		wantStatus     int
+		wantMessage    string
+		wantRetryDelay time.Duration
+	}{
+		{
+			name:       "Internal",
+			err:        &domain.Error{Code: domain.CodeInternal, Message: "database is down"},
+			wantStatus: http.StatusInternalServerError, wantMessage: "Internal Server Error",
+		},
+		{
+			name:       "Not Found",
+			err:        &domain.Error{Code: domain.CodeNotFound, Message: "Post not found"},
+			wantStatus: http.StatusNotFound, wantMessage: "Post not found",
+		},
+		{
+			name:       "Already Exists",
+			err:        &domain.Error{Code: domain.CodeAlreadyExists, Message: "Post is a duplicate"},
+			wantStatus: http.StatusConflict, wantMessage: "Post is a duplicate",
+		},
+		{
+			name:       "Invalid Argument",
+			err:        &domain.Error{Code: domain.CodeInvalidArgument, Message: "Invalid id"},
+			wantStatus: http.StatusBadRequest, wantMessage: "Invalid id",
+		},
+		{
+			name:       "Resource Exhausted",
+			err:        &domain.Error{Code: domain.CodeResourceExhausted, Message: "Too slow", RetryDelay: time.Second},
+			wantStatus: http.StatusTooManyRequests, wantMessage: "Too slow", wantRetryDelay: time.Second,
+		},
+		{
+			name:       "Unauthenticated",
+			err:        &domain.Error{Code: domain.CodeUnauthenticated, Message: "Authentication required"},
+			wantStatus: http.StatusUnauthorized, wantMessage: "Authentication required",
+		},
+		{
+			name:       "Out Of Range",
+			err:        &domain.Error{Code: domain.CodeOutOfRange, Message: "Cursor expired"},
+			wantStatus: http.StatusGone, wantMessage: "Cursor expired",
+		},
+		{
+			name:       "Unavailable",
+			err:        &domain.Error{Code: domain.CodeUnavailable, Message: "Shutting down", RetryDelay: time.Second},
+			wantStatus: http.StatusServiceUnavailable, wantMessage: "Shutting down", wantRetryDelay: time.Second,
+		},
+		{
+			name:       "Permission Denied Status",
+			err:        status.Error(codes.PermissionDenied, "Permission denied"),
+			wantStatus: http.StatusForbidden, wantMessage: "Permission denied",
+		},
+		{
+			name:       "Failed Precondition Status",
+			err:        status.Error(codes.FailedPrecondition, "Precondition failed"),
+			wantStatus: http.StatusPreconditionFailed, wantMessage: "Precondition failed",
+		},
+		{
+			name:       "Internal Status",
+			err:        status.Error(codes.Internal, "panic"),
+			wantStatus: http.StatusInternalServerError, wantMessage: "Internal Server Error",
+		},
+		{
+			name:       "Unknown",
+			err:        errors.New("unknown error"),
+			wantStatus: http.StatusInternalServerError, wantMessage: "Internal Server Error",
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, retryDelay := newError(tt.err)
+
+			if got.Code != tt.wantStatus || got.Message != tt.wantMessage || retryDelay != tt.wantRetryDelay {
+				t.Errorf("error response: got %+v with retry delay %s", got, retryDelay)
+			}
+
+			// Check for the status code of observed requests.
+			if code := StatusCode(got.Code); httpStatus(code) != got.Code {
+				t.Errorf("error status code %s of HTTP status %d", code, got.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres
+
+import (
+	"github.com/jackc/pgtype"
+	"github.com/segmentio/ksuid"
+)
+
+// Ksuid query argument.
+//
+// Unlike ksuid.KSUID it is not a driver value, which pgx converts to the base62
+// string before encoding, so it is encoded as the type of the compared column:
+// the 20 raw bytes of bytea and the base62 string of character columns.
+type KSUIDArg ksuid.KSUID
+
+// Getting base62 string of the ksuid.
+func (id KSUIDArg) String() string { return ksuid.KSUID(id).String() }
+
+// Binary ksuid postgres type.
+//
+// Extends bytea with encoding ksuid values as their 20 raw bytes instead of
+// the base62 string of the ksuid driver value.
+type KSUID struct{ pgtype.Bytea }
+
+// Setting a ksuid or bytea value.
+func (dst *KSUID) Set(src interface{}) error {
+	switch id := src.(type) {
+	case ksuid.KSUID:
+		return dst.Bytea.Set(id.Bytes())
+	case KSUIDArg:
+		return dst.Bytea.Set(ksuid.KSUID(id).Bytes())
+	}
+
+	return dst.Bytea.Set(src)
+}
+
+// Assigning the value to a ksuid or bytea destination.
+func (src *KSUID) AssignTo(dst interface{}) error {
+	if id, ok := dst.(*ksuid.KSUID); ok {
+		if src.Status != pgtype.Present {
+			*id = ksuid.Nil
+			return nil
+		}
+
+		value, err := ksuid.FromBytes(src.Bytes)
+		if err != nil {
+			return err
+		}
+
+		*id = value
+
+		return nil
+	}
+
+	return src.Bytea.AssignTo(dst)
+}
+
+// Binary ksuid array postgres type.
+type KSUIDArray struct{ pgtype.ByteaArray }
+
+// Setting a ksuid slice or bytea array value.
+func (dst *KSUIDArray) Set(src interface{}) error {
+	if ids, ok := src.([]ksuid.KSUID); ok {
+		values := make([][]byte, len(ids))
+
+		for i, id := range ids {
+			values[i] = id.Bytes()
+		}
+
+		return dst.ByteaArray.Set(values)
+	}
+
+	return dst.ByteaArray.Set(src)
+}
+
+// String ksuid array postgres type.
+//
+// Extends bpchar array with encoding ksuid slices as base62 strings, so that
+// ksuid slices are compared with the string ksuid columns.
+type KSUIDStringArray struct{ pgtype.BPCharArray }
+
+// Setting a ksuid slice or bpchar array value.
+func (dst *KSUIDStringArray) Set(src interface{}) error {
+	if ids, ok := src.([]ksuid.KSUID); ok {
+		values := make([]string, len(ids))
+
+		for i, id := range ids {
+			values[i] = id.String()
+		}
+
+		return dst.BPCharArray.Set(values)
+	}
+
+	return dst.BPCharArray.Set(src)
+}
+
+// Registering ksuid types of the bytea and bpchar values, so that ksuids are
+// encoded as the type of the compared column.
+func RegisterKSUID(ci *pgtype.ConnInfo) {
+	ci.RegisterDataType(pgtype.DataType{Value: &KSUID{}, Name: "bytea", OID: pgtype.ByteaOID})
+	ci.RegisterDataType(pgtype.DataType{Value: &KSUIDArray{}, Name: "_bytea", OID: pgtype.ByteaArrayOID})
+	ci.RegisterDataType(pgtype.DataType{Value: &KSUIDStringArray{}, Name: "_bpchar", OID: pgtype.BPCharArrayOID})
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/pkg/database/postgres"
+	"github.com/durudex/durudex-post-service/pkg/database/postgres/postgrestest"
+
+	"github.com/jackc/pgtype"
+	"github.com/segmentio/ksuid"
+)
+
+// Testing encoding and decoding binary ksuid values.
+func TestKSUID(t *testing.T) {
+	// Registering binary ksuid type.
+	ci := pgtype.NewConnInfo()
+	postgres.RegisterKSUID(ci)
+
+	id := ksuid.New()
+
+	// Tests structures.
+	tests := []struct {
+		name string
+		arg  interface{}
+		want ksuid.KSUID
+	}{
+		{name: "KSUID", arg: id, want: id},
+		{name: "Bytes", arg: ksuid.Max.Bytes(), want: ksuid.Max},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dt, ok := ci.DataTypeForOID(pgtype.ByteaOID)
+			if !ok {
+				t.Fatal("error getting bytea data type")
+			}
+
+			// Encoding value.
+			if err := dt.Value.Set(tt.arg); err != nil {
+				t.Fatalf("error setting value: %s", err.Error())
+			}
+
+			buf, err := dt.Value.(pgtype.BinaryEncoder).EncodeBinary(ci, nil)
+			if err != nil {
+				t.Fatalf("error encoding value: %s", err.Error())
+			}
+
+			if len(buf) != 20 {
+				t.Fatalf("error encoded length: got %d, want 20", len(buf))
+			}
+
+			// Decoding value.
+			var got ksuid.KSUID
+
+			if err := ci.Scan(pgtype.ByteaOID, pgtype.BinaryFormatCode, buf, &got); err != nil {
+				t.Fatalf("error scanning value: %s", err.Error())
+			}
+
+			if got != tt.want {
+				t.Errorf("error ksuid are not equal: got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// Testing setting ksuid array values.
+func TestKSUIDArray(t *testing.T) {
+	// Registering ksuid types.
+	ci := pgtype.NewConnInfo()
+	postgres.RegisterKSUID(ci)
+
+	ids := []ksuid.KSUID{ksuid.New(), ksuid.Max}
+
+	// Tests structures.
+	tests := []struct {
+		name string
+		oid  uint32
+		dst  interface{}
+		want interface{}
+	}{
+		{
+			name: "Bytea",
+			oid:  pgtype.ByteaArrayOID,
+			dst:  &[][]byte{},
+			want: &[][]byte{ids[0].Bytes(), ids[1].Bytes()},
+		},
+		{
+			name: "BPChar",
+			oid:  pgtype.BPCharArrayOID,
+			dst:  &[]string{},
+			want: &[]string{ids[0].String(), ids[1].String()},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dt, ok := ci.DataTypeForOID(tt.oid)
+			if !ok {
+				t.Fatal("error getting array data type")
+			}
+
+			// Setting value.
+			if err := dt.Value.Set(ids); err != nil {
+				t.Fatalf("error setting value: %s", err.Error())
+			}
+
+			if err := dt.Value.AssignTo(tt.dst); err != nil {
+				t.Fatalf("error assigning value: %s", err.Error())
+			}
+
+			if !reflect.DeepEqual(tt.dst, tt.want) {
+				t.Errorf("error array values are not equal: got %v, want %v", tt.dst, tt.want)
+			}
+		})
+	}
+}
+
+// Testing encoding ksuid query arguments as the type of the compared column.
+func TestKSUIDArg(t *testing.T) {
+	id := ksuid.New()
+
+	// Tests structures.
+	tests := []struct {
+		name string
+		oid  uint32
+		arg  interface{}
+		want []byte
+	}{
+		{name: "Bytea", oid: pgtype.ByteaOID, arg: postgres.KSUIDArg(id), want: id.Bytes()},
+		{name: "BPChar", oid: pgtype.BPCharOID, arg: postgres.KSUIDArg(id), want: []byte(id.String())},
+		{name: "Text", oid: pgtype.TextOID, arg: postgres.KSUIDArg(id), want: []byte(id.String())},
+		{name: "KSUID", oid: pgtype.BPCharOID, arg: id, want: []byte(id.String())},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := postgrestest.NewBackend(func(string) []uint32 { return []uint32{tt.oid} })
+
+			// Connecting to the fake backend.
+			conn, err := backend.Connect(context.Background())
+			if err != nil {
+				t.Fatalf("error connecting to backend: %s", err.Error())
+			}
+			defer conn.Close(context.Background())
+
+			// Registering ksuid types.
+			postgres.RegisterKSUID(conn.ConnInfo())
+
+			if _, err := conn.Exec(context.Background(), "DELETE FROM post WHERE id=$1", tt.arg); err != nil {
+				t.Fatalf("error executing query: %s", err.Error())
+			}
+
+			binds := backend.Binds()
+			if len(binds) != 1 || len(binds[0].Params) != 1 {
+				t.Fatalf("error bound statements: %v", binds)
+			}
+
+			// Check for encoded argument.
+			if !bytes.Equal(binds[0].Params[0], tt.want) {
+				t.Errorf("error encoded argument: got %q, want %q", binds[0].Params[0], tt.want)
+			}
+		})
+	}
+}
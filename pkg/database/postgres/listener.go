@@ -0,0 +1,70 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Postgres notification listener interface.
+type Listener interface {
+	// Listening for channel notifications until the context is done or the connection fails.
+	// The handler is called with an empty payload once the listener is ready.
+	Listen(ctx context.Context, channel string, handler func(payload string)) error
+}
+
+// Postgres notification listener structure.
+type PoolListener struct{ pool *pgxpool.Pool }
+
+// Creating a new postgres notification listener.
+func NewListener(pool *pgxpool.Pool) *PoolListener {
+	return &PoolListener{pool: pool}
+}
+
+// Listening for channel notifications until the context is done or the connection fails.
+func (l *PoolListener) Listen(ctx context.Context, channel string, handler func(payload string)) error {
+	// Acquire a dedicated connection from the pool.
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	// A listening connection must not be returned to the pool.
+	defer conn.Conn().Close(context.Background())
+
+	// Subscribe to the channel.
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return err
+	}
+
+	// Listener is ready.
+	handler("")
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		handler(notification.Payload)
+	}
+}
@@ -0,0 +1,358 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// Advisory lock key of applying schema migrations.
+const migrationLockKey int64 = 0x6475727564657801
+
+// Postgres undefined table error code.
+const undefinedTableCode = "42P01"
+
+var (
+	// Schema is marked as dirty by a failed migration of the migrate tool.
+	ErrDirtySchema = errors.New("schema is dirty")
+	// Schema version is behind the latest migration.
+	ErrSchemaBehind = errors.New("schema version is behind")
+)
+
+// Schema migration structure.
+//
+// Batch migration is a statement executed repeatedly, each time in its own
+// transaction, until it no longer affects any rows. Concurrent migration is a
+// single statement executed outside a transaction, such as building an index
+// concurrently. Contract migration breaks instances of the previous release,
+// so it is applied only when explicitly requested.
+type Migration struct {
+	Version    uint
+	Name       string
+	Up         string
+	Batch      bool
+	Concurrent bool
+	Contract   bool
+}
+
+// Postgres schema migrator structure.
+//
+// Schema versions are stored in the schema_migrations table of the migrate
+// tool, so databases migrated by the tool are continued.
+type Migrator struct {
+	psql       Postgres
+	migrations []Migration
+}
+
+// Creating a new postgres schema migrator of the up migration files
+// named as {version}_{name}.up.sql, batch and concurrent migrations are
+// named as {version}_{name}.batch.up.sql and {version}_{name}.concurrent.up.sql,
+// contract migrations are named as {version}_{name}.contract.up.sql.
+func NewMigrator(psql Postgres, fsys fs.FS) (*Migrator, error) {
+	files, err := fs.Glob(fsys, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]Migration, 0, len(files))
+
+	for _, file := range files {
+		// Parsing migration version and name.
+		name := strings.TrimSuffix(path.Base(file), ".up.sql")
+		batch := strings.HasSuffix(name, ".batch")
+		name = strings.TrimSuffix(name, ".batch")
+		concurrent := strings.HasSuffix(name, ".concurrent")
+		name = strings.TrimSuffix(name, ".concurrent")
+		contract := strings.HasSuffix(name, ".contract")
+		name = strings.TrimSuffix(name, ".contract")
+
+		if batch && concurrent {
+			return nil, fmt.Errorf("batch migration can not be concurrent: %s", file)
+		}
+
+		i := strings.IndexByte(name, '_')
+		if i < 0 {
+			return nil, fmt.Errorf("invalid migration file name: %s", file)
+		}
+
+		version, err := strconv.ParseUint(name[:i], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version: %s", file)
+		}
+
+		up, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, Migration{
+			Version:    uint(version),
+			Name:       name[i+1:],
+			Up:         string(up),
+			Batch:      batch,
+			Concurrent: concurrent,
+			Contract:   contract,
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	// Check for duplicate versions.
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return nil, fmt.Errorf("duplicate migration version: %d", migrations[i].Version)
+		}
+	}
+
+	return &Migrator{psql: psql, migrations: migrations}, nil
+}
+
+// Getting the latest migration version.
+func (m *Migrator) Latest() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Getting the schema version required to serve at the current version. Pending
+// contract migrations and the migrations after them are not required.
+func (m *Migrator) Required(current uint) uint {
+	var required uint
+
+	for _, migration := range m.migrations {
+		if migration.Contract && migration.Version > current {
+			break
+		}
+
+		required = migration.Version
+	}
+
+	return required
+}
+
+// Getting the current schema version. Version of a database without the
+// migrations table is zero.
+func (m *Migrator) Version(ctx context.Context) (uint, bool, error) {
+	return version(ctx, m.psql)
+}
+
+// Applying pending migrations, contract migrations are applied only when
+// the contract is enabled.
+//
+// Every migration is applied in its own transaction holding the migrations
+// advisory lock, so that concurrently starting instances apply each
+// migration once. Batch migrations hold the lock only for a single batch,
+// so that large tables are backfilled without long running transactions.
+// Concurrent migrations mark the schema version as dirty while the
+// statement is executed, and a failed statement leaves it dirty to be fixed
+// manually like the migrate tool.
+func (m *Migrator) Up(ctx context.Context, contract bool) error {
+	for {
+		applied, done, err := m.next(ctx, contract)
+		if err != nil {
+			return err
+		}
+
+		if applied == nil {
+			return nil
+		}
+
+		if !done {
+			log.Debug().Uint("version", applied.Version).Msg("Applied schema migration batch")
+			continue
+		}
+
+		log.Info().Uint("version", applied.Version).Str("name", applied.Name).Msg("Applied schema migration")
+	}
+}
+
+// Checking the schema version is not behind the latest migration.
+func (m *Migrator) Check(ctx context.Context) error {
+	version, dirty, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		return fmt.Errorf("%w: version %d", ErrDirtySchema, version)
+	}
+
+	if required := m.Required(version); version < required {
+		return fmt.Errorf("%w: version %d, expected %d", ErrSchemaBehind, version, required)
+	}
+
+	return nil
+}
+
+// Applying the next pending migration or the next batch of a batch
+// migration. Nil migration is returned when the schema is up to date or the
+// next migration is a disabled contract, and the migration is done when the
+// schema version is set.
+func (m *Migrator) next(ctx context.Context, contract bool) (*Migration, bool, error) {
+	// Begin a migration transaction.
+	tx, err := m.psql.Begin(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Waiting for other instances applying migrations.
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLockKey); err != nil {
+		return nil, false, err
+	}
+
+	// Query to create the migrations table of the migrate tool.
+	query := "CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)"
+
+	if _, err := tx.Exec(ctx, query); err != nil {
+		return nil, false, err
+	}
+
+	current, dirty, err := version(ctx, tx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if dirty {
+		return nil, false, fmt.Errorf("%w: version %d", ErrDirtySchema, current)
+	}
+
+	// Getting the next pending migration.
+	var migration *Migration
+
+	for i := range m.migrations {
+		if m.migrations[i].Version > current {
+			migration = &m.migrations[i]
+			break
+		}
+	}
+
+	if migration == nil {
+		return nil, false, tx.Commit(ctx)
+	}
+
+	if migration.Contract && !contract {
+		log.Warn().Uint("version", migration.Version).Str("name", migration.Name).
+			Msg("Contract schema migration is pending, apply it with --migrate-contract")
+
+		return nil, false, tx.Commit(ctx)
+	}
+
+	if migration.Concurrent {
+		return m.concurrent(ctx, tx, migration)
+	}
+
+	// Applying migration.
+	tag, err := tx.Exec(ctx, migration.Up)
+	if err != nil {
+		return nil, false, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	// Committing the batch until the migration has no rows left.
+	if migration.Batch && tag.RowsAffected() != 0 {
+		return migration, false, tx.Commit(ctx)
+	}
+
+	// Setting schema version.
+	if err := setVersion(ctx, tx, migration.Version, false); err != nil {
+		return nil, false, err
+	}
+
+	return migration, true, tx.Commit(ctx)
+}
+
+// Applying a concurrent migration outside the migration transaction.
+func (m *Migrator) concurrent(ctx context.Context, tx pgx.Tx, migration *Migration) (*Migration, bool, error) {
+	// Marking schema version as dirty, so that other instances do not apply
+	// the migration at the same time.
+	if err := setVersion(ctx, tx, migration.Version, true); err != nil {
+		return nil, false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, false, err
+	}
+
+	// Applying migration.
+	if _, err := m.psql.Exec(ctx, migration.Up); err != nil {
+		return nil, false, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	// Query to mark schema version as clean.
+	query := "UPDATE schema_migrations SET dirty = false WHERE version = $1"
+
+	if _, err := m.psql.Exec(ctx, query, int64(migration.Version)); err != nil {
+		return nil, false, err
+	}
+
+	return migration, true, nil
+}
+
+// Setting schema version of the migrations table in the transaction.
+func setVersion(ctx context.Context, tx pgx.Tx, version uint, dirty bool) error {
+	if _, err := tx.Exec(ctx, "DELETE FROM schema_migrations"); err != nil {
+		return err
+	}
+
+	query := "INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)"
+
+	_, err := tx.Exec(ctx, query, int64(version), dirty)
+
+	return err
+}
+
+// Query row interface.
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// Getting schema version of the migrations table.
+func version(ctx context.Context, q queryRower) (uint, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+
+	// Query for getting schema version.
+	query := "SELECT version, dirty FROM schema_migrations LIMIT 1"
+
+	if err := q.QueryRow(ctx, query).Scan(&version, &dirty); err != nil {
+		var pgErr *pgconn.PgError
+
+		if errors.Is(err, pgx.ErrNoRows) || (errors.As(err, &pgErr) && pgErr.Code == undefinedTableCode) {
+			return 0, false, nil
+		}
+
+		return 0, false, err
+	}
+
+	return uint(version), dirty, nil
+}
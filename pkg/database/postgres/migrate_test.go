@@ -0,0 +1,374 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/durudex/durudex-post-service/pkg/database/postgres"
+
+	"github.com/jackc/pgconn"
+	"github.com/pashagolub/pgxmock"
+)
+
+// Testing schema migrations.
+var migrations = fstest.MapFS{
+	"000002_post_fingerprint.up.sql":   {Data: []byte("ALTER TABLE post ADD COLUMN hash BYTEA;")},
+	"000002_post_fingerprint.down.sql": {Data: []byte("ALTER TABLE post DROP COLUMN hash;")},
+	"000001_durudex.up.sql":            {Data: []byte("CREATE TABLE post (id CHAR(27));")},
+	"000001_durudex.down.sql":          {Data: []byte("DROP TABLE post;")},
+}
+
+// Testing loading schema migrations.
+func TestNewMigrator(t *testing.T) {
+	// Tests structures.
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		want    uint
+		wantErr bool
+	}{
+		{name: "OK", fsys: migrations, want: 2},
+		{name: "Empty", fsys: fstest.MapFS{}, want: 0},
+		{
+			name:    "Invalid version",
+			fsys:    fstest.MapFS{"first_durudex.up.sql": {}},
+			wantErr: true,
+		},
+		{
+			name:    "Concurrent batch",
+			fsys:    fstest.MapFS{"000001_post_backfill.concurrent.batch.up.sql": {}},
+			wantErr: true,
+		},
+		{
+			name: "Duplicate version",
+			fsys: fstest.MapFS{
+				"000001_durudex.up.sql": {},
+				"1_post.up.sql":         {},
+			},
+			wantErr: true,
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Creating a new migrator.
+			m, err := postgres.NewMigrator(nil, tt.fsys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error creating migrator: %v", err)
+			}
+
+			// Check for latest migration version.
+			if err == nil && m.Latest() != tt.want {
+				t.Errorf("error latest version: got %d, want %d", m.Latest(), tt.want)
+			}
+		})
+	}
+}
+
+// Testing applying pending schema migrations.
+func TestMigrator_Up(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Creating a new migrator.
+	m, err := postgres.NewMigrator(mock, migrations)
+	if err != nil {
+		t.Fatalf("error creating migrator: %s", err.Error())
+	}
+
+	// Expecting a locked migration transaction at the version.
+	expectVersion := func(version int64) {
+		mock.ExpectBegin()
+		mock.ExpectExec("SELECT pg_advisory_xact_lock").WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").
+			WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+		mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+			WillReturnRows(mock.NewRows([]string{"version", "dirty"}).AddRow(version, false))
+	}
+
+	// Applying the second migration.
+	expectVersion(1)
+	mock.ExpectExec("ALTER TABLE post ADD COLUMN hash BYTEA").WillReturnResult(pgxmock.NewResult("ALTER TABLE", 0))
+	mock.ExpectExec("DELETE FROM schema_migrations").WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(int64(2), false).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+
+	// Schema is up to date.
+	expectVersion(2)
+	mock.ExpectCommit()
+
+	// Applying pending migrations.
+	if err := m.Up(context.Background(), false); err != nil {
+		t.Fatalf("error applying migrations: %s", err.Error())
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("error unmet expectations: %s", err.Error())
+	}
+}
+
+// Testing applying batch schema migrations.
+func TestMigrator_UpBatch(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Creating a new migrator.
+	m, err := postgres.NewMigrator(mock, fstest.MapFS{
+		"000001_durudex.up.sql":             {Data: []byte("CREATE TABLE post (id CHAR(27));")},
+		"000002_post_backfill.batch.up.sql": {Data: []byte("UPDATE post SET hash = '' WHERE hash IS NULL;")},
+	})
+	if err != nil {
+		t.Fatalf("error creating migrator: %s", err.Error())
+	}
+
+	// Expecting a locked migration transaction at the version.
+	expectVersion := func(version int64) {
+		mock.ExpectBegin()
+		mock.ExpectExec("SELECT pg_advisory_xact_lock").WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").
+			WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+		mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+			WillReturnRows(mock.NewRows([]string{"version", "dirty"}).AddRow(version, false))
+	}
+
+	// Applying batches until no rows are updated.
+	for _, affected := range []int64{1000, 1} {
+		expectVersion(1)
+		mock.ExpectExec("UPDATE post SET hash").WillReturnResult(pgxmock.NewResult("UPDATE", affected))
+		mock.ExpectCommit()
+	}
+
+	expectVersion(1)
+	mock.ExpectExec("UPDATE post SET hash").WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mock.ExpectExec("DELETE FROM schema_migrations").WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(int64(2), false).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+
+	// Schema is up to date.
+	expectVersion(2)
+	mock.ExpectCommit()
+
+	// Applying pending migrations.
+	if err := m.Up(context.Background(), false); err != nil {
+		t.Fatalf("error applying migrations: %s", err.Error())
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("error unmet expectations: %s", err.Error())
+	}
+}
+
+// Testing contract schema migrations.
+var contractMigrations = fstest.MapFS{
+	"000001_durudex.up.sql": {Data: []byte("CREATE TABLE post (id CHAR(27), id_bytes BYTEA);")},
+	"000002_post_id_bytes_key.contract.concurrent.up.sql": {
+		Data: []byte("CREATE UNIQUE INDEX CONCURRENTLY post_id_bytes_key ON post (id_bytes);"),
+	},
+	"000003_post_ksuid_contract.contract.up.sql": {Data: []byte("ALTER TABLE post DROP COLUMN id;")},
+	"000004_post_fingerprint.up.sql":             {Data: []byte("ALTER TABLE post ADD COLUMN hash BYTEA;")},
+}
+
+// Testing applying contract schema migrations.
+func TestMigrator_UpContract(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Creating a new migrator.
+	m, err := postgres.NewMigrator(mock, contractMigrations)
+	if err != nil {
+		t.Fatalf("error creating migrator: %s", err.Error())
+	}
+
+	// Expecting a locked migration transaction at the version.
+	expectVersion := func(version int64) {
+		mock.ExpectBegin()
+		mock.ExpectExec("SELECT pg_advisory_xact_lock").WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").
+			WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+		mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+			WillReturnRows(mock.NewRows([]string{"version", "dirty"}).AddRow(version, false))
+	}
+
+	// Stopping at the contract migration.
+	expectVersion(1)
+	mock.ExpectCommit()
+
+	if err := m.Up(context.Background(), false); err != nil {
+		t.Fatalf("error applying migrations: %s", err.Error())
+	}
+
+	// Applying the concurrent migration outside the transaction.
+	expectVersion(1)
+	mock.ExpectExec("DELETE FROM schema_migrations").WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(int64(2), true).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+	mock.ExpectExec("CREATE UNIQUE INDEX CONCURRENTLY post_id_bytes_key").
+		WillReturnResult(pgxmock.NewResult("CREATE INDEX", 0))
+	mock.ExpectExec("UPDATE schema_migrations SET dirty = false").WithArgs(int64(2)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	// Applying the contract and later migrations.
+	for _, migration := range []struct {
+		version int64
+		query   string
+	}{{2, "ALTER TABLE post DROP COLUMN id"}, {3, "ALTER TABLE post ADD COLUMN hash"}} {
+		expectVersion(migration.version)
+		mock.ExpectExec(migration.query).WillReturnResult(pgxmock.NewResult("ALTER TABLE", 0))
+		mock.ExpectExec("DELETE FROM schema_migrations").WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(migration.version+1, false).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+	}
+
+	// Schema is up to date.
+	expectVersion(4)
+	mock.ExpectCommit()
+
+	if err := m.Up(context.Background(), true); err != nil {
+		t.Fatalf("error applying contract migrations: %s", err.Error())
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("error unmet expectations: %s", err.Error())
+	}
+}
+
+// Testing getting required schema version.
+func TestMigrator_Required(t *testing.T) {
+	// Creating a new migrator.
+	m, err := postgres.NewMigrator(nil, contractMigrations)
+	if err != nil {
+		t.Fatalf("error creating migrator: %s", err.Error())
+	}
+
+	// Tests structures.
+	tests := []struct {
+		name    string
+		current uint
+		want    uint
+	}{
+		{name: "Not migrated", current: 0, want: 1},
+		{name: "Contract pending", current: 1, want: 1},
+		{name: "Contract index built", current: 2, want: 2},
+		{name: "Contracted", current: 3, want: 4},
+		{name: "Up to date", current: 4, want: 4},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.Required(tt.current); got != tt.want {
+				t.Errorf("error required version: got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// Testing checking schema version.
+func TestMigrator_Check(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Creating a new migrator.
+	m, err := postgres.NewMigrator(mock, migrations)
+	if err != nil {
+		t.Fatalf("error creating migrator: %s", err.Error())
+	}
+
+	// Tests structures.
+	tests := []struct {
+		name         string
+		wantErr      error
+		mockBehavior func()
+	}{
+		{
+			name: "OK",
+			mockBehavior: func() {
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+					WillReturnRows(mock.NewRows([]string{"version", "dirty"}).AddRow(int64(2), false))
+			},
+		},
+		{
+			name: "Ahead",
+			mockBehavior: func() {
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+					WillReturnRows(mock.NewRows([]string{"version", "dirty"}).AddRow(int64(3), false))
+			},
+		},
+		{
+			name:    "Behind",
+			wantErr: postgres.ErrSchemaBehind,
+			mockBehavior: func() {
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+					WillReturnRows(mock.NewRows([]string{"version", "dirty"}).AddRow(int64(1), false))
+			},
+		},
+		{
+			name:    "Dirty",
+			wantErr: postgres.ErrDirtySchema,
+			mockBehavior: func() {
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+					WillReturnRows(mock.NewRows([]string{"version", "dirty"}).AddRow(int64(2), true))
+			},
+		},
+		{
+			name:    "Not migrated",
+			wantErr: postgres.ErrSchemaBehind,
+			mockBehavior: func() {
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+					WillReturnError(&pgconn.PgError{Code: "42P01"})
+			},
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			// Checking schema version.
+			if err := m.Check(context.Background()); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error checking schema version: got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -21,6 +21,8 @@ import (
 	"context"
 	"os"
 
+	"github.com/durudex/durudex-post-service/pkg/requestid"
+
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/log/zerologadapter"
@@ -35,6 +37,7 @@ type Postgres interface {
 	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
 	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
 	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Ping(ctx context.Context) error
 }
 
 // Postgres config structure.
@@ -48,16 +51,30 @@ type PostgresConfig struct {
 func (c *PostgresConfig) Configure(cfg *pgxpool.Config) {
 	log.Debug().Msg("Configuring postgres driver")
 
-	// Set driver logger.
-	cfg.ConnConfig.Logger = zerologadapter.NewLogger(zerolog.New(os.Stderr))
+	// Set driver logger with request id of the query context.
+	cfg.ConnConfig.Logger = zerologadapter.NewLogger(zerolog.New(os.Stderr),
+		zerologadapter.WithContextFunc(func(ctx context.Context, zc zerolog.Context) zerolog.Context {
+			if id, ok := requestid.FromContext(ctx); ok {
+				zc = zc.Str("request_id", id)
+			}
+
+			return zc
+		}),
+	)
 
 	// Set max and min postgres driver connections.
 	cfg.MaxConns = c.MaxConns
 	cfg.MinConns = c.MinConns
+
+	// Register binary ksuid type on each new connection.
+	cfg.AfterConnect = func(_ context.Context, conn *pgx.Conn) error {
+		RegisterKSUID(conn.ConnInfo())
+		return nil
+	}
 }
 
 // Creating a new postgres pool connection.
-func NewPool(cfg *PostgresConfig) (Postgres, error) {
+func NewPool(cfg *PostgresConfig) (*pgxpool.Pool, error) {
 	log.Debug().Msg("Creating a new postgres pool connection")
 
 	// Parsing database url.
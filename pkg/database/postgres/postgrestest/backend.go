@@ -0,0 +1,187 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package postgrestest provides a fake postgres protocol backend for testing
+// the arguments encoded by pgx.
+package postgrestest
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgx/v4"
+)
+
+// Bound statement structure.
+type Bind struct {
+	// Statement query.
+	SQL string
+	// Parameter type OIDs of the statement.
+	OIDs []uint32
+	// Encoded parameter values.
+	Params [][]byte
+}
+
+// Fake postgres protocol backend structure.
+//
+// Statements have no result columns, and every statement affects one row.
+type Backend struct {
+	// Getting parameter type OIDs of a statement.
+	oids func(sql string) []uint32
+
+	mu    sync.Mutex
+	binds []Bind
+}
+
+// Creating a new fake postgres protocol backend with parameter type OIDs of
+// the statements.
+func NewBackend(oids func(sql string) []uint32) *Backend {
+	return &Backend{oids: oids}
+}
+
+// Connecting to the backend.
+func (b *Backend) Connect(ctx context.Context) (*pgx.Conn, error) {
+	config, err := pgx.ParseConfig("postgres://postgres@localhost/postgres?sslmode=disable")
+	if err != nil {
+		return nil, err
+	}
+
+	// Serving the server side of an in-memory connection.
+	config.DialFunc = func(context.Context, string, string) (net.Conn, error) {
+		client, server := net.Pipe()
+		go b.serve(server)
+
+		return client, nil
+	}
+
+	return pgx.ConnectConfig(ctx, config)
+}
+
+// Getting bound statements.
+func (b *Backend) Binds() []Bind {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]Bind(nil), b.binds...)
+}
+
+// Serving a connection.
+func (b *Backend) serve(conn net.Conn) {
+	defer conn.Close()
+
+	backend := pgproto3.NewBackend(pgproto3.NewChunkReader(conn), conn)
+
+	if _, err := backend.ReceiveStartupMessage(); err != nil {
+		return
+	}
+
+	if err := b.send(backend, &pgproto3.AuthenticationOk{}, &pgproto3.ReadyForQuery{TxStatus: 'I'}); err != nil {
+		return
+	}
+
+	// Prepared statements by name.
+	statements := make(map[string]string)
+
+	var portal string
+
+	for {
+		msg, err := backend.Receive()
+		if err != nil {
+			return
+		}
+
+		switch msg := msg.(type) {
+		case *pgproto3.Query:
+			err = b.send(backend, &pgproto3.CommandComplete{CommandTag: commandTag(msg.String)},
+				&pgproto3.ReadyForQuery{TxStatus: 'I'})
+		case *pgproto3.Parse:
+			statements[msg.Name] = msg.Query
+			err = b.send(backend, &pgproto3.ParseComplete{})
+		case *pgproto3.Describe:
+			if msg.ObjectType == 'S' {
+				err = b.send(backend, &pgproto3.ParameterDescription{ParameterOIDs: b.oids(statements[msg.Name])},
+					&pgproto3.NoData{})
+			} else {
+				err = b.send(backend, &pgproto3.NoData{})
+			}
+		case *pgproto3.Bind:
+			portal = statements[msg.PreparedStatement]
+			b.bind(portal, msg.Parameters)
+			err = b.send(backend, &pgproto3.BindComplete{})
+		case *pgproto3.Execute:
+			err = b.send(backend, &pgproto3.CommandComplete{CommandTag: commandTag(portal)})
+		case *pgproto3.Sync:
+			err = b.send(backend, &pgproto3.ReadyForQuery{TxStatus: 'I'})
+		case *pgproto3.Terminate:
+			return
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Recording a bound statement.
+func (b *Backend) bind(sql string, params [][]byte) {
+	bind := Bind{SQL: sql, OIDs: b.oids(sql), Params: make([][]byte, len(params))}
+
+	// Copying parameters of the reused message buffer.
+	for i, param := range params {
+		if param != nil {
+			bind.Params[i] = append([]byte{}, param...)
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.binds = append(b.binds, bind)
+}
+
+// Sending backend messages.
+func (b *Backend) send(backend *pgproto3.Backend, msgs ...pgproto3.BackendMessage) error {
+	for _, msg := range msgs {
+		if err := backend.Send(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Getting command tag of a statement affecting one row.
+func commandTag(sql string) []byte {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	command := strings.ToUpper(fields[0])
+
+	switch command {
+	case "INSERT":
+		return []byte("INSERT 0 1")
+	case "SELECT", "UPDATE", "DELETE":
+		return []byte(command + " 1")
+	}
+
+	return []byte(command)
+}
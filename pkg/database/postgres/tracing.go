@@ -0,0 +1,271 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Postgres tracer name.
+const tracerName = "github.com/durudex/durudex-post-service/pkg/database/postgres"
+
+// Tracing postgres driver structure.
+//
+// Every query is recorded as a client span with sanitized SQL, queries of
+// transactions started by the driver are traced as well.
+type TracingPostgres struct {
+	Postgres
+	tracer trace.Tracer
+}
+
+// Creating a new tracing postgres driver.
+func NewTracingPostgres(psql Postgres, provider trace.TracerProvider) *TracingPostgres {
+	return &TracingPostgres{Postgres: psql, tracer: provider.Tracer(tracerName)}
+}
+
+// Beginning a traced transaction.
+func (p *TracingPostgres) Begin(ctx context.Context) (pgx.Tx, error) {
+	ctx, span := startSpan(ctx, p.tracer, "BEGIN")
+	defer span.End()
+
+	tx, err := p.Postgres.Begin(ctx)
+	if err != nil {
+		recordError(span, err)
+
+		return nil, err
+	}
+
+	return &tracingTx{Tx: tx, tracer: p.tracer}, nil
+}
+
+// Executing a traced query returning rows.
+func (p *TracingPostgres) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return query(ctx, p.tracer, p.Postgres.Query, sql, args...)
+}
+
+// Executing a traced query returning a single row.
+func (p *TracingPostgres) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return queryRow(ctx, p.tracer, p.Postgres.QueryRow, sql, args...)
+}
+
+// Executing a traced query.
+func (p *TracingPostgres) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return exec(ctx, p.tracer, p.Postgres.Exec, sql, args...)
+}
+
+// Tracing transaction structure.
+type tracingTx struct {
+	pgx.Tx
+	tracer trace.Tracer
+}
+
+// Executing a traced query returning rows in the transaction.
+func (t *tracingTx) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return query(ctx, t.tracer, t.Tx.Query, sql, args...)
+}
+
+// Executing a traced query returning a single row in the transaction.
+func (t *tracingTx) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return queryRow(ctx, t.tracer, t.Tx.QueryRow, sql, args...)
+}
+
+// Executing a traced query in the transaction.
+func (t *tracingTx) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return exec(ctx, t.tracer, t.Tx.Exec, sql, args...)
+}
+
+// Committing a traced transaction.
+func (t *tracingTx) Commit(ctx context.Context) error {
+	ctx, span := startSpan(ctx, t.tracer, "COMMIT")
+	defer span.End()
+
+	if err := t.Tx.Commit(ctx); err != nil {
+		recordError(span, err)
+
+		return err
+	}
+
+	return nil
+}
+
+// Executing a query, the span ends when rows are closed.
+func query(ctx context.Context, tracer trace.Tracer, fn func(context.Context, string, ...interface{}) (pgx.Rows, error), sql string, args ...interface{}) (pgx.Rows, error) {
+	ctx, span := startSpan(ctx, tracer, sql)
+
+	rows, err := fn(ctx, sql, args...)
+	if err != nil {
+		recordError(span, err)
+		span.End()
+
+		return nil, err
+	}
+
+	return &tracingRows{Rows: rows, span: span}, nil
+}
+
+// Executing a query returning a row, the span ends when the row is scanned.
+func queryRow(ctx context.Context, tracer trace.Tracer, fn func(context.Context, string, ...interface{}) pgx.Row, sql string, args ...interface{}) pgx.Row {
+	ctx, span := startSpan(ctx, tracer, sql)
+
+	return &tracingRow{Row: fn(ctx, sql, args...), span: span}
+}
+
+// Executing a query.
+func exec(ctx context.Context, tracer trace.Tracer, fn func(context.Context, string, ...interface{}) (pgconn.CommandTag, error), sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	ctx, span := startSpan(ctx, tracer, sql)
+	defer span.End()
+
+	tag, err := fn(ctx, sql, args...)
+	if err != nil {
+		recordError(span, err)
+
+		return tag, err
+	}
+
+	span.SetAttributes(attribute.Int64("db.rows_affected", tag.RowsAffected()))
+
+	return tag, nil
+}
+
+// Tracing rows structure.
+type tracingRows struct {
+	pgx.Rows
+	span trace.Span
+}
+
+// Closing rows and ending the query span.
+func (r *tracingRows) Close() {
+	r.Rows.Close()
+
+	if err := r.Rows.Err(); err != nil {
+		recordError(r.span, err)
+	}
+
+	r.span.End()
+}
+
+// Tracing row structure.
+type tracingRow struct {
+	pgx.Row
+	span trace.Span
+}
+
+// Scanning row and ending the query span.
+func (r *tracingRow) Scan(dest ...interface{}) error {
+	defer r.span.End()
+
+	err := r.Row.Scan(dest...)
+	if err != nil && err != pgx.ErrNoRows {
+		recordError(r.span, err)
+	}
+
+	return err
+}
+
+// Starting a new query span.
+func startSpan(ctx context.Context, tracer trace.Tracer, sql string) (context.Context, trace.Span) {
+	statement := SanitizeSQL(sql)
+
+	return tracer.Start(ctx, spanName(statement),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBStatementKey.String(statement)),
+	)
+}
+
+// Recording span error.
+func recordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Getting span name by the statement operation.
+func spanName(statement string) string {
+	operation := statement
+	if i := strings.IndexByte(statement, ' '); i != -1 {
+		operation = statement[:i]
+	}
+
+	return "postgres." + strings.ToUpper(operation)
+}
+
+// Sanitizing SQL by replacing string and numeric literals with "?" and
+// collapsing whitespace. Query parameters such as $1 are kept.
+func SanitizeSQL(sql string) string {
+	var b strings.Builder
+
+	runes := []rune(sql)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == '\'':
+			// Skipping string literal, quotes are escaped by doubling.
+			for i++; i < len(runes); i++ {
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						i++
+						continue
+					}
+
+					break
+				}
+			}
+
+			b.WriteRune('?')
+		case unicode.IsDigit(r) && !isIdentifier(runes, i):
+			// Skipping numeric literal.
+			for i+1 < len(runes) && (unicode.IsDigit(runes[i+1]) || runes[i+1] == '.') {
+				i++
+			}
+
+			b.WriteRune('?')
+		case unicode.IsSpace(r):
+			// Collapsing whitespace.
+			for i+1 < len(runes) && unicode.IsSpace(runes[i+1]) {
+				i++
+			}
+
+			b.WriteRune(' ')
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+// Check is digit a part of an identifier or a query parameter.
+func isIdentifier(runes []rune, i int) bool {
+	if i == 0 {
+		return false
+	}
+
+	prev := runes[i-1]
+
+	return prev == '$' || prev == '_' || unicode.IsLetter(prev) || unicode.IsDigit(prev)
+}
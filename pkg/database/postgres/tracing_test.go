@@ -0,0 +1,161 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/pkg/database/postgres"
+
+	"github.com/pashagolub/pgxmock"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Testing sanitizing SQL.
+func TestSanitizeSQL(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{
+			name: "Parameters",
+			sql:  "SELECT author_id, text FROM post\n\t\tWHERE id=$1 AND n > $12",
+			want: "SELECT author_id, text FROM post WHERE id=$1 AND n > $12",
+		},
+		{
+			name: "Literals",
+			sql:  "SELECT id FROM post WHERE text = 'it''s secret' AND simhash::bit(64) <> 42.5 AND t2 = 1",
+			want: "SELECT id FROM post WHERE text = ? AND simhash::bit(?) <> ? AND t2 = ?",
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := postgres.SanitizeSQL(tt.sql); got != tt.want {
+				t.Errorf("error sanitizing sql: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Testing tracing queries and transactions.
+func TestTracingPostgres(t *testing.T) {
+	// Creating a new mock connection.
+	mock, err := pgxmock.NewConn()
+	if err != nil {
+		t.Fatalf("error creating a new mock connection: %s", err.Error())
+	}
+	defer mock.Close(context.Background())
+
+	// Creating a new tracer provider with span recorder.
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	// Creating a new tracing postgres driver.
+	psql := postgres.NewTracingPostgres(mock, provider)
+
+	mock.ExpectQuery("SELECT text FROM post").WithArgs("id").
+		WillReturnRows(mock.NewRows([]string{"text"}).AddRow("text"))
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM post").WithArgs("id").
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectCommit()
+
+	// Starting a parent span.
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+
+	var text string
+
+	// Scanning a traced query row.
+	if err := psql.QueryRow(ctx, "SELECT text FROM post WHERE id = $1 AND text <> 'secret'", "id").Scan(&text); err != nil {
+		t.Fatalf("error scanning row: %s", err.Error())
+	}
+
+	// Executing a traced transaction.
+	tx, err := psql.Begin(ctx)
+	if err != nil {
+		t.Fatalf("error beginning transaction: %s", err.Error())
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM post WHERE id = $1", "id"); err != nil {
+		t.Fatalf("error executing query: %s", err.Error())
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatalf("error committing transaction: %s", err.Error())
+	}
+
+	parent.End()
+
+	// Check for expected mock calls.
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("error expectations were not met: %s", err.Error())
+	}
+
+	spans := recorder.Ended()
+
+	// Check for span structure.
+	want := []struct {
+		name      string
+		statement string
+	}{
+		{name: "postgres.SELECT", statement: "SELECT text FROM post WHERE id = $1 AND text <> ?"},
+		{name: "postgres.BEGIN", statement: "BEGIN"},
+		{name: "postgres.DELETE", statement: "DELETE FROM post WHERE id = $1"},
+		{name: "postgres.COMMIT", statement: "COMMIT"},
+	}
+
+	if len(spans) != len(want)+1 {
+		t.Fatalf("error span count: got %d, want %d", len(spans), len(want)+1)
+	}
+
+	for i, w := range want {
+		span := spans[i]
+
+		if span.Name() != w.name {
+			t.Errorf("error span name: got %q, want %q", span.Name(), w.name)
+		}
+
+		if span.SpanKind() != trace.SpanKindClient {
+			t.Errorf("error span %q kind: %s", span.Name(), span.SpanKind())
+		}
+
+		// Check for parent span.
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("error span %q is not a child of the parent span", span.Name())
+		}
+
+		var statement string
+
+		for _, attr := range span.Attributes() {
+			if attr.Key == semconv.DBStatementKey {
+				statement = attr.Value.AsString()
+			}
+		}
+
+		if statement != w.statement {
+			t.Errorf("error span statement: got %q, want %q", statement, w.statement)
+		}
+	}
+}
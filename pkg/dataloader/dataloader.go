@@ -0,0 +1,183 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Error returned for keys missing from the batch result.
+var ErrNotFound = errors.New("dataloader: key not found")
+
+// Batch function loading values of the keys. Keys missing from the returned
+// map are resolved with ErrNotFound.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loaded key result structure.
+type result[V any] struct {
+	value V
+	err   error
+	done  chan struct{}
+}
+
+// Pending batch structure.
+type batch[K comparable, V any] struct {
+	keys    []K
+	results []*result[V]
+	timer   *time.Timer
+}
+
+// Data loader structure.
+//
+// Loads requested within the wait window are collected into a single batch
+// call, loaded values are cached for the lifetime of the loader. A loader is
+// meant to be created per request.
+type Loader[K comparable, V any] struct {
+	fetch    BatchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	cache map[K]*result[V]
+	batch *batch[K, V]
+}
+
+// Creating a new data loader. A batch is dispatched after the wait window or
+// as soon as it has max batch keys, zero max batch means no limit.
+func NewLoader[K comparable, V any](fetch BatchFunc[K, V], wait time.Duration, maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		cache:    make(map[K]*result[V]),
+	}
+}
+
+// Loading a value by key.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+
+	// Check is key already loaded or pending.
+	r, ok := l.cache[key]
+	if !ok {
+		r = &result[V]{done: make(chan struct{})}
+		l.cache[key] = r
+
+		l.enqueue(ctx, key, r)
+	}
+
+	l.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// Loading values by keys, results are in the order of keys.
+func (l *Loader[K, V]) LoadMany(ctx context.Context, keys []K) ([]V, []error) {
+	var wg sync.WaitGroup
+
+	values, errs := make([]V, len(keys)), make([]error, len(keys))
+
+	for i, key := range keys {
+		wg.Add(1)
+
+		go func(i int, key K) {
+			defer wg.Done()
+			values[i], errs[i] = l.Load(ctx, key)
+		}(i, key)
+	}
+
+	wg.Wait()
+
+	return values, errs
+}
+
+// Adding a key to the pending batch. Must be called with the lock held.
+func (l *Loader[K, V]) enqueue(ctx context.Context, key K, r *result[V]) {
+	// Creating a new pending batch.
+	if l.batch == nil {
+		b := &batch[K, V]{}
+		b.timer = time.AfterFunc(l.wait, func() {
+			l.mu.Lock()
+
+			// Check is batch already dispatched.
+			if l.batch != b {
+				l.mu.Unlock()
+				return
+			}
+
+			l.batch = nil
+			l.mu.Unlock()
+
+			l.dispatch(ctx, b)
+		})
+
+		l.batch = b
+	}
+
+	l.batch.keys = append(l.batch.keys, key)
+	l.batch.results = append(l.batch.results, r)
+
+	// Dispatching a full batch.
+	if l.maxBatch > 0 && len(l.batch.keys) >= l.maxBatch {
+		b := l.batch
+		l.batch = nil
+
+		b.timer.Stop()
+
+		go l.dispatch(ctx, b)
+	}
+}
+
+// Dispatching a batch and resolving its results.
+func (l *Loader[K, V]) dispatch(ctx context.Context, b *batch[K, V]) {
+	values, err := l.fetch(ctx, b.keys)
+
+	for i, key := range b.keys {
+		r := b.results[i]
+
+		if err != nil {
+			r.err = err
+		} else if value, ok := values[key]; ok {
+			r.value = value
+		} else {
+			r.err = ErrNotFound
+		}
+
+		close(r.done)
+	}
+
+	// Failed loads are not cached.
+	if err != nil {
+		l.mu.Lock()
+		for i, key := range b.keys {
+			if l.cache[key] == b.results[i] {
+				delete(l.cache, key)
+			}
+		}
+		l.mu.Unlock()
+	}
+}
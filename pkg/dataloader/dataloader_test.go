@@ -0,0 +1,132 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package dataloader_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/durudex/durudex-post-service/pkg/dataloader"
+)
+
+// Batch fetcher recording every batch call.
+type fetcher struct {
+	mu      sync.Mutex
+	batches [][]int
+	err     error
+}
+
+// Fetching values of the keys, key zero is missing.
+func (f *fetcher) fetch(ctx context.Context, keys []int) (map[int]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	batch := append([]int(nil), keys...)
+	sort.Ints(batch)
+	f.batches = append(f.batches, batch)
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	values := make(map[int]string, len(keys))
+
+	for _, key := range keys {
+		if key != 0 {
+			values[key] = string(rune('a' + key))
+		}
+	}
+
+	return values, nil
+}
+
+// Testing loading values in a single batch.
+func TestLoader_LoadMany(t *testing.T) {
+	f := &fetcher{}
+
+	// Creating a new data loader.
+	l := dataloader.NewLoader(f.fetch, 10*time.Millisecond, 0)
+
+	// Loading values.
+	values, errs := l.LoadMany(context.Background(), []int{1, 2, 3, 2, 0})
+
+	// Check for loaded values.
+	for i, want := range []string{"b", "c", "d", "c", ""} {
+		if values[i] != want {
+			t.Errorf("error value %d: got %q, want %q", i, values[i], want)
+		}
+	}
+
+	// Check for missing key error.
+	if !errors.Is(errs[4], dataloader.ErrNotFound) {
+		t.Errorf("error missing key: %v", errs[4])
+	}
+
+	// Check for a single batch with unique keys.
+	if len(f.batches) != 1 || len(f.batches[0]) != 4 {
+		t.Errorf("error batches: %v", f.batches)
+	}
+
+	// Loading a cached value.
+	if value, err := l.Load(context.Background(), 1); err != nil || value != "b" {
+		t.Errorf("error loading cached value: %q, %v", value, err)
+	}
+
+	if len(f.batches) != 1 {
+		t.Errorf("error cached value was fetched: %v", f.batches)
+	}
+}
+
+// Testing splitting loads by max batch size.
+func TestLoader_MaxBatch(t *testing.T) {
+	f := &fetcher{}
+
+	// Creating a new data loader.
+	l := dataloader.NewLoader(f.fetch, time.Second, 2)
+
+	// Loading values.
+	start := time.Now()
+	l.LoadMany(context.Background(), []int{1, 2, 3, 4})
+
+	// Check for full batches dispatched without waiting.
+	if len(f.batches) != 2 || time.Since(start) >= time.Second {
+		t.Errorf("error batches: %v", f.batches)
+	}
+}
+
+// Testing failed loads are not cached.
+func TestLoader_Error(t *testing.T) {
+	f := &fetcher{err: errors.New("fetch error")}
+
+	// Creating a new data loader.
+	l := dataloader.NewLoader(f.fetch, time.Millisecond, 0)
+
+	if _, err := l.Load(context.Background(), 1); err == nil || err.Error() != "fetch error" {
+		t.Errorf("error loading value: %v", err)
+	}
+
+	f.err = nil
+
+	if value, err := l.Load(context.Background(), 1); err != nil || value != "b" {
+		t.Errorf("error reloading value: %q, %v", value, err)
+	}
+}
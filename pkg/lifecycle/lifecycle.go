@@ -0,0 +1,136 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package lifecycle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Stop hook structure.
+type Hook struct {
+	Name string
+	Stop func(ctx context.Context) error
+}
+
+// Lifecycle manager structure.
+type Manager struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	stages  [][]Hook
+	timeout time.Duration
+}
+
+// Creating a new lifecycle manager. Every stop stage gets its own shutdown
+// timeout.
+func NewManager(timeout time.Duration) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Manager{ctx: ctx, cancel: cancel, timeout: timeout}
+}
+
+// Running a background worker until workers are stopped.
+func (m *Manager) Go(run func(ctx context.Context)) {
+	m.wg.Add(1)
+
+	go func() {
+		defer m.wg.Done()
+		run(m.ctx)
+	}()
+}
+
+// Stopping background workers and waiting for them to return.
+func (m *Manager) StopWorkers(ctx context.Context) error {
+	m.cancel()
+
+	done := make(chan struct{})
+
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Adding a stop hook. Hooks are called in the order they were added.
+func (m *Manager) OnStop(name string, stop func(ctx context.Context) error) {
+	m.OnStopConcurrent(Hook{Name: name, Stop: stop})
+}
+
+// Adding stop hooks called concurrently, such as servers draining at the same
+// time. The next hooks are called after all of them return.
+func (m *Manager) OnStopConcurrent(hooks ...Hook) {
+	m.stages = append(m.stages, hooks)
+}
+
+// Shutting down by calling all stop hooks. Every stage is called under its own
+// shutdown deadline, so a slow stage, such as draining servers, does not leave
+// the next stages with an expired context. A failed hook does not prevent the
+// next hooks from being called. Returns the first hook error in the order hooks
+// were added.
+func (m *Manager) Shutdown() error {
+	var err error
+
+	for _, stage := range m.stages {
+		for _, e := range m.stop(stage) {
+			if e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+
+	return err
+}
+
+// Calling stop hooks of the stage concurrently under the stage deadline.
+func (m *Manager) stop(stage []Hook) []error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	errs := make([]error, len(stage))
+
+	var wg sync.WaitGroup
+
+	for i, h := range stage {
+		wg.Add(1)
+
+		go func(i int, h Hook) {
+			defer wg.Done()
+
+			log.Info().Msgf("Stopping %s...", h.Name)
+
+			if errs[i] = h.Stop(ctx); errs[i] != nil {
+				log.Error().Err(errs[i]).Msgf("error stopping %s", h.Name)
+			}
+		}(i, h)
+	}
+
+	wg.Wait()
+
+	return errs
+}
@@ -0,0 +1,176 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/durudex/durudex-post-service/pkg/lifecycle"
+)
+
+// Testing shutting down by calling all stop hooks.
+func TestManager_Shutdown(t *testing.T) {
+	var got []string
+
+	// Creating a new lifecycle manager.
+	m := lifecycle.NewManager(time.Second)
+
+	// Running a background worker.
+	m.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		got = append(got, "worker")
+	})
+
+	m.OnStop("server", func(context.Context) error {
+		got = append(got, "server")
+		return errors.New("server error")
+	})
+	m.OnStop("workers", m.StopWorkers)
+	m.OnStop("database", func(context.Context) error {
+		got = append(got, "database")
+		return nil
+	})
+
+	// Shutting down.
+	if err := m.Shutdown(); err == nil || err.Error() != "server error" {
+		t.Errorf("error shutting down: %v", err)
+	}
+
+	// Check for order of stop hooks.
+	if want := []string{"server", "worker", "database"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("error stop order: got %v, want %v", got, want)
+	}
+}
+
+// Testing stopping background workers after the timeout.
+func TestManager_StopWorkers(t *testing.T) {
+	// Creating a new lifecycle manager.
+	m := lifecycle.NewManager(time.Millisecond)
+
+	block := make(chan struct{})
+	defer close(block)
+
+	// Running a background worker that ignores the context.
+	m.Go(func(context.Context) { <-block })
+
+	m.OnStop("workers", m.StopWorkers)
+
+	// Shutting down.
+	if err := m.Shutdown(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error shutting down: %v", err)
+	}
+}
+
+// Testing stop hooks after a stop hook exceeding the shutdown deadline.
+func TestManager_ShutdownDeadline(t *testing.T) {
+	var got []error
+
+	// Creating a new lifecycle manager.
+	m := lifecycle.NewManager(50 * time.Millisecond)
+
+	// Draining servers until the deadline is exceeded.
+	m.OnStop("server", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	for _, name := range []string{"workers", "tracer"} {
+		m.OnStop(name, func(ctx context.Context) error {
+			got = append(got, ctx.Err())
+			return nil
+		})
+	}
+
+	start := time.Now()
+
+	// Shutting down.
+	if err := m.Shutdown(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error shutting down: %v", err)
+	}
+
+	// Check for the next stop hooks context.
+	if want := []error{nil, nil}; !reflect.DeepEqual(got, want) {
+		t.Errorf("error next stop hooks context: got %v, want %v", got, want)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("error shutdown took %s", elapsed)
+	}
+}
+
+// Testing calling concurrent stop hooks before the next stop hooks.
+func TestManager_ShutdownConcurrent(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		got []string
+	)
+
+	// Creating a new lifecycle manager.
+	m := lifecycle.NewManager(time.Second)
+
+	var starting sync.WaitGroup
+	starting.Add(2)
+
+	started := make(chan struct{})
+
+	go func() {
+		starting.Wait()
+		close(started)
+	}()
+
+	// Every server waits for the other one to start stopping.
+	server := func(name string) lifecycle.Hook {
+		return lifecycle.Hook{Name: name, Stop: func(ctx context.Context) error {
+			starting.Done()
+
+			select {
+			case <-started:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			got = append(got, name)
+
+			return nil
+		}}
+	}
+
+	m.OnStopConcurrent(server("HTTP server"), server("gRPC server"))
+	m.OnStop("database", func(context.Context) error {
+		got = append(got, "database")
+		return nil
+	})
+
+	// Shutting down.
+	if err := m.Shutdown(); err != nil {
+		t.Errorf("error shutting down: %v", err)
+	}
+
+	// Check for order of stop hooks.
+	if len(got) != 3 || got[2] != "database" {
+		t.Errorf("error stop order: %v", got)
+	}
+}
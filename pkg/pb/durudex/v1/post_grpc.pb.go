@@ -1,4 +1,8 @@
 // Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: durudex/v1/post.proto
 
 package durudexv1
 
@@ -30,6 +34,10 @@ type PostServiceClient interface {
 	UpdatePost(ctx context.Context, in *UpdatePostRequest, opts ...grpc.CallOption) (*UpdatePostResponse, error)
 	// Getting total posts count.
 	GetTotalPostsCount(ctx context.Context, in *GetTotalPostsCountRequest, opts ...grpc.CallOption) (*GetTotalPostsCountResponse, error)
+	// Finding similar posts.
+	FindSimilarPosts(ctx context.Context, in *FindSimilarPostsRequest, opts ...grpc.CallOption) (*FindSimilarPostsResponse, error)
+	// Watching author post events.
+	WatchPosts(ctx context.Context, in *WatchPostsRequest, opts ...grpc.CallOption) (PostService_WatchPostsClient, error)
 }
 
 type postServiceClient struct {
@@ -94,6 +102,47 @@ func (c *postServiceClient) GetTotalPostsCount(ctx context.Context, in *GetTotal
 	return out, nil
 }
 
+func (c *postServiceClient) FindSimilarPosts(ctx context.Context, in *FindSimilarPostsRequest, opts ...grpc.CallOption) (*FindSimilarPostsResponse, error) {
+	out := new(FindSimilarPostsResponse)
+	err := c.cc.Invoke(ctx, "/durudex.v1.PostService/FindSimilarPosts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postServiceClient) WatchPosts(ctx context.Context, in *WatchPostsRequest, opts ...grpc.CallOption) (PostService_WatchPostsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PostService_ServiceDesc.Streams[0], "/durudex.v1.PostService/WatchPosts", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &postServiceWatchPostsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PostService_WatchPostsClient interface {
+	Recv() (*WatchPostsResponse, error)
+	grpc.ClientStream
+}
+
+type postServiceWatchPostsClient struct {
+	grpc.ClientStream
+}
+
+func (x *postServiceWatchPostsClient) Recv() (*WatchPostsResponse, error) {
+	m := new(WatchPostsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // PostServiceServer is the server API for PostService service.
 // All implementations must embed UnimplementedPostServiceServer
 // for forward compatibility
@@ -110,6 +159,10 @@ type PostServiceServer interface {
 	UpdatePost(context.Context, *UpdatePostRequest) (*UpdatePostResponse, error)
 	// Getting total posts count.
 	GetTotalPostsCount(context.Context, *GetTotalPostsCountRequest) (*GetTotalPostsCountResponse, error)
+	// Finding similar posts.
+	FindSimilarPosts(context.Context, *FindSimilarPostsRequest) (*FindSimilarPostsResponse, error)
+	// Watching author post events.
+	WatchPosts(*WatchPostsRequest, PostService_WatchPostsServer) error
 	mustEmbedUnimplementedPostServiceServer()
 }
 
@@ -135,6 +188,12 @@ func (UnimplementedPostServiceServer) UpdatePost(context.Context, *UpdatePostReq
 func (UnimplementedPostServiceServer) GetTotalPostsCount(context.Context, *GetTotalPostsCountRequest) (*GetTotalPostsCountResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetTotalPostsCount not implemented")
 }
+func (UnimplementedPostServiceServer) FindSimilarPosts(context.Context, *FindSimilarPostsRequest) (*FindSimilarPostsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindSimilarPosts not implemented")
+}
+func (UnimplementedPostServiceServer) WatchPosts(*WatchPostsRequest, PostService_WatchPostsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchPosts not implemented")
+}
 func (UnimplementedPostServiceServer) mustEmbedUnimplementedPostServiceServer() {}
 
 // UnsafePostServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -256,6 +315,45 @@ func _PostService_GetTotalPostsCount_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PostService_FindSimilarPosts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindSimilarPostsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostServiceServer).FindSimilarPosts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/durudex.v1.PostService/FindSimilarPosts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostServiceServer).FindSimilarPosts(ctx, req.(*FindSimilarPostsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PostService_WatchPosts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchPostsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PostServiceServer).WatchPosts(m, &postServiceWatchPostsServer{stream})
+}
+
+type PostService_WatchPostsServer interface {
+	Send(*WatchPostsResponse) error
+	grpc.ServerStream
+}
+
+type postServiceWatchPostsServer struct {
+	grpc.ServerStream
+}
+
+func (x *postServiceWatchPostsServer) Send(m *WatchPostsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // PostService_ServiceDesc is the grpc.ServiceDesc for PostService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -287,7 +385,17 @@ var PostService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetTotalPostsCount",
 			Handler:    _PostService_GetTotalPostsCount_Handler,
 		},
+		{
+			MethodName: "FindSimilarPosts",
+			Handler:    _PostService_FindSimilarPosts_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchPosts",
+			Handler:       _PostService_WatchPosts_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "durudex/v1/post.proto",
 }
@@ -0,0 +1,73 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package requestid
+
+import (
+	"context"
+
+	"github.com/segmentio/ksuid"
+)
+
+// Request id metadata key.
+const Key = "x-request-id"
+
+// Max length of an incoming request id.
+const maxLength = 128
+
+// Request id context key.
+type contextKey struct{}
+
+// Creating a new request id.
+func New() string {
+	return ksuid.New().String()
+}
+
+// Getting a valid incoming request id or creating a new one.
+func FromIncoming(id string) string {
+	if !valid(id) {
+		return New()
+	}
+
+	return id
+}
+
+// Adding request id to the context.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// Getting request id from the context.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok
+}
+
+// Check is request id not empty, not too long and printable.
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package requestid_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/pkg/requestid"
+)
+
+// Testing getting incoming request id.
+func TestFromIncoming(t *testing.T) {
+	// Tests structures.
+	tests := []struct {
+		name string
+		id   string
+		keep bool
+	}{
+		{name: "OK", id: "7b0a6c7e-2f1d-4c1f-9a3e-0f2e4d8c1b5a", keep: true},
+		{name: "Empty", id: ""},
+		{name: "Too Long", id: strings.Repeat("a", 129)},
+		{name: "Control Characters", id: "id\nlevel=error"},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := requestid.FromIncoming(tt.id)
+
+			// Check for kept or generated request id.
+			if (got == tt.id) != tt.keep || got == "" {
+				t.Errorf("error getting incoming request id: %q", got)
+			}
+		})
+	}
+}
+
+// Testing request id context.
+func TestNewContext(t *testing.T) {
+	if _, ok := requestid.FromContext(context.Background()); ok {
+		t.Error("error request id in empty context")
+	}
+
+	ctx := requestid.NewContext(context.Background(), "id")
+
+	if id, ok := requestid.FromContext(ctx); !ok || id != "id" {
+		t.Errorf("error getting request id: %q", id)
+	}
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package simhash
+
+import (
+	"hash/fnv"
+	"math/bits"
+)
+
+// Computing a 64-bit SimHash fingerprint of the features.
+func Sum(features []string) uint64 {
+	var weights [64]int
+
+	for _, feature := range features {
+		// Hashing a feature.
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+
+		// Voting for each bit of the fingerprint.
+		for i := 0; i < 64; i++ {
+			if sum&(1<<i) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+
+	var fingerprint uint64
+
+	for i, weight := range weights {
+		if weight > 0 {
+			fingerprint |= 1 << i
+		}
+	}
+
+	return fingerprint
+}
+
+// Getting the number of different bits between two fingerprints.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
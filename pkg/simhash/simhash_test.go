@@ -0,0 +1,76 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package simhash_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/durudex/durudex-post-service/pkg/simhash"
+)
+
+// Testing computing a SimHash fingerprint.
+func TestSum(t *testing.T) {
+	// Testing args.
+	type args struct{ a, b string }
+
+	// Tests structures.
+	tests := []struct {
+		name    string
+		args    args
+		maxDist int
+	}{
+		{
+			name:    "Equal",
+			args:    args{a: "buy cheap watches online", b: "buy cheap watches online"},
+			maxDist: 0,
+		},
+		{
+			name: "Similar",
+			args: args{
+				a: "buy cheap watches online today with the best price guaranteed and free shipping",
+				b: "buy cheap watches online today with the best price guaranteed and free shipping!!",
+			},
+			maxDist: 3,
+		},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Getting distance between fingerprints.
+			dist := simhash.Distance(simhash.Sum(shingles(tt.args.a)), simhash.Sum(shingles(tt.args.b)))
+			if dist > tt.maxDist {
+				t.Errorf("error distance %d is greater than %d", dist, tt.maxDist)
+			}
+		})
+	}
+}
+
+// Splitting text to character shingles.
+func shingles(text string) []string {
+	text = strings.ToLower(text)
+
+	features := make([]string, 0, len(text))
+
+	for i := 0; i+4 <= len(text); i++ {
+		features = append(features, text[i:i+4])
+	}
+
+	return features
+}
@@ -26,6 +26,22 @@ import (
 
 // Loading TLS credentials config.
 func LoadTLSConfig(caCertPath, certPath, keyPath string) (*tls.Config, error) {
+	c, err := loadCertificates(caCertPath, certPath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.config(), nil
+}
+
+// Loaded TLS certificates structure.
+type certificates struct {
+	cert tls.Certificate
+	pool *x509.CertPool
+}
+
+// Loading server certificate and client CA certificates.
+func loadCertificates(caCertPath, certPath, keyPath string) (*certificates, error) {
 	// Load certificate on the CA who signed client's certificate.
 	pemCA, err := os.ReadFile(caCertPath)
 	if err != nil {
@@ -44,9 +60,20 @@ func LoadTLSConfig(caCertPath, certPath, keyPath string) (*tls.Config, error) {
 		return nil, err
 	}
 
+	// Parsing server's leaf certificate.
+	serverCert.Leaf, err = x509.ParseCertificate(serverCert.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &certificates{cert: serverCert, pool: certPool}, nil
+}
+
+// Getting TLS config requiring verified client certificates.
+func (c *certificates) config() *tls.Config {
 	return &tls.Config{
-		Certificates: []tls.Certificate{serverCert},
+		Certificates: []tls.Certificate{c.cert},
 		ClientAuth:   tls.RequireAndVerifyClientCert,
-		ClientCAs:    certPool,
-	}, nil
+		ClientCAs:    c.pool,
+	}
 }
@@ -0,0 +1,196 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package tls
+
+import (
+	"context"
+	"crypto/tls"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// Delay of reloading after the last file change, certificate files are
+	// usually replaced with several writes.
+	reloadDelay = 500 * time.Millisecond
+	// Interval of checking certificate expiry.
+	expiryCheckInterval = time.Hour
+)
+
+// TLS certificates reloader structure.
+//
+// Certificates are swapped atomically, handshakes in progress keep using the
+// certificates they started with.
+type Reloader struct {
+	caCertPath, certPath, keyPath string
+	expiryWarning                 time.Duration
+	current                       atomic.Value
+}
+
+// Creating a new TLS certificates reloader. Expiry warnings are logged when
+// the certificate expires within the expiry warning duration.
+func NewReloader(caCertPath, certPath, keyPath string, expiryWarning time.Duration) (*Reloader, error) {
+	r := &Reloader{
+		caCertPath:    caCertPath,
+		certPath:      certPath,
+		keyPath:       keyPath,
+		expiryWarning: expiryWarning,
+	}
+
+	// Loading initial certificates.
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Reloading certificates. The previous certificates are kept when loading
+// fails.
+func (r *Reloader) Reload() error {
+	c, err := loadCertificates(r.caCertPath, r.certPath, r.keyPath)
+	if err != nil {
+		return err
+	}
+
+	r.current.Store(c)
+
+	return nil
+}
+
+// Getting TLS config requiring verified client certificates. Every handshake
+// uses the current certificates.
+//
+// Fields set on the returned config, such as NextProtos, are copied to the
+// config of every handshake.
+func (r *Reloader) Config() *tls.Config {
+	base := &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert}
+
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		c := r.certificates()
+
+		config := base.Clone()
+		config.GetConfigForClient = nil
+		config.Certificates = []tls.Certificate{c.cert}
+		config.ClientCAs = c.pool
+
+		return config, nil
+	}
+
+	return base
+}
+
+// Getting current server certificate expiry time.
+func (r *Reloader) NotAfter() time.Time {
+	return r.certificates().cert.Leaf.NotAfter
+}
+
+// Getting current certificates.
+func (r *Reloader) certificates() *certificates {
+	return r.current.Load().(*certificates)
+}
+
+// Watching certificate files and reloading certificates on changes until
+// the context is done.
+//
+// Directories of the files are watched, so that files replaced by renaming,
+// as with mounted secrets, are reloaded too.
+func (r *Reloader) Watch(ctx context.Context) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Error().Err(err).Msg("error creating TLS certificates watcher")
+		return
+	}
+	defer watcher.Close()
+
+	// Watching certificate directories.
+	for dir := range r.dirs() {
+		if err := watcher.Add(dir); err != nil {
+			log.Error().Err(err).Msgf("error watching TLS certificates directory %s", dir)
+			return
+		}
+	}
+
+	reload := time.NewTimer(reloadDelay)
+	reload.Stop()
+	defer reload.Stop()
+
+	expiry := time.NewTicker(expiryCheckInterval)
+	defer expiry.Stop()
+
+	r.checkExpiry()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			// Skipping file mode changes.
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			reload.Reset(reloadDelay)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+
+			log.Error().Err(err).Msg("error watching TLS certificates")
+		case <-reload.C:
+			if err := r.Reload(); err != nil {
+				log.Error().Err(err).Msg("error reloading TLS certificates, keeping previous certificates")
+				continue
+			}
+
+			log.Info().Time("not_after", r.NotAfter()).Msg("TLS certificates reloaded")
+
+			r.checkExpiry()
+		case <-expiry.C:
+			r.checkExpiry()
+		}
+	}
+}
+
+// Logging a warning when the certificate is about to expire.
+func (r *Reloader) checkExpiry() {
+	notAfter := r.NotAfter()
+
+	if left := time.Until(notAfter); left < r.expiryWarning {
+		log.Warn().Time("not_after", notAfter).Dur("left", left).Msg("TLS certificate is about to expire")
+	}
+}
+
+// Getting directories of the certificate files.
+func (r *Reloader) dirs() map[string]struct{} {
+	dirs := make(map[string]struct{}, 3)
+
+	for _, path := range []string{r.caCertPath, r.certPath, r.keyPath} {
+		dirs[filepath.Dir(path)] = struct{}{}
+	}
+
+	return dirs
+}
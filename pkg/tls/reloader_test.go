@@ -0,0 +1,181 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package tls_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	ctls "crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/durudex/durudex-post-service/pkg/tls"
+)
+
+// Writing a self-signed certificate and key expiring at the time.
+func writeCert(t *testing.T, dir string, notAfter time.Time) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %s", err.Error())
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "post.service.durudex.local"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("error creating certificate: %s", err.Error())
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("error encoding key: %s", err.Error())
+	}
+
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	for name, data := range map[string][]byte{
+		"ca.pem":   cert,
+		"cert.pem": cert,
+		"key.pem":  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}),
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatalf("error writing file: %s", err.Error())
+		}
+	}
+}
+
+// Creating a new reloader of certificates in the directory.
+func newReloader(t *testing.T, dir string) *tls.Reloader {
+	t.Helper()
+
+	r, err := tls.NewReloader(filepath.Join(dir, "ca.pem"), filepath.Join(dir, "cert.pem"),
+		filepath.Join(dir, "key.pem"), time.Hour)
+	if err != nil {
+		t.Fatalf("error creating reloader: %s", err.Error())
+	}
+
+	return r
+}
+
+// Testing reloading certificates.
+func TestReloader_Reload(t *testing.T) {
+	dir := t.TempDir()
+	first := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+
+	writeCert(t, dir, first)
+
+	// Creating a new reloader.
+	r := newReloader(t, dir)
+
+	// Check for handshake certificate.
+	config, err := r.Config().GetConfigForClient(&ctls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatalf("error getting config: %s", err.Error())
+	}
+
+	if len(config.Certificates) != 1 || config.ClientCAs == nil || config.ClientAuth != ctls.RequireAndVerifyClientCert {
+		t.Fatal("error handshake config without certificates")
+	}
+
+	second := first.Add(24 * time.Hour)
+
+	writeCert(t, dir, second)
+
+	// Reloading certificates.
+	if err := r.Reload(); err != nil {
+		t.Fatalf("error reloading certificates: %s", err.Error())
+	}
+
+	if !r.NotAfter().Equal(second) {
+		t.Errorf("error reloaded expiry: got %s, want %s", r.NotAfter(), second)
+	}
+
+	// Writing an invalid certificate.
+	if err := os.WriteFile(filepath.Join(dir, "cert.pem"), []byte("invalid"), 0o600); err != nil {
+		t.Fatalf("error writing file: %s", err.Error())
+	}
+
+	if err := r.Reload(); err == nil {
+		t.Error("error reloading invalid certificate")
+	}
+
+	// Check previous certificate is kept.
+	if !r.NotAfter().Equal(second) {
+		t.Errorf("error kept expiry: got %s, want %s", r.NotAfter(), second)
+	}
+}
+
+// Testing reloading certificates on file changes.
+func TestReloader_Watch(t *testing.T) {
+	dir := t.TempDir()
+	first := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+
+	writeCert(t, dir, first)
+
+	// Creating a new reloader.
+	r := newReloader(t, dir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+
+	// Watching certificate files.
+	go func() {
+		r.Watch(ctx)
+		close(done)
+	}()
+
+	// Waiting for the watcher to start.
+	time.Sleep(100 * time.Millisecond)
+
+	second := first.Add(24 * time.Hour)
+
+	writeCert(t, dir, second)
+
+	// Waiting for certificates reloading.
+	deadline := time.Now().Add(5 * time.Second)
+
+	for !r.NotAfter().Equal(second) {
+		if time.Now().After(deadline) {
+			t.Fatal("error certificates are not reloaded")
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	cancel()
+	<-done
+}
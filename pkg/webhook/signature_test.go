@@ -0,0 +1,55 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package webhook_test
+
+import (
+	"testing"
+
+	"github.com/durudex/durudex-post-service/pkg/webhook"
+)
+
+// Testing signing and verifying a webhook request body.
+func TestSignature(t *testing.T) {
+	body := []byte(`{"id":1,"type":"post.created"}`)
+
+	signature := webhook.Sign("secret", 1650000000, body)
+
+	// Tests structures.
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+		want      bool
+	}{
+		{name: "OK", secret: "secret", timestamp: 1650000000, body: body, want: true},
+		{name: "Wrong Secret", secret: "other", timestamp: 1650000000, body: body},
+		{name: "Wrong Timestamp", secret: "secret", timestamp: 1650000001, body: body},
+		{name: "Wrong Body", secret: "secret", timestamp: 1650000000, body: []byte(`{}`)},
+	}
+
+	// Conducting tests in various structures.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Verifying a webhook request signature.
+			if got := webhook.Verify(tt.secret, signature, tt.timestamp, tt.body); got != tt.want {
+				t.Errorf("error verifying signature: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+ALTER TABLE "post"
+  ADD COLUMN IF NOT EXISTS "hash"    BYTEA,
+  ADD COLUMN IF NOT EXISTS "simhash" BIGINT,
+  ADD COLUMN IF NOT EXISTS "flagged" BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS "post_author_id_created_at_idx" ON "post" ("author_id", "created_at");
+CREATE INDEX IF NOT EXISTS "post_created_at_idx" ON "post" ("created_at");
+
+-- Posts created before the fingerprint columns are fingerprinted by the service in batches.
+CREATE INDEX IF NOT EXISTS "post_hash_null_idx" ON "post" ("created_at") WHERE "hash" IS NULL;
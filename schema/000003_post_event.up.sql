@@ -0,0 +1,53 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+CREATE TABLE IF NOT EXISTS "post_event" (
+  "id"         BIGSERIAL NOT NULL PRIMARY KEY,
+  "type"       TEXT      NOT NULL,
+  "post_id"    CHAR(27)  NOT NULL,
+  "author_id"  CHAR(27)  NOT NULL,
+  "text"       TEXT,
+  "created_at" TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS "post_event_author_id_id_idx" ON "post_event" ("author_id", "id");
+CREATE INDEX IF NOT EXISTS "post_event_created_at_idx" ON "post_event" ("created_at");
+
+-- Record every post change and notify all service replicas.
+CREATE OR REPLACE FUNCTION "post_event_notify"() RETURNS TRIGGER AS $$
+DECLARE
+  "event_id" BIGINT;
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    INSERT INTO "post_event" ("type", "post_id", "author_id")
+      VALUES ('post.deleted', OLD."id", OLD."author_id")
+      RETURNING "id" INTO "event_id";
+  ELSE
+    INSERT INTO "post_event" ("type", "post_id", "author_id", "text")
+      VALUES (CASE TG_OP WHEN 'INSERT' THEN 'post.created' ELSE 'post.updated' END,
+        NEW."id", NEW."author_id", NEW."text")
+      RETURNING "id" INTO "event_id";
+  END IF;
+
+  PERFORM pg_notify('post_event', "event_id"::TEXT);
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "post_event_trigger" AFTER INSERT OR UPDATE OR DELETE ON "post"
+  FOR EACH ROW EXECUTE FUNCTION "post_event_notify"();
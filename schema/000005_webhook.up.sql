@@ -0,0 +1,45 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+CREATE TABLE IF NOT EXISTS "webhook" (
+  "id"         CHAR(27)  NOT NULL PRIMARY KEY,
+  "url"        TEXT      NOT NULL,
+  "events"     TEXT[]    NOT NULL DEFAULT '{}',
+  "secret"     TEXT      NOT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS "webhook_delivery" (
+  "id"              CHAR(27)  NOT NULL PRIMARY KEY,
+  "webhook_id"      CHAR(27)  NOT NULL REFERENCES "webhook" ("id") ON DELETE CASCADE,
+  "event_id"        BIGINT    NOT NULL,
+  "event_type"      TEXT      NOT NULL,
+  "payload"         JSONB     NOT NULL,
+  "status"          TEXT      NOT NULL DEFAULT 'pending',
+  "attempts"        INT       NOT NULL DEFAULT 0,
+  "response_code"   INT       NOT NULL DEFAULT 0,
+  "error"           TEXT      NOT NULL DEFAULT '',
+  "next_attempt_at" TIMESTAMP NOT NULL DEFAULT now(),
+  "created_at"      TIMESTAMP NOT NULL DEFAULT now(),
+  "updated_at"      TIMESTAMP,
+  UNIQUE ("webhook_id", "event_id")
+);
+
+CREATE INDEX IF NOT EXISTS "webhook_delivery_pending_idx" ON "webhook_delivery" ("next_attempt_at")
+  WHERE "status" = 'pending';
+CREATE INDEX IF NOT EXISTS "webhook_delivery_webhook_id_created_at_idx"
+  ON "webhook_delivery" ("webhook_id", "created_at");
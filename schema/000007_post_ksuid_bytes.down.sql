@@ -0,0 +1,40 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+SET LOCAL timezone = 'UTC';
+
+ALTER TABLE "post"
+  ALTER COLUMN "created_at" TYPE TIMESTAMP,
+  ALTER COLUMN "updated_at" TYPE TIMESTAMP;
+
+DROP INDEX IF EXISTS "post_id_bytes_null_idx";
+
+DROP TRIGGER IF EXISTS "post_ksuid_bytes_trigger" ON "post";
+DROP FUNCTION IF EXISTS "post_ksuid_bytes"();
+
+ALTER TABLE "post"
+  DROP COLUMN IF EXISTS "id_bytes",
+  DROP COLUMN IF EXISTS "author_id_bytes";
+
+DROP TRIGGER IF EXISTS "post_event_trigger" ON "post";
+
+CREATE TRIGGER "post_event_trigger" AFTER INSERT OR UPDATE OR DELETE ON "post"
+  FOR EACH ROW EXECUTE FUNCTION "post_event_notify"();
+
+DROP FUNCTION IF EXISTS "ksuid_to_text"(BYTEA);
+DROP FUNCTION IF EXISTS "ksuid_to_bytea"(BYTEA);
+DROP FUNCTION IF EXISTS "ksuid_to_bytea"(TEXT);
@@ -0,0 +1,94 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+-- Convert between base62 ksuid strings and their 20 raw bytes.
+CREATE OR REPLACE FUNCTION "ksuid_to_bytea"("value" TEXT) RETURNS BYTEA AS $$
+DECLARE
+  "alphabet" CONSTANT TEXT := '0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz';
+  "n"        NUMERIC := 0;
+  "result"   BYTEA := decode(repeat('00', 20), 'hex');
+BEGIN
+  FOR "i" IN 1..length("value") LOOP
+    "n" := "n" * 62 + strpos("alphabet", substr("value", "i", 1)) - 1;
+  END LOOP;
+
+  FOR "i" IN REVERSE 19..0 LOOP
+    "result" := set_byte("result", "i", ("n" % 256)::INT);
+    "n" := div("n", 256);
+  END LOOP;
+
+  RETURN "result";
+END;
+$$ LANGUAGE plpgsql IMMUTABLE STRICT;
+
+CREATE OR REPLACE FUNCTION "ksuid_to_text"("value" BYTEA) RETURNS TEXT AS $$
+DECLARE
+  "alphabet" CONSTANT TEXT := '0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz';
+  "n"        NUMERIC := 0;
+  "result"   TEXT := '';
+BEGIN
+  FOR "i" IN 0..19 LOOP
+    "n" := "n" * 256 + get_byte("value", "i");
+  END LOOP;
+
+  FOR "i" IN 1..27 LOOP
+    "result" := substr("alphabet", ("n" % 62)::INT + 1, 1) || "result";
+    "n" := div("n", 62);
+  END LOOP;
+
+  RETURN "result";
+END;
+$$ LANGUAGE plpgsql IMMUTABLE STRICT;
+
+-- Binary ksuids are kept as is, so that queries converting ksuid columns work before and after the contract
+-- migration.
+CREATE OR REPLACE FUNCTION "ksuid_to_bytea"("value" BYTEA) RETURNS BYTEA AS $$
+  SELECT "value";
+$$ LANGUAGE sql IMMUTABLE STRICT;
+
+-- Backfill updates must not be recorded as post changes.
+DROP TRIGGER IF EXISTS "post_event_trigger" ON "post";
+
+CREATE TRIGGER "post_event_trigger"
+  AFTER INSERT OR UPDATE OF "text", "flagged", "updated_at" OR DELETE ON "post"
+  FOR EACH ROW EXECUTE FUNCTION "post_event_notify"();
+
+ALTER TABLE "post"
+  ADD COLUMN IF NOT EXISTS "id_bytes"        BYTEA,
+  ADD COLUMN IF NOT EXISTS "author_id_bytes" BYTEA;
+
+-- Keep binary ksuids of written posts while existing rows are backfilled.
+CREATE OR REPLACE FUNCTION "post_ksuid_bytes"() RETURNS TRIGGER AS $$
+BEGIN
+  NEW."id_bytes" := ksuid_to_bytea(NEW."id");
+  NEW."author_id_bytes" := ksuid_to_bytea(NEW."author_id");
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "post_ksuid_bytes_trigger" BEFORE INSERT OR UPDATE OF "id", "author_id" ON "post"
+  FOR EACH ROW EXECUTE FUNCTION "post_ksuid_bytes"();
+
+CREATE INDEX IF NOT EXISTS "post_id_bytes_null_idx" ON "post" ("id") WHERE "id_bytes" IS NULL;
+
+-- Stored times are UTC, changing the type in the UTC time zone does not rewrite the table.
+SET LOCAL timezone = 'UTC';
+
+ALTER TABLE "post"
+  ALTER COLUMN "created_at" TYPE TIMESTAMPTZ,
+  ALTER COLUMN "updated_at" TYPE TIMESTAMPTZ;
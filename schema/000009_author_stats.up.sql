@@ -0,0 +1,26 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+CREATE TABLE IF NOT EXISTS "author_stats" (
+  "author_id"   BYTEA       NOT NULL PRIMARY KEY CHECK (octet_length("author_id") = 20),
+  "posts_count" BIGINT      NOT NULL DEFAULT 0,
+  "updated_at"  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO "author_stats" ("author_id", "posts_count")
+  SELECT ksuid_to_bytea("author_id"), count(*) FROM "post" GROUP BY "author_id"
+  ON CONFLICT ("author_id") DO NOTHING;
@@ -0,0 +1,28 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+DROP TABLE IF EXISTS "post_event_retention";
+
+CREATE INDEX IF NOT EXISTS "post_event_author_id_id_idx" ON "post_event" ("author_id", "id");
+
+DROP INDEX IF EXISTS "post_event_position_null_idx";
+DROP INDEX IF EXISTS "post_event_author_id_position_idx";
+DROP INDEX IF EXISTS "post_event_position_idx";
+
+ALTER TABLE "post_event" DROP COLUMN IF EXISTS "position";
+
+DROP SEQUENCE IF EXISTS "post_event_position_seq";
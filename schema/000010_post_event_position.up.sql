@@ -0,0 +1,41 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+-- Event ids are taken when rows are inserted, so events may commit out of id order. Stream positions are
+-- taken by the events dispatcher for committed events only, so a reader that has seen a position has seen
+-- every earlier one, and post transactions never wait for each other to commit.
+CREATE SEQUENCE IF NOT EXISTS "post_event_position_seq";
+
+ALTER TABLE "post_event" ADD COLUMN IF NOT EXISTS "position" BIGINT;
+
+UPDATE "post_event" SET "position" = "id";
+
+SELECT setval('post_event_position_seq', COALESCE(max("position"), 0) + 1, false) FROM "post_event";
+
+CREATE UNIQUE INDEX IF NOT EXISTS "post_event_position_idx" ON "post_event" ("position");
+CREATE INDEX IF NOT EXISTS "post_event_author_id_position_idx" ON "post_event" ("author_id", "position");
+CREATE INDEX IF NOT EXISTS "post_event_position_null_idx" ON "post_event" ("id") WHERE "position" IS NULL;
+
+DROP INDEX IF EXISTS "post_event_author_id_id_idx";
+
+-- Position of the last deleted event, watchers behind it have missed events.
+CREATE TABLE IF NOT EXISTS "post_event_retention" (
+  "id"       BOOLEAN NOT NULL PRIMARY KEY DEFAULT true CHECK ("id"),
+  "position" BIGINT  NOT NULL DEFAULT 0
+);
+
+INSERT INTO "post_event_retention" DEFAULT VALUES ON CONFLICT DO NOTHING;
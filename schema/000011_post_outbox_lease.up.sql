@@ -0,0 +1,24 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+-- Outbox events are claimed with a lease and published outside the claiming transaction.
+ALTER TABLE "post_outbox" ADD COLUMN IF NOT EXISTS "locked_until" TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS "post_outbox_post_id_id_idx" ON "post_outbox" ("post_id", "id");
+
+-- Events of a post with a dead letter are parked until the dead letter is deleted.
+CREATE INDEX IF NOT EXISTS "post_outbox_dead_post_id_idx" ON "post_outbox_dead" ("post_id");
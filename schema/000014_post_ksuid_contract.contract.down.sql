@@ -0,0 +1,77 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+CREATE OR REPLACE FUNCTION "post_event_notify"() RETURNS TRIGGER AS $$
+DECLARE
+  "event_id" BIGINT;
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    INSERT INTO "post_event" ("type", "post_id", "author_id")
+      VALUES ('post.deleted', OLD."id", OLD."author_id")
+      RETURNING "id" INTO "event_id";
+  ELSE
+    INSERT INTO "post_event" ("type", "post_id", "author_id", "text")
+      VALUES (CASE TG_OP WHEN 'INSERT' THEN 'post.created' ELSE 'post.updated' END,
+        NEW."id", NEW."author_id", NEW."text")
+      RETURNING "id" INTO "event_id";
+  END IF;
+
+  PERFORM pg_notify('post_event', "event_id"::TEXT);
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER INDEX IF EXISTS "post_author_id_created_at_id_idx" RENAME TO "post_author_id_bytes_created_at_id_idx";
+
+ALTER TABLE "post"
+  DROP CONSTRAINT IF EXISTS "post_pkey",
+  DROP CONSTRAINT IF EXISTS "post_id_check",
+  DROP CONSTRAINT IF EXISTS "post_author_id_check";
+
+ALTER TABLE "post" RENAME COLUMN "id" TO "id_bytes";
+ALTER TABLE "post" RENAME COLUMN "author_id" TO "author_id_bytes";
+
+ALTER TABLE "post"
+  ALTER COLUMN "id_bytes" DROP NOT NULL,
+  ALTER COLUMN "author_id_bytes" DROP NOT NULL,
+  ADD COLUMN "id"        CHAR(27),
+  ADD COLUMN "author_id" CHAR(27);
+
+UPDATE "post" SET "id" = ksuid_to_text("id_bytes"), "author_id" = ksuid_to_text("author_id_bytes");
+
+ALTER TABLE "post"
+  ALTER COLUMN "id" SET NOT NULL,
+  ALTER COLUMN "author_id" SET NOT NULL,
+  ADD PRIMARY KEY ("id");
+
+CREATE UNIQUE INDEX IF NOT EXISTS "post_id_bytes_key" ON "post" ("id_bytes");
+CREATE INDEX IF NOT EXISTS "post_author_id_created_at_id_idx" ON "post" ("author_id", "created_at", "id");
+CREATE INDEX IF NOT EXISTS "post_id_bytes_null_idx" ON "post" ("id") WHERE "id_bytes" IS NULL;
+
+-- Keep binary ksuids of written posts while existing rows are backfilled.
+CREATE OR REPLACE FUNCTION "post_ksuid_bytes"() RETURNS TRIGGER AS $$
+BEGIN
+  NEW."id_bytes" := ksuid_to_bytea(NEW."id");
+  NEW."author_id_bytes" := ksuid_to_bytea(NEW."author_id");
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "post_ksuid_bytes_trigger" BEFORE INSERT OR UPDATE OF "id", "author_id" ON "post"
+  FOR EACH ROW EXECUTE FUNCTION "post_ksuid_bytes"();
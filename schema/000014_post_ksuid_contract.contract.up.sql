@@ -0,0 +1,67 @@
+/*
+ * Copyright © 2022 Durudex
+ *
+ * This file is part of Durudex: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * Durudex is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with Durudex. If not, see <https://www.gnu.org/licenses/>.
+ */
+
+DROP TRIGGER IF EXISTS "post_ksuid_bytes_trigger" ON "post";
+DROP FUNCTION IF EXISTS "post_ksuid_bytes"();
+
+-- Backfill posts written before the trigger was dropped.
+UPDATE "post" SET "id_bytes" = ksuid_to_bytea("id"), "author_id_bytes" = ksuid_to_bytea("author_id")
+  WHERE "id_bytes" IS NULL;
+
+DROP INDEX IF EXISTS "post_id_bytes_null_idx";
+DROP INDEX IF EXISTS "post_author_id_created_at_id_idx";
+
+ALTER TABLE "post"
+  DROP CONSTRAINT IF EXISTS "post_pkey",
+  DROP COLUMN "id",
+  DROP COLUMN "author_id";
+
+ALTER TABLE "post" RENAME COLUMN "id_bytes" TO "id";
+ALTER TABLE "post" RENAME COLUMN "author_id_bytes" TO "author_id";
+
+ALTER TABLE "post"
+  ALTER COLUMN "id" SET NOT NULL,
+  ALTER COLUMN "author_id" SET NOT NULL,
+  ADD CONSTRAINT "post_id_check" CHECK (octet_length("id") = 20),
+  ADD CONSTRAINT "post_author_id_check" CHECK (octet_length("author_id") = 20);
+
+-- Indexes of the binary ksuids are built concurrently by the previous migrations.
+ALTER TABLE "post" ADD CONSTRAINT "post_pkey" PRIMARY KEY USING INDEX "post_id_bytes_key";
+
+ALTER INDEX "post_author_id_bytes_created_at_id_idx" RENAME TO "post_author_id_created_at_id_idx";
+
+-- Post events keep string ksuids.
+CREATE OR REPLACE FUNCTION "post_event_notify"() RETURNS TRIGGER AS $$
+DECLARE
+  "event_id" BIGINT;
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    INSERT INTO "post_event" ("type", "post_id", "author_id")
+      VALUES ('post.deleted', ksuid_to_text(OLD."id"), ksuid_to_text(OLD."author_id"))
+      RETURNING "id" INTO "event_id";
+  ELSE
+    INSERT INTO "post_event" ("type", "post_id", "author_id", "text")
+      VALUES (CASE TG_OP WHEN 'INSERT' THEN 'post.created' ELSE 'post.updated' END,
+        ksuid_to_text(NEW."id"), ksuid_to_text(NEW."author_id"), NEW."text")
+      RETURNING "id" INTO "event_id";
+  END IF;
+
+  PERFORM pg_notify('post_event', "event_id"::TEXT);
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;